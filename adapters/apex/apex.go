@@ -11,6 +11,7 @@ import (
 
 	"github.com/apex/log"
 
+	"github.com/axiomhq/axiom-go/adapters/sampling"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 )
@@ -62,6 +63,15 @@ func SetIngestOptions(opts ...ingest.Option) Option {
 	}
 }
 
+// SetSampler specifies the sampler that decides which entries are ingested.
+// Entries are sampled by their level and message.
+func SetSampler(sampler *sampling.Sampler) Option {
+	return func(h *Handler) error {
+		h.sampler = sampler
+		return nil
+	}
+}
+
 // Handler implements a [log.Handler] used for shipping logs to Axiom.
 type Handler struct {
 	client      *axiom.Client
@@ -69,6 +79,7 @@ type Handler struct {
 
 	clientOptions []axiom.Option
 	ingestOptions []ingest.Option
+	sampler       *sampling.Sampler
 
 	eventCh   chan axiom.Event
 	stopCh    chan struct{}
@@ -161,6 +172,14 @@ func (h *Handler) Close() {
 
 // HandleLog implements [log.Handler].
 func (h *Handler) HandleLog(entry *log.Entry) error {
+	var dropped uint64
+	if h.sampler != nil {
+		var ok bool
+		if ok, dropped = h.sampler.Sample(entry.Level.String(), entry.Message); !ok {
+			return nil
+		}
+	}
+
 	event := axiom.Event{}
 
 	// Set fields first.
@@ -170,6 +189,9 @@ func (h *Handler) HandleLog(entry *log.Entry) error {
 	event[ingest.TimestampField] = entry.Timestamp.Format(time.RFC3339Nano)
 	event["severity"] = entry.Level.String()
 	event["message"] = entry.Message
+	if dropped > 0 {
+		event[sampling.DroppedField] = dropped
+	}
 
 	select {
 	case <-h.closeCh:
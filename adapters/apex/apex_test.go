@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/axiomhq/axiom-go/adapters/sampling"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 	"github.com/axiomhq/axiom-go/internal/test/adapters"
@@ -72,6 +73,58 @@ func TestHandler(t *testing.T) {
 	assert.EqualValues(t, 1, atomic.LoadUint64(&hasRun))
 }
 
+func TestHandler_Sampler(t *testing.T) {
+	exp := []string{
+		`{"_time":"time","severity":"info","message":"my message"}`,
+		`{"_time":"time","severity":"warn","message":"my other message","dropped_events":3}`,
+	}
+
+	var lines []string
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			lines = append(lines, s.Text())
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	sampler, err := sampling.New(sampling.SetFirstThereafter(time.Minute, 1, 0))
+	require.NoError(t, err)
+
+	logger, closeHandler := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*log.Logger, func()) {
+		t.Helper()
+
+		handler, err := New(
+			SetClient(client),
+			SetDataset(dataset),
+			SetSampler(sampler),
+		)
+		require.NoError(t, err)
+		t.Cleanup(handler.Close)
+
+		return &log.Logger{Handler: handler, Level: log.InfoLevel}, handler.Close
+	})
+
+	for range 4 {
+		logger.Info("my message")
+	}
+	logger.Warn("my other message")
+
+	closeHandler()
+
+	if assert.Len(t, lines, len(exp)) {
+		for i := range exp {
+			testhelper.JSONEqExp(t, exp[i], lines[i], []string{ingest.TimestampField})
+		}
+	}
+}
+
 func TestHandler_NoPanicAfterClose(t *testing.T) {
 	exp := fmt.Sprintf(`{"_time":"%s","severity":"info","key":"value","message":"my message"}`,
 		time.Now().Format(time.RFC3339Nano))
@@ -0,0 +1,3 @@
+// Package hclog provides an adapter for the github.com/hashicorp/go-hclog
+// logging library used throughout the HashiCorp ecosystem.
+package hclog
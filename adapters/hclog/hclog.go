@@ -0,0 +1,401 @@
+package hclog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/internal/ingester"
+)
+
+var (
+	_ hclog.Logger      = (*Logger)(nil)
+	_ hclog.SinkAdapter = (*Logger)(nil)
+)
+
+// ErrMissingDatasetName is raised when a dataset name is not provided. Set it
+// manually using the [SetDataset] option or export "AXIOM_DATASET".
+var ErrMissingDatasetName = errors.New("missing dataset name")
+
+// An Option modifies the behaviour of the Axiom logger.
+type Option func(*Logger) error
+
+// SetClient specifies the Axiom client to use for ingesting the logs.
+func SetClient(client *axiom.Client) Option {
+	return func(l *Logger) error {
+		l.client = client
+		return nil
+	}
+}
+
+// SetClientOptions specifies the Axiom client options to pass to
+// [axiom.NewClient] which is only called if no [axiom.Client] was specified by
+// the [SetClient] option.
+func SetClientOptions(options ...axiom.Option) Option {
+	return func(l *Logger) error {
+		l.clientOptions = options
+		return nil
+	}
+}
+
+// SetDataset specifies the dataset to ingest the logs into. Can also be
+// specified using the "AXIOM_DATASET" environment variable.
+func SetDataset(datasetName string) Option {
+	return func(l *Logger) error {
+		l.datasetName = datasetName
+		return nil
+	}
+}
+
+// SetIngestOptions specifies the ingestion options to use for ingesting the
+// logs.
+func SetIngestOptions(opts ...ingest.Option) Option {
+	return func(l *Logger) error {
+		l.ingestOptions = opts
+		return nil
+	}
+}
+
+// SetLevel specifies the minimum level of the logs that are ingested. Defaults
+// to [hclog.Info].
+func SetLevel(level hclog.Level) Option {
+	return func(l *Logger) error {
+		l.level.Store(int32(level))
+		return nil
+	}
+}
+
+// SetName specifies the name of the logger.
+func SetName(name string) Option {
+	return func(l *Logger) error {
+		l.name = name
+		return nil
+	}
+}
+
+type rootLogger struct {
+	client      *axiom.Client
+	datasetName string
+
+	clientOptions []axiom.Option
+	ingestOptions []ingest.Option
+
+	// level is shared by all loggers, just like with the hclog loggers that
+	// are not created with independent levels.
+	level atomic.Int32
+
+	ingester *ingester.Ingester
+}
+
+// Logger implements a [hclog.Logger] used for shipping logs to Axiom. It also
+// implements [hclog.SinkAdapter], so it can be registered with an existing
+// [hclog.InterceptLogger]. Every log carries the following fields, in addition
+// to its key/value pairs:
+//
+//   - _time: The time of the log.
+//   - level: The level of the log, e.g. "info".
+//   - logger: The name of the logger, if any. Names of sub-loggers created by
+//     [Logger.Named] are separated by ".".
+//   - msg: The message of the log.
+type Logger struct {
+	*rootLogger
+
+	name        string
+	impliedArgs []any
+}
+
+// New creates a new logger that ingests logs into Axiom. It automatically takes
+// its configuration from the environment. To connect, export the following
+// environment variables:
+//
+//   - AXIOM_TOKEN
+//   - AXIOM_ORG_ID (only when using a personal token)
+//   - AXIOM_DATASET
+//
+// The configuration can be set manually using options which are prefixed with
+// "Set".
+//
+// An API token with "ingest" permission is sufficient enough.
+//
+// A logger needs to be closed properly to make sure all logs are sent by
+// calling [Logger.Close].
+func New(options ...Option) (*Logger, error) {
+	logger := &Logger{
+		rootLogger: &rootLogger{},
+	}
+	logger.level.Store(int32(hclog.Info))
+
+	// Apply supplied options.
+	for _, option := range options {
+		if option == nil {
+			continue
+		} else if err := option(logger); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create client, if not set.
+	if logger.client == nil {
+		var err error
+		if logger.client, err = axiom.NewClient(logger.clientOptions...); err != nil {
+			return nil, err
+		}
+	}
+
+	// When the dataset name is not set, use "AXIOM_DATASET".
+	if logger.datasetName == "" {
+		logger.datasetName = os.Getenv("AXIOM_DATASET")
+		if logger.datasetName == "" {
+			return nil, ErrMissingDatasetName
+		}
+	}
+
+	// Run background ingest.
+	logger.ingester = ingester.New(logger.client, logger.datasetName, "[AXIOM|HCLOG]", logger.ingestOptions...)
+
+	return logger, nil
+}
+
+// Close the logger and make sure all events are flushed. Closing the logger
+// renders it and all of its sub-loggers unusable for further use.
+func (l *Logger) Close() {
+	l.ingester.Close()
+}
+
+// Accept implements [hclog.SinkAdapter]. The arguments passed by an
+// [hclog.InterceptLogger] already include its implied arguments, so the
+// implied arguments of the logger are not added.
+func (l *Logger) Accept(name string, level hclog.Level, msg string, args ...any) {
+	if !l.enabled(level) {
+		return
+	}
+	l.ingest(name, level, msg, args)
+}
+
+// Log implements [hclog.Logger].
+func (l *Logger) Log(level hclog.Level, msg string, args ...any) {
+	if !l.enabled(level) {
+		return
+	}
+	l.ingest(l.name, level, msg, append(slices.Clip(l.impliedArgs), args...))
+}
+
+// Trace implements [hclog.Logger].
+func (l *Logger) Trace(msg string, args ...any) { l.Log(hclog.Trace, msg, args...) }
+
+// Debug implements [hclog.Logger].
+func (l *Logger) Debug(msg string, args ...any) { l.Log(hclog.Debug, msg, args...) }
+
+// Info implements [hclog.Logger].
+func (l *Logger) Info(msg string, args ...any) { l.Log(hclog.Info, msg, args...) }
+
+// Warn implements [hclog.Logger].
+func (l *Logger) Warn(msg string, args ...any) { l.Log(hclog.Warn, msg, args...) }
+
+// Error implements [hclog.Logger].
+func (l *Logger) Error(msg string, args ...any) { l.Log(hclog.Error, msg, args...) }
+
+// IsTrace implements [hclog.Logger].
+func (l *Logger) IsTrace() bool { return l.enabled(hclog.Trace) }
+
+// IsDebug implements [hclog.Logger].
+func (l *Logger) IsDebug() bool { return l.enabled(hclog.Debug) }
+
+// IsInfo implements [hclog.Logger].
+func (l *Logger) IsInfo() bool { return l.enabled(hclog.Info) }
+
+// IsWarn implements [hclog.Logger].
+func (l *Logger) IsWarn() bool { return l.enabled(hclog.Warn) }
+
+// IsError implements [hclog.Logger].
+func (l *Logger) IsError() bool { return l.enabled(hclog.Error) }
+
+// ImpliedArgs implements [hclog.Logger].
+func (l *Logger) ImpliedArgs() []any { return l.impliedArgs }
+
+// With implements [hclog.Logger].
+func (l *Logger) With(args ...any) hclog.Logger {
+	l2 := l.clone()
+	l2.impliedArgs = append(l2.impliedArgs, args...)
+	return l2
+}
+
+// Name implements [hclog.Logger].
+func (l *Logger) Name() string { return l.name }
+
+// Named implements [hclog.Logger].
+func (l *Logger) Named(name string) hclog.Logger {
+	l2 := l.clone()
+	if l2.name != "" {
+		l2.name += "." + name
+	} else {
+		l2.name = name
+	}
+	return l2
+}
+
+// ResetNamed implements [hclog.Logger].
+func (l *Logger) ResetNamed(name string) hclog.Logger {
+	l2 := l.clone()
+	l2.name = name
+	return l2
+}
+
+// SetLevel implements [hclog.Logger]. It affects all related loggers.
+func (l *Logger) SetLevel(level hclog.Level) {
+	l.level.Store(int32(level))
+}
+
+// GetLevel implements [hclog.Logger].
+func (l *Logger) GetLevel() hclog.Level {
+	return hclog.Level(l.level.Load())
+}
+
+// StandardLogger implements [hclog.Logger].
+func (l *Logger) StandardLogger(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return log.New(l.StandardWriter(opts), "", 0)
+}
+
+// StandardWriter implements [hclog.Logger].
+func (l *Logger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	if opts == nil {
+		opts = &hclog.StandardLoggerOptions{}
+	}
+	return &stdWriter{
+		logger: l,
+		opts:   *opts,
+	}
+}
+
+func (l *Logger) clone() *Logger {
+	return &Logger{
+		rootLogger: l.rootLogger,
+
+		name:        l.name,
+		impliedArgs: slices.Clip(l.impliedArgs),
+	}
+}
+
+func (l *Logger) enabled(level hclog.Level) bool {
+	minLevel := l.GetLevel()
+	return minLevel != hclog.Off && level != hclog.Off && (level == hclog.NoLevel || level >= minLevel)
+}
+
+func (l *Logger) ingest(name string, level hclog.Level, msg string, args []any) {
+	event := make(axiom.Event, len(args)/2+4)
+
+	// Set arguments first.
+	for kv := range slices.Chunk(args, 2) {
+		key, ok := kv[0].(string)
+		if !ok {
+			key = fmt.Sprint(kv[0])
+		}
+		if len(kv) < 2 {
+			event[hclog.MissingKey] = value(kv[0])
+			continue
+		}
+		event[key] = value(kv[1])
+	}
+
+	// Set timestamp, level, logger name and actual message. Logs without a
+	// level are treated as info logs, just like hclog does.
+	if level == hclog.NoLevel {
+		level = hclog.Info
+	}
+	event[ingest.TimestampField] = time.Now().Format(time.RFC3339Nano)
+	event["level"] = level.String()
+	if name != "" {
+		event["logger"] = name
+	}
+	event["msg"] = msg
+
+	_ = l.ingester.Ingest(event)
+}
+
+// value formats the hclog specific value types the same way hclog does.
+func value(v any) any {
+	switch v := v.(type) {
+	case hclog.Format:
+		if len(v) == 0 {
+			return ""
+		}
+		format, _ := v[0].(string)
+		return fmt.Sprintf(format, v[1:]...)
+	case hclog.Hex:
+		return fmt.Sprintf("0x%x", int(v))
+	case hclog.Octal:
+		return fmt.Sprintf("0%o", int(v))
+	case hclog.Binary:
+		return fmt.Sprintf("0b%b", int(v))
+	case hclog.Quote:
+		return fmt.Sprintf("%q", string(v))
+	case error:
+		return v.Error()
+	default:
+		return v
+	}
+}
+
+// timestampRegexp matches characters commonly found in timestamps at the
+// beginning of a line.
+var timestampRegexp = regexp.MustCompile(`^[\d\s\:\/\.\+-TZ]*`)
+
+// stdWriter is the [io.Writer] returned by [Logger.StandardWriter]. It logs
+// every write as a message, optionally inferring its level from a prefix like
+// "[WARN]".
+type stdWriter struct {
+	logger *Logger
+	opts   hclog.StandardLoggerOptions
+}
+
+func (w *stdWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimRight(p, " \t\n"))
+
+	level := hclog.Info
+	switch {
+	case w.opts.ForceLevel != hclog.NoLevel:
+		_, msg = pickLevel(msg)
+		level = w.opts.ForceLevel
+	case w.opts.InferLevels:
+		if w.opts.InferLevelsWithTimestamp {
+			msg = msg[timestampRegexp.FindStringIndex(msg)[1]:]
+		}
+		level, msg = pickLevel(msg)
+	}
+	w.logger.Log(level, msg)
+
+	return len(p), nil
+}
+
+// pickLevel detects the level of the message based on conventional prefixes
+// and strips them off.
+func pickLevel(msg string) (hclog.Level, string) {
+	for _, prefix := range []struct {
+		prefix string
+		level  hclog.Level
+	}{
+		{"[TRACE]", hclog.Trace},
+		{"[DEBUG]", hclog.Debug},
+		{"[INFO]", hclog.Info},
+		{"[WARN]", hclog.Warn},
+		{"[ERROR]", hclog.Error},
+		{"[ERR]", hclog.Error},
+	} {
+		if rest, ok := strings.CutPrefix(msg, prefix.prefix); ok {
+			return prefix.level, strings.TrimSpace(rest)
+		}
+	}
+	return hclog.Info, msg
+}
@@ -0,0 +1,21 @@
+package hclog_test
+
+import (
+	"log"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/hclog"
+)
+
+func Example() {
+	// Export "AXIOM_DATASET" in addition to the required environment variables.
+
+	logger, err := adapter.New(adapter.SetName("example"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer logger.Close()
+
+	logger.Info("This is awesome!", "mood", "hyped")
+	logger.Warn("This is not that awesome...", "mood", "worried")
+	logger.Error("This is rather bad.", "mood", "depressed")
+}
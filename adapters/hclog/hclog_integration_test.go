@@ -0,0 +1,28 @@
+package hclog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/hclog"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+)
+
+func Test(t *testing.T) {
+	adapters.IntegrationTest(t, "hclog", func(_ context.Context, dataset string, client *axiom.Client) {
+		logger, err := adapter.New(
+			adapter.SetClient(client),
+			adapter.SetDataset(dataset),
+		)
+		require.NoError(t, err)
+
+		defer logger.Close()
+
+		logger.Info("This is awesome!", "mood", "hyped")
+		logger.Warn("This is not that awesome...", "mood", "worried")
+		logger.Error("This is rather bad.", "mood", "depressed")
+	})
+}
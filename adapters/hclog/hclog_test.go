@@ -0,0 +1,149 @@
+package hclog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+	"github.com/axiomhq/axiom-go/internal/test/testhelper"
+)
+
+// TestNew makes sure New() picks up the "AXIOM_DATASET" environment variable.
+func TestNew(t *testing.T) {
+	testhelper.SafeClearEnv(t)
+
+	t.Setenv("AXIOM_TOKEN", "xaat-test")
+	t.Setenv("AXIOM_ORG_ID", "123")
+
+	logger, err := New()
+	require.ErrorIs(t, err, ErrMissingDatasetName)
+	require.Nil(t, logger)
+
+	t.Setenv("AXIOM_DATASET", "test")
+
+	logger, err = New()
+	require.NoError(t, err)
+	require.NotNil(t, logger)
+	logger.Close()
+
+	assert.Equal(t, "test", logger.datasetName)
+}
+
+func TestLogger(t *testing.T) {
+	now := time.Now().Format(time.RFC3339Nano)
+	exp := []string{
+		fmt.Sprintf(`{"_time":"%s","level":"info","logger":"plugin.backend","msg":"my message","key":"value","hex":"0xff","format":"a-1","error":"this is an error"}`, now),
+		fmt.Sprintf(`{"_time":"%s","level":"debug","logger":"plugin.backend","msg":"debug message","key":"value","EXTRA_VALUE_AT_END":"missing"}`, now),
+		fmt.Sprintf(`{"_time":"%s","level":"warn","logger":"std","msg":"from the standard logger"}`, now),
+	}
+
+	var lines uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			i := atomic.AddUint64(&lines, 1) - 1
+			if assert.Less(t, int(i), len(exp)) {
+				testhelper.JSONEqExp(t, exp[i], s.Text(), []string{ingest.TimestampField})
+			}
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, closeLogger := adapters.Setup(t, hf, setup(t, SetName("plugin")))
+
+	sub := logger.Named("backend").With("key", "value")
+
+	sub.Info("my message",
+		"hex", hclog.Hex(255),
+		"format", hclog.Fmt("%s-%d", "a", 1),
+		"error", errors.New("this is an error"),
+	)
+	sub.Debug("debug message", "missing") // Not enabled yet.
+	sub.SetLevel(hclog.Debug)             // Affects the parent logger, too.
+	assert.True(t, logger.IsDebug())
+	assert.False(t, logger.IsTrace())
+	sub.Debug("debug message", "missing")
+	sub.Trace("trace message") // Not enabled.
+
+	std := logger.ResetNamed("std").StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true})
+	std.Println("[WARN] from the standard logger")
+
+	closeLogger()
+
+	assert.EqualValues(t, 3, atomic.LoadUint64(&lines))
+}
+
+func TestLogger_SinkAdapter(t *testing.T) {
+	exp := fmt.Sprintf(`{"_time":"%s","level":"error","logger":"app","msg":"my message","key":"value"}`,
+		time.Now().Format(time.RFC3339Nano))
+
+	var lines uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			testhelper.JSONEqExp(t, exp, s.Text(), []string{ingest.TimestampField})
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	sink, closeSink := adapters.Setup(t, hf, setup(t))
+
+	logger := hclog.NewInterceptLogger(&hclog.LoggerOptions{
+		Name:   "app",
+		Output: testWriter{t},
+	})
+	logger.RegisterSink(sink)
+
+	logger.With("key", "value").Error("my message")
+
+	closeSink()
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&lines))
+}
+
+func setup(t *testing.T, options ...Option) func(dataset string, client *axiom.Client) (*Logger, func()) {
+	return func(dataset string, client *axiom.Client) (*Logger, func()) {
+		t.Helper()
+
+		logger, err := New(append([]Option{
+			SetClient(client),
+			SetDataset(dataset),
+		}, options...)...)
+		require.NoError(t, err)
+		t.Cleanup(logger.Close)
+
+		return logger, logger.Close
+	}
+}
+
+type testWriter struct{ t *testing.T }
+
+func (w testWriter) Write(p []byte) (int, error) {
+	w.t.Log(string(p))
+	return len(p), nil
+}
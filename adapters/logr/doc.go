@@ -0,0 +1,3 @@
+// Package logr provides an adapter for the github.com/go-logr/logr logging
+// interface, which is used by Kubernetes controllers and klog, amongst others.
+package logr
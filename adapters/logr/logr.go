@@ -0,0 +1,264 @@
+package logr
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/internal/ingester"
+)
+
+var _ logr.LogSink = (*Sink)(nil)
+
+// ErrMissingDatasetName is raised when a dataset name is not provided. Set it
+// manually using the [SetDataset] option or export "AXIOM_DATASET".
+var ErrMissingDatasetName = errors.New("missing dataset name")
+
+// noValue is the value of a key without a value.
+const noValue = "<no-value>"
+
+// An Option modifies the behaviour of the Axiom sink.
+type Option func(*Sink) error
+
+// SetClient specifies the Axiom client to use for ingesting the logs.
+func SetClient(client *axiom.Client) Option {
+	return func(s *Sink) error {
+		s.client = client
+		return nil
+	}
+}
+
+// SetClientOptions specifies the Axiom client options to pass to
+// [axiom.NewClient] which is only called if no [axiom.Client] was specified by
+// the [SetClient] option.
+func SetClientOptions(options ...axiom.Option) Option {
+	return func(s *Sink) error {
+		s.clientOptions = options
+		return nil
+	}
+}
+
+// SetDataset specifies the dataset to ingest the logs into. Can also be
+// specified using the "AXIOM_DATASET" environment variable.
+func SetDataset(datasetName string) Option {
+	return func(s *Sink) error {
+		s.datasetName = datasetName
+		return nil
+	}
+}
+
+// SetIngestOptions specifies the ingestion options to use for ingesting the
+// logs.
+func SetIngestOptions(opts ...ingest.Option) Option {
+	return func(s *Sink) error {
+		s.ingestOptions = opts
+		return nil
+	}
+}
+
+// SetVerbosity specifies the maximum V-level of info logs that are ingested.
+// Error logs are always ingested. Defaults to 0, which only ingests info logs
+// that are not created by [logr.Logger.V] with a level greater than 0.
+func SetVerbosity(verbosity int) Option {
+	return func(s *Sink) error {
+		if verbosity < 0 {
+			return errors.New("verbosity cannot be negative")
+		}
+		s.verbosity = verbosity
+		return nil
+	}
+}
+
+type rootSink struct {
+	client      *axiom.Client
+	datasetName string
+
+	clientOptions []axiom.Option
+	ingestOptions []ingest.Option
+	verbosity     int
+
+	ingester *ingester.Ingester
+}
+
+// Sink implements a [logr.LogSink] used for shipping logs to Axiom. Every log
+// carries the following fields, in addition to its key/value pairs:
+//
+//   - _time: The time of the log.
+//   - level: The level of the log, either "info" or "error".
+//   - v: The V-level of info logs.
+//   - logger: The name of the logger given by [logr.Logger.WithName], with
+//     the name of each sub-logger separated by "/", if any.
+//   - msg: The message of the log.
+//   - error: The message of the error passed to [logr.Logger.Error], if any.
+type Sink struct {
+	*rootSink
+
+	name   string
+	values axiom.Event
+}
+
+// New creates a new sink that ingests logs into Axiom. It automatically takes
+// its configuration from the environment. To connect, export the following
+// environment variables:
+//
+//   - AXIOM_TOKEN
+//   - AXIOM_ORG_ID (only when using a personal token)
+//   - AXIOM_DATASET
+//
+// The configuration can be set manually using options which are prefixed with
+// "Set".
+//
+// An API token with "ingest" permission is sufficient enough.
+//
+// A sink needs to be closed properly to make sure all logs are sent by calling
+// [Sink.Close].
+func New(options ...Option) (*Sink, error) {
+	sink := &Sink{
+		rootSink: &rootSink{},
+	}
+
+	// Apply supplied options.
+	for _, option := range options {
+		if option == nil {
+			continue
+		} else if err := option(sink); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create client, if not set.
+	if sink.client == nil {
+		var err error
+		if sink.client, err = axiom.NewClient(sink.clientOptions...); err != nil {
+			return nil, err
+		}
+	}
+
+	// When the dataset name is not set, use "AXIOM_DATASET".
+	if sink.datasetName == "" {
+		sink.datasetName = os.Getenv("AXIOM_DATASET")
+		if sink.datasetName == "" {
+			return nil, ErrMissingDatasetName
+		}
+	}
+
+	// Run background ingest.
+	sink.ingester = ingester.New(sink.client, sink.datasetName, "[AXIOM|LOGR]", sink.ingestOptions...)
+
+	return sink, nil
+}
+
+// Close the sink and make sure all events are flushed. Closing the sink renders
+// it and all loggers using it unusable for further use.
+func (s *Sink) Close() {
+	s.ingester.Close()
+}
+
+// Init implements [logr.LogSink].
+func (s *Sink) Init(logr.RuntimeInfo) {}
+
+// Enabled implements [logr.LogSink].
+func (s *Sink) Enabled(level int) bool {
+	return level <= s.verbosity
+}
+
+// Info implements [logr.LogSink].
+func (s *Sink) Info(level int, msg string, keysAndValues ...any) {
+	event := s.event(msg, keysAndValues)
+	event["level"] = "info"
+	event["v"] = level
+
+	_ = s.ingester.Ingest(event)
+}
+
+// Error implements [logr.LogSink].
+func (s *Sink) Error(err error, msg string, keysAndValues ...any) {
+	event := s.event(msg, keysAndValues)
+	event["level"] = "error"
+	if err != nil {
+		event["error"] = err.Error()
+	}
+
+	_ = s.ingester.Ingest(event)
+}
+
+// WithValues implements [logr.LogSink].
+func (s *Sink) WithValues(keysAndValues ...any) logr.LogSink {
+	s2 := s.clone()
+	addKeysAndValues(s2.values, keysAndValues)
+	return s2
+}
+
+// WithName implements [logr.LogSink].
+func (s *Sink) WithName(name string) logr.LogSink {
+	s2 := s.clone()
+	if s2.name == "" {
+		s2.name = name
+	} else {
+		s2.name = strings.Join([]string{s2.name, name}, "/")
+	}
+	return s2
+}
+
+func (s *Sink) clone() *Sink {
+	values := make(axiom.Event, len(s.values))
+	maps.Copy(values, s.values)
+	return &Sink{
+		rootSink: s.rootSink,
+
+		name:   s.name,
+		values: values,
+	}
+}
+
+func (s *Sink) event(msg string, keysAndValues []any) axiom.Event {
+	event := make(axiom.Event, len(s.values)+len(keysAndValues)/2+5)
+
+	// Set sink values first, log values second.
+	maps.Copy(event, s.values)
+	addKeysAndValues(event, keysAndValues)
+
+	// Set timestamp, logger name and actual message.
+	event[ingest.TimestampField] = time.Now().Format(time.RFC3339Nano)
+	if s.name != "" {
+		event["logger"] = s.name
+	}
+	event["msg"] = msg
+
+	return event
+}
+
+// addKeysAndValues adds the key/value pairs to the event. Keys that are not
+// strings are formatted, a key without a value gets the value "<no-value>".
+func addKeysAndValues(event axiom.Event, keysAndValues []any) {
+	for kv := range slices.Chunk(keysAndValues, 2) {
+		key, ok := kv[0].(string)
+		if !ok {
+			key = fmt.Sprint(kv[0])
+		}
+		if len(kv) < 2 {
+			event[key] = noValue
+			continue
+		}
+		event[key] = value(kv[1])
+	}
+}
+
+func value(v any) any {
+	switch v := v.(type) {
+	case logr.Marshaler:
+		return v.MarshalLog()
+	case error:
+		return v.Error()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,26 @@
+package logr_test
+
+import (
+	"errors"
+	"log"
+
+	"github.com/go-logr/logr"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/logr"
+)
+
+func Example() {
+	// Export "AXIOM_DATASET" in addition to the required environment variables.
+
+	sink, err := adapter.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer sink.Close()
+
+	logger := logr.New(sink)
+
+	logger.Info("This is awesome!", "mood", "hyped")
+	logger.V(1).Info("This is not that awesome...", "mood", "worried")
+	logger.Error(errors.New("oops"), "This is rather bad.", "mood", "depressed")
+}
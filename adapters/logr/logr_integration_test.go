@@ -0,0 +1,32 @@
+package logr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/logr"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+)
+
+func Test(t *testing.T) {
+	adapters.IntegrationTest(t, "logr", func(_ context.Context, dataset string, client *axiom.Client) {
+		sink, err := adapter.New(
+			adapter.SetClient(client),
+			adapter.SetDataset(dataset),
+		)
+		require.NoError(t, err)
+
+		defer sink.Close()
+
+		logger := logr.New(sink)
+
+		logger.Info("This is awesome!", "mood", "hyped")
+		logger.Info("This is not that awesome...", "mood", "worried")
+		logger.Error(errors.New("oops"), "This is rather bad.", "mood", "depressed")
+	})
+}
@@ -0,0 +1,125 @@
+package logr
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+	"github.com/axiomhq/axiom-go/internal/test/testhelper"
+)
+
+// TestNew makes sure New() picks up the "AXIOM_DATASET" environment variable.
+func TestNew(t *testing.T) {
+	testhelper.SafeClearEnv(t)
+
+	t.Setenv("AXIOM_TOKEN", "xaat-test")
+	t.Setenv("AXIOM_ORG_ID", "123")
+
+	sink, err := New()
+	require.ErrorIs(t, err, ErrMissingDatasetName)
+	require.Nil(t, sink)
+
+	t.Setenv("AXIOM_DATASET", "test")
+
+	sink, err = New()
+	require.NoError(t, err)
+	require.NotNil(t, sink)
+	sink.Close()
+
+	assert.Equal(t, "test", sink.datasetName)
+}
+
+func TestSink(t *testing.T) {
+	now := time.Now().Format(time.RFC3339Nano)
+	exp := []string{
+		fmt.Sprintf(`{"_time":"%s","level":"info","v":0,"logger":"controller/reconciler","msg":"my message","key":"value","count":1,"missing":"<no-value>"}`, now),
+		fmt.Sprintf(`{"_time":"%s","level":"info","v":1,"logger":"controller/reconciler","msg":"debug message","key":"value"}`, now),
+		fmt.Sprintf(`{"_time":"%s","level":"error","logger":"controller/reconciler","msg":"my error","key":"value","error":"this is an error"}`, now),
+	}
+
+	var lines uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			i := atomic.AddUint64(&lines, 1) - 1
+			if assert.Less(t, int(i), len(exp)) {
+				testhelper.JSONEqExp(t, exp[i], s.Text(), []string{ingest.TimestampField})
+			}
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, closeSink := adapters.Setup(t, hf, setup(t, SetVerbosity(1)))
+
+	logger = logger.WithName("controller").WithName("reconciler").WithValues("key", "value")
+
+	logger.Info("my message", "count", 1, "missing")
+	logger.V(1).Info("debug message")
+	logger.V(2).Info("trace message") // Not enabled.
+	logger.Error(errors.New("this is an error"), "my error")
+
+	closeSink()
+
+	assert.EqualValues(t, 3, atomic.LoadUint64(&lines))
+}
+
+func TestSink_NoPanicAfterClose(t *testing.T) {
+	var lines uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, closeSink := adapters.Setup(t, hf, setup(t))
+
+	logger.Info("my message")
+
+	closeSink()
+
+	// This should be a no-op.
+	logger.Info("my message")
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&lines))
+}
+
+func setup(t *testing.T, options ...Option) func(dataset string, client *axiom.Client) (logr.Logger, func()) {
+	return func(dataset string, client *axiom.Client) (logr.Logger, func()) {
+		t.Helper()
+
+		sink, err := New(append([]Option{
+			SetClient(client),
+			SetDataset(dataset),
+		}, options...)...)
+		require.NoError(t, err)
+		t.Cleanup(sink.Close)
+
+		return logr.New(sink), sink.Close
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/axiomhq/axiom-go/adapters/sampling"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 )
@@ -71,6 +72,15 @@ func SetLevels(levels ...logrus.Level) Option {
 	}
 }
 
+// SetSampler specifies the sampler that decides which entries are ingested.
+// Entries are sampled by their level and message.
+func SetSampler(sampler *sampling.Sampler) Option {
+	return func(h *Hook) error {
+		h.sampler = sampler
+		return nil
+	}
+}
+
 // Hook implements a [logrus.Hook] used for shipping logs to Axiom.
 type Hook struct {
 	client      *axiom.Client
@@ -79,6 +89,7 @@ type Hook struct {
 	clientOptions []axiom.Option
 	ingestOptions []ingest.Option
 	levels        []logrus.Level
+	sampler       *sampling.Sampler
 
 	eventCh   chan axiom.Event
 	stopCh    chan struct{}
@@ -179,6 +190,14 @@ func (h *Hook) Levels() []logrus.Level {
 
 // Fire implements [logrus.Hook].
 func (h *Hook) Fire(entry *logrus.Entry) error {
+	var dropped uint64
+	if h.sampler != nil {
+		var ok bool
+		if ok, dropped = h.sampler.Sample(entry.Level.String(), entry.Message); !ok {
+			return nil
+		}
+	}
+
 	event := axiom.Event{}
 
 	// Set fields first.
@@ -188,6 +207,9 @@ func (h *Hook) Fire(entry *logrus.Entry) error {
 	event[ingest.TimestampField] = entry.Time.Format(time.RFC3339Nano)
 	event["severity"] = entry.Level.String()
 	event["message"] = entry.Message
+	if dropped > 0 {
+		event[sampling.DroppedField] = dropped
+	}
 
 	select {
 	case <-h.closeCh:
@@ -14,7 +14,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/axiomhq/axiom-go/adapters/sampling"
 	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
 	"github.com/axiomhq/axiom-go/internal/test/adapters"
 	"github.com/axiomhq/axiom-go/internal/test/testhelper"
 )
@@ -74,6 +76,64 @@ func TestHook(t *testing.T) {
 	assert.EqualValues(t, 1, atomic.LoadUint64(&hasRun))
 }
 
+func TestHook_Sampler(t *testing.T) {
+	exp := []string{
+		`{"_time":"time","severity":"info","message":"my message"}`,
+		`{"_time":"time","severity":"warning","message":"my other message","dropped_events":3}`,
+	}
+
+	var lines []string
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			lines = append(lines, s.Text())
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	sampler, err := sampling.New(sampling.SetFirstThereafter(time.Minute, 1, 0))
+	require.NoError(t, err)
+
+	logger, closeHook := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*logrus.Logger, func()) {
+		t.Helper()
+
+		hook, err := New(
+			SetClient(client),
+			SetDataset(dataset),
+			SetSampler(sampler),
+		)
+		require.NoError(t, err)
+		t.Cleanup(hook.Close)
+
+		logger := logrus.New()
+		logger.AddHook(hook)
+
+		// We don't want output in tests.
+		logger.Out = io.Discard
+
+		return logger, hook.Close
+	})
+
+	for range 4 {
+		logger.Info("my message")
+	}
+	logger.Warn("my other message")
+
+	closeHook()
+
+	if assert.Len(t, lines, len(exp)) {
+		for i := range exp {
+			testhelper.JSONEqExp(t, exp[i], lines[i], []string{ingest.TimestampField})
+		}
+	}
+}
+
 func TestHook_NoPanicAfterClose(t *testing.T) {
 	now := time.Now()
 
@@ -0,0 +1,17 @@
+// Package sampling provides a [Sampler] that limits the number of events the
+// logging adapters ingest, e.g. to keep noisy debug or error loops from using
+// up the ingest quota. It is passed to an adapter using its SetSampler option.
+//
+// Usage:
+//
+//	import "github.com/axiomhq/axiom-go/adapters/sampling"
+//
+//	sampler, err := sampling.New(
+//	    sampling.SetLevelRate("debug", 0.1),
+//	    sampling.SetFirstThereafter(time.Second, 100, 10),
+//	    sampling.SetRateLimit(1000),
+//	)
+//
+// Every event that is ingested after events have been dropped carries the
+// number of dropped events in the [DroppedField] field.
+package sampling
@@ -0,0 +1,186 @@
+package sampling
+
+import (
+	"errors"
+	"hash/fnv"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DroppedField is the field that records the number of events that were
+// dropped since the last event that was ingested.
+const DroppedField = "dropped_events"
+
+// numCounters is the number of counters used to sample by message. Messages
+// whose hashes collide share a counter.
+const numCounters = 4096
+
+// An Option modifies the behaviour of the sampler.
+type Option func(*Sampler) error
+
+// SetLevelRate specifies the fraction of events of the given level that are
+// ingested. The rate must be in the range [0, 1]. Levels are matched
+// case-insensitively and "warning" is treated as "warn". Levels without a rate
+// are not sampled.
+func SetLevelRate(level string, rate float64) Option {
+	return func(s *Sampler) error {
+		if rate < 0 || rate > 1 {
+			return errors.New("sample rate must be in the range [0, 1]")
+		}
+		s.levelRates[normalizeLevel(level)] = rate
+		return nil
+	}
+}
+
+// SetFirstThereafter specifies that, per level and message and within each
+// tick, the first events are ingested and then only every thereafter-th event.
+// A thereafter of 0 drops all events after the first ones. This works like the
+// sampler of zap.
+func SetFirstThereafter(tick time.Duration, first, thereafter int) Option {
+	return func(s *Sampler) error {
+		if tick <= 0 {
+			return errors.New("tick must be greater than 0")
+		} else if first < 0 || thereafter < 0 {
+			return errors.New("first and thereafter cannot be negative")
+		}
+		s.tick = tick
+		s.first = uint64(first)
+		s.thereafter = uint64(thereafter)
+		s.counters = new([numCounters]counter)
+		return nil
+	}
+}
+
+// SetRateLimit specifies the maximum number of events ingested per second,
+// regardless of their level and message.
+func SetRateLimit(eventsPerSecond int) Option {
+	return func(s *Sampler) error {
+		if eventsPerSecond <= 0 {
+			return errors.New("rate limit must be greater than 0")
+		}
+		s.rateLimit = eventsPerSecond
+		return nil
+	}
+}
+
+// Sampler decides which events are ingested. It is safe for concurrent use and
+// can be shared by multiple adapters.
+type Sampler struct {
+	levelRates map[string]float64
+
+	tick       time.Duration
+	first      uint64
+	thereafter uint64
+	counters   *[numCounters]counter
+
+	rateLimit int
+	// mu guards window and count.
+	mu     sync.Mutex
+	window int64
+	count  int
+
+	dropped atomic.Uint64
+
+	now func() time.Time
+}
+
+// New creates a new sampler. Without any options, it ingests all events.
+func New(options ...Option) (*Sampler, error) {
+	s := &Sampler{
+		levelRates: make(map[string]float64),
+		now:        time.Now,
+	}
+
+	// Apply supplied options.
+	for _, option := range options {
+		if option == nil {
+			continue
+		} else if err := option(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Sample reports whether an event of the given level and message is ingested.
+// If it is, the number of events dropped since the last ingested event is
+// returned as well, which should be recorded in the [DroppedField] field.
+func (s *Sampler) Sample(level, message string) (bool, uint64) {
+	if !s.sample(normalizeLevel(level), message) {
+		s.dropped.Add(1)
+		return false, 0
+	}
+	return true, s.dropped.Swap(0)
+}
+
+func (s *Sampler) sample(level, message string) bool {
+	//nolint:gosec // Sampling does not need a cryptographically secure source.
+	if rate, ok := s.levelRates[level]; ok && rand.Float64() >= rate {
+		return false
+	}
+
+	if s.counters != nil {
+		n := s.counters[counterIndex(level, message)].incCheckReset(s.now(), s.tick)
+		if n > s.first && (s.thereafter == 0 || (n-s.first)%s.thereafter != 0) {
+			return false
+		}
+	}
+
+	if s.rateLimit > 0 {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if window := s.now().Unix(); window != s.window {
+			s.window, s.count = window, 0
+		}
+		if s.count >= s.rateLimit {
+			return false
+		}
+		s.count++
+	}
+
+	return true
+}
+
+// counter counts the events of a tick.
+type counter struct {
+	resetAt atomic.Int64
+	n       atomic.Uint64
+}
+
+// incCheckReset increments the counter and returns the new count. The counter
+// is reset first, if the current tick has passed.
+func (c *counter) incCheckReset(t time.Time, tick time.Duration) uint64 {
+	tn := t.UnixNano()
+	resetAt := c.resetAt.Load()
+	if resetAt > tn {
+		return c.n.Add(1)
+	}
+
+	c.n.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, tn+tick.Nanoseconds()) {
+		// Another goroutine reset the counter in the meantime.
+		return c.n.Add(1)
+	}
+	return 1
+}
+
+func counterIndex(level, message string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(level))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(message))
+	return h.Sum32() % numCounters
+}
+
+func normalizeLevel(level string) string {
+	level = strings.ToLower(level)
+	if level == "warning" {
+		return "warn"
+	}
+	return level
+}
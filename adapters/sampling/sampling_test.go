@@ -0,0 +1,100 @@
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampler_Default(t *testing.T) {
+	s, err := New()
+	require.NoError(t, err)
+
+	for range 100 {
+		ok, dropped := s.Sample("debug", "my message")
+		assert.True(t, ok)
+		assert.Zero(t, dropped)
+	}
+}
+
+func TestSampler_LevelRate(t *testing.T) {
+	s, err := New(
+		SetLevelRate("DEBUG", 0),
+		SetLevelRate("warning", 1),
+	)
+	require.NoError(t, err)
+
+	ok, _ := s.Sample("debug", "my message")
+	assert.False(t, ok)
+	ok, _ = s.Sample("Debug", "my message")
+	assert.False(t, ok)
+
+	ok, dropped := s.Sample("warn", "my message")
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, dropped)
+
+	ok, dropped = s.Sample("info", "my message")
+	assert.True(t, ok)
+	assert.Zero(t, dropped)
+}
+
+func TestSampler_FirstThereafter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := New(SetFirstThereafter(time.Second, 2, 3))
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+
+	var kept []int
+	for i := 1; i <= 10; i++ {
+		if ok, _ := s.Sample("info", "my message"); ok {
+			kept = append(kept, i)
+		}
+	}
+	// First two, then every third.
+	assert.Equal(t, []int{1, 2, 5, 8}, kept)
+
+	// Other messages are counted separately.
+	ok, dropped := s.Sample("info", "other message")
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, dropped)
+
+	// The counter is reset with the next tick.
+	now = now.Add(time.Second)
+	ok, _ = s.Sample("info", "my message")
+	assert.True(t, ok)
+}
+
+func TestSampler_RateLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s, err := New(SetRateLimit(3))
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+
+	var kept int
+	for range 10 {
+		if ok, _ := s.Sample("error", "my message"); ok {
+			kept++
+		}
+	}
+	assert.Equal(t, 3, kept)
+
+	now = now.Add(time.Second)
+	ok, dropped := s.Sample("error", "my message")
+	assert.True(t, ok)
+	assert.EqualValues(t, 7, dropped)
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	_, err := New(SetLevelRate("debug", 2))
+	require.EqualError(t, err, "sample rate must be in the range [0, 1]")
+
+	_, err = New(SetFirstThereafter(0, 1, 1))
+	require.EqualError(t, err, "tick must be greater than 0")
+
+	_, err = New(SetRateLimit(0))
+	require.EqualError(t, err, "rate limit must be greater than 0")
+}
@@ -2,7 +2,10 @@ package slog
 
 import (
 	"context"
+	"encoding"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
@@ -10,6 +13,9 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/axiomhq/axiom-go/adapters/sampling"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 )
@@ -18,6 +24,13 @@ var _ slog.Handler = (*Handler)(nil)
 
 const defaultBatchSize = 10_000
 
+// Default keys of the trace context fields added to each event.
+const (
+	DefaultTraceIDKey    = "trace_id"
+	DefaultSpanIDKey     = "span_id"
+	DefaultTraceFlagsKey = "trace_flags"
+)
+
 // ErrMissingDatasetName is raised when a dataset name is not provided. Set it
 // manually using the [SetDataset] option or export "AXIOM_DATASET".
 var ErrMissingDatasetName = errors.New("missing dataset name")
@@ -77,6 +90,96 @@ func SetAddSource() Option {
 	}
 }
 
+// SetReplaceAttr specifies a function that rewrites each non-group attribute
+// before it is added to the event, just like [slog.HandlerOptions.ReplaceAttr].
+// It is called with the path of the groups the attribute is nested in and the
+// attribute with its value resolved. If it returns the zero [slog.Attr], the
+// attribute is discarded. The built-in attributes for time, level, message and
+// source (if enabled) are passed with a nil group path and the keys the handler
+// uses for them (see [SetTimeKey], [SetLevelKey], [SetMessageKey] and
+// [SetSourceKey]).
+func SetReplaceAttr(replaceAttr func(groups []string, a slog.Attr) slog.Attr) Option {
+	return func(h *Handler) error {
+		h.replaceAttr = replaceAttr
+		return nil
+	}
+}
+
+// SetTimeKey specifies the key of the time field. Defaults to
+// [ingest.TimestampField]. When changed, use [ingest.SetTimestampField] with
+// [SetIngestOptions] to let Axiom pick up the time of the events.
+func SetTimeKey(key string) Option {
+	return func(h *Handler) error {
+		h.timeKey = key
+		return nil
+	}
+}
+
+// SetLevelKey specifies the key of the level field. Defaults to
+// [slog.LevelKey].
+func SetLevelKey(key string) Option {
+	return func(h *Handler) error {
+		h.levelKey = key
+		return nil
+	}
+}
+
+// SetMessageKey specifies the key of the message field. Defaults to
+// [slog.MessageKey].
+func SetMessageKey(key string) Option {
+	return func(h *Handler) error {
+		h.messageKey = key
+		return nil
+	}
+}
+
+// SetSourceKey specifies the key of the source field which is only added if
+// [SetAddSource] is used. Defaults to [slog.SourceKey].
+func SetSourceKey(key string) Option {
+	return func(h *Handler) error {
+		h.sourceKey = key
+		return nil
+	}
+}
+
+// SetTraceKeys specifies the keys of the fields the trace ID, span ID and trace
+// flags of the OpenTelemetry span in the context passed to the logger are added
+// under. An empty key omits the respective field. Defaults to
+// [DefaultTraceIDKey], [DefaultSpanIDKey] and [DefaultTraceFlagsKey].
+func SetTraceKeys(traceIDKey, spanIDKey, traceFlagsKey string) Option {
+	return func(h *Handler) error {
+		h.traceIDKey = traceIDKey
+		h.spanIDKey = spanIDKey
+		h.traceFlagsKey = traceFlagsKey
+		return nil
+	}
+}
+
+// A ContextExtractor extracts attributes from the context passed to the logger,
+// e.g. a request or tenant ID. The attributes are added to the top level of the
+// event, regardless of any groups.
+type ContextExtractor func(ctx context.Context) []slog.Attr
+
+// SetContextExtractors specifies functions that extract additional attributes
+// from the context passed to the logger. They are called in order, for every
+// record handled.
+func SetContextExtractors(extractors ...ContextExtractor) Option {
+	return func(h *Handler) error {
+		h.contextExtractors = append(h.contextExtractors, extractors...)
+		return nil
+	}
+}
+
+// SetSampler specifies the sampler that decides which records are ingested.
+// Records are sampled by their level ("debug", "info", "warn" or "error") and
+// message.
+func SetSampler(sampler *sampling.Sampler) Option {
+	return func(h *Handler) error {
+		h.sampler = sampler
+		return nil
+	}
+}
+
 type rootHandler struct {
 	client      *axiom.Client
 	datasetName string
@@ -84,6 +187,19 @@ type rootHandler struct {
 	clientOptions []axiom.Option
 	ingestOptions []ingest.Option
 
+	replaceAttr func(groups []string, a slog.Attr) slog.Attr
+	timeKey     string
+	levelKey    string
+	messageKey  string
+	sourceKey   string
+
+	traceIDKey        string
+	spanIDKey         string
+	traceFlagsKey     string
+	contextExtractors []ContextExtractor
+
+	sampler *sampling.Sampler
+
 	eventCh   chan axiom.Event
 	stopCh    chan struct{}
 	closeCh   chan struct{}
@@ -95,11 +211,18 @@ type Handler struct {
 	*rootHandler
 
 	level     slog.Leveler
-	attrs     []slog.Attr
+	attrs     []groupedAttrs
 	groups    []string
 	addSource bool
 }
 
+// groupedAttrs are attributes added by [Handler.WithAttrs] together with the
+// groups that were open at the time.
+type groupedAttrs struct {
+	groups []string
+	attrs  []slog.Attr
+}
+
 // New creates a new handler that ingests logs into Axiom. It automatically
 // takes its configuration from the environment. To connect, export the
 // following environment variables:
@@ -117,6 +240,15 @@ type Handler struct {
 // calling [Handler.Close].
 func New(options ...Option) (*Handler, error) {
 	root := &rootHandler{
+		timeKey:    ingest.TimestampField,
+		levelKey:   slog.LevelKey,
+		messageKey: slog.MessageKey,
+		sourceKey:  slog.SourceKey,
+
+		traceIDKey:    DefaultTraceIDKey,
+		spanIDKey:     DefaultSpanIDKey,
+		traceFlagsKey: DefaultTraceFlagsKey,
+
 		eventCh: make(chan axiom.Event, defaultBatchSize),
 		stopCh:  make(chan struct{}),
 		closeCh: make(chan struct{}),
@@ -197,32 +329,45 @@ func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
 }
 
 // Handle implements [slog.Handler].
-func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	var dropped uint64
+	if h.sampler != nil {
+		var ok bool
+		if ok, dropped = h.sampler.Sample(samplingLevel(r.Level), r.Message); !ok {
+			return nil
+		}
+	}
+
 	event := axiom.Event{}
 
 	// Set handler attributes first, record attributes second.
-	for _, attr := range h.attrs {
-		addAttrToEvent(event, attr)
+	for _, ga := range h.attrs {
+		h.addAttrsToEvent(event, ga.groups, ga.attrs)
 	}
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
 	r.Attrs(func(attr slog.Attr) bool {
-		addAttrToEvent(event, attr)
+		attrs = append(attrs, attr)
 		return true
 	})
-
-	// Nest attributes in handler groups as objects, if any.
-	for i := len(h.groups) - 1; i >= 0; i-- {
-		event = axiom.Event{h.groups[i]: event}
-	}
+	h.addAttrsToEvent(event, h.groups, attrs)
 
 	// Set timestamp, level and actual message. The zero time is ignored.
 	if !r.Time.IsZero() {
-		event[ingest.TimestampField] = r.Time.Format(time.RFC3339Nano)
+		h.addBuiltinAttrToEvent(event, slog.Time(h.timeKey, r.Time))
 	}
-	event[slog.LevelKey] = r.Level.String()
-	event[slog.MessageKey] = r.Message
+	h.addBuiltinAttrToEvent(event, slog.Any(h.levelKey, r.Level))
+	h.addBuiltinAttrToEvent(event, slog.String(h.messageKey, r.Message))
 
 	if h.addSource {
-		event[slog.SourceKey] = r.Source()
+		h.addBuiltinAttrToEvent(event, slog.Any(h.sourceKey, r.Source()))
+	}
+
+	if ctx != nil {
+		h.addContextToEvent(ctx, event)
+	}
+
+	if dropped > 0 {
+		event[sampling.DroppedField] = dropped
 	}
 
 	select {
@@ -240,7 +385,10 @@ func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 		return h
 	}
 	h2 := h.clone()
-	h2.attrs = append(h2.attrs, attrs...)
+	h2.attrs = append(h2.attrs, groupedAttrs{
+		groups: h2.groups,
+		attrs:  slices.Clone(attrs),
+	})
 	return h2
 }
 
@@ -265,27 +413,179 @@ func (h *Handler) clone() *Handler {
 	}
 }
 
-func addAttrToEvent(event axiom.Event, attr slog.Attr) {
+// addAttrsToEvent adds the attributes to the event, nested in the given groups
+// as objects. Groups without any attributes are omitted.
+func (h *Handler) addAttrsToEvent(event axiom.Event, groups []string, attrs []slog.Attr) {
+	group := axiom.Event{}
+	for _, attr := range attrs {
+		h.addAttrToEvent(group, groups, attr)
+	}
+	if len(group) == 0 {
+		return
+	}
+
+	for _, name := range groups {
+		nested, ok := event[name].(axiom.Event)
+		if !ok {
+			nested = axiom.Event{}
+			event[name] = nested
+		}
+		event = nested
+	}
+	for k, v := range group {
+		event[k] = v
+	}
+}
+
+func (h *Handler) addAttrToEvent(event axiom.Event, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
 	if attr.Equal(slog.Attr{}) {
 		return
 	}
 
-	// If we have a group, nest it as an object.
-	v := attr.Value.Resolve()
+	// If we have a group, nest it as an object. Groups without a key are
+	// inlined.
+	if attr.Value.Kind() == slog.KindGroup {
+		group := event
+		if attr.Key != "" {
+			group = axiom.Event{}
+			groups = append(slices.Clip(groups), attr.Key)
+		}
+		for _, attr := range attr.Value.Group() {
+			h.addAttrToEvent(group, groups, attr)
+		}
+		if len(group) > 0 && attr.Key != "" {
+			event[attr.Key] = group
+		}
+		return
+	}
+
+	if h.replaceAttr != nil {
+		attr = h.replaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+		if attr.Equal(slog.Attr{}) {
+			return
+		}
+	}
+	event[attr.Key] = valueOf(attr.Value)
+}
+
+func (h *Handler) addBuiltinAttrToEvent(event axiom.Event, attr slog.Attr) {
+	if h.replaceAttr != nil {
+		attr = h.replaceAttr(nil, attr)
+		attr.Value = attr.Value.Resolve()
+		if attr.Equal(slog.Attr{}) {
+			return
+		}
+	}
+
+	switch v := attr.Value; v.Kind() {
+	case slog.KindTime:
+		event[attr.Key] = v.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		if level, ok := v.Any().(slog.Level); ok {
+			event[attr.Key] = level.String()
+		} else {
+			event[attr.Key] = valueOf(v)
+		}
+	default:
+		event[attr.Key] = valueOf(v)
+	}
+}
+
+// addContextToEvent adds the OpenTelemetry span context and the attributes of
+// the context extractors to the event.
+func (h *Handler) addContextToEvent(ctx context.Context, event axiom.Event) {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		if h.traceIDKey != "" {
+			event[h.traceIDKey] = sc.TraceID().String()
+		}
+		if h.spanIDKey != "" {
+			event[h.spanIDKey] = sc.SpanID().String()
+		}
+		if h.traceFlagsKey != "" {
+			event[h.traceFlagsKey] = sc.TraceFlags().String()
+		}
+	}
+	for _, extractor := range h.contextExtractors {
+		for _, attr := range extractor(ctx) {
+			h.addAttrToEvent(event, nil, attr)
+		}
+	}
+}
+
+// valueOf returns the representation of the resolved value in an event. Errors
+// are kept as objects, values implementing [encoding.TextMarshaler] but not
+// [json.Marshaler] are converted to strings.
+func valueOf(v slog.Value) any {
 	if v.Kind() == slog.KindGroup {
 		group := axiom.Event{}
 		for _, attr := range v.Group() {
-			addAttrToEvent(group, attr)
+			attr.Value = attr.Value.Resolve()
+			if !attr.Equal(slog.Attr{}) {
+				group[attr.Key] = valueOf(attr.Value)
+			}
 		}
-		if len(group) > 0 && attr.Key != "" {
-			event[attr.Key] = group
+		return group
+	} else if v.Kind() != slog.KindAny {
+		return v.Any()
+	}
+
+	switch a := v.Any().(type) {
+	case json.Marshaler:
+		return a
+	case error:
+		return errorToEvent(a)
+	case encoding.TextMarshaler:
+		text, err := a.MarshalText()
+		if err != nil {
+			return fmt.Sprintf("!ERROR:%v", err)
+		}
+		return string(text)
+	default:
+		return a
+	}
+}
+
+// errorToEvent turns an error into an object carrying its message, its type
+// and the errors it wraps, if any.
+func errorToEvent(err error) axiom.Event {
+	event := axiom.Event{
+		"message": err.Error(),
+		"type":    fmt.Sprintf("%T", err),
+	}
+
+	//nolint:errorlint // Only the directly wrapped errors are of interest.
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if wrapped := u.Unwrap(); wrapped != nil {
+			event["wrapped"] = errorToEvent(wrapped)
+		}
+	case interface{ Unwrap() []error }:
+		var wrapped []axiom.Event
+		for _, err := range u.Unwrap() {
+			if err != nil {
+				wrapped = append(wrapped, errorToEvent(err))
+			}
 		}
-	} else {
-		a := v.Any()
-		if err, ok := a.(error); ok {
-			//If the value is an error, we want to use the error message.
-			a = err.Error()
+		if len(wrapped) > 0 {
+			event["wrapped"] = wrapped
 		}
-		event[attr.Key] = a
 	}
+
+	return event
+}
+
+// samplingLevel maps the level to the name of the closest standard level below
+// it, which is used for sampling.
+func samplingLevel(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "error"
+	case level >= slog.LevelWarn:
+		return "warn"
+	case level >= slog.LevelInfo:
+		return "info"
+	}
+	return "debug"
 }
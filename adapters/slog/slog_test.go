@@ -2,11 +2,13 @@ package slog
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"runtime"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -14,7 +16,9 @@ import (
 	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/axiomhq/axiom-go/adapters/sampling"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 	"github.com/axiomhq/axiom-go/internal/test/adapters"
@@ -110,7 +114,7 @@ func TestHandler_Source(t *testing.T) {
 }
 
 func TestHandler_WithError(t *testing.T) {
-	exp := fmt.Sprintf(`{"_time":"%s","level":"INFO","key":"value","msg":"my message","error":"this is an error"}`,
+	exp := fmt.Sprintf(`{"_time":"%s","level":"INFO","key":"value","msg":"my message","error":{"message":"this is an error: EOF","type":"*fmt.wrapError","wrapped":{"message":"EOF","type":"*errors.errorString"}}}`,
 		time.Now().Format(time.RFC3339Nano))
 
 	var hasRun uint64
@@ -131,7 +135,7 @@ func TestHandler_WithError(t *testing.T) {
 
 	logger, closeHandler := adapters.Setup(t, hf, setup(t))
 
-	err := fmt.Errorf("this is an error")
+	err := fmt.Errorf("this is an error: %w", io.EOF)
 
 	logger.
 		With("key", "value").
@@ -210,6 +214,190 @@ func TestHandler_Groups(t *testing.T) {
 	assert.EqualValues(t, 2, atomic.LoadUint64(&lines))
 }
 
+type textValue struct{}
+
+func (textValue) MarshalText() ([]byte, error) { return []byte("text"), nil }
+
+type logValuer struct{}
+
+func (logValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("name", "value"), slog.Any("text", textValue{}))
+}
+
+func TestHandler_ReplaceAttr(t *testing.T) {
+	exp := `{"time":"2024-01-01T00:00:00Z","severity":"info","message":"my message","key":"value","s":{"v":{"name":"value","text":"text"},"group_path":"s"}}`
+
+	var lines uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			assert.JSONEq(t, exp, s.Text())
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, closeHandler := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
+		t.Helper()
+
+		handler, err := New(
+			SetClient(client),
+			SetDataset(dataset),
+			SetTimeKey("time"),
+			SetLevelKey("severity"),
+			SetMessageKey("message"),
+			SetReplaceAttr(func(groups []string, a slog.Attr) slog.Attr {
+				switch {
+				case a.Key == "time" && groups == nil:
+					return slog.Time(a.Key, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+				case a.Key == "severity" && groups == nil:
+					return slog.String(a.Key, strings.ToLower(a.Value.String()))
+				case a.Key == "secret":
+					return slog.Attr{}
+				case a.Key == "group_path":
+					return slog.String(a.Key, strings.Join(groups, "."))
+				}
+				return a
+			}),
+		)
+		require.NoError(t, err)
+		t.Cleanup(handler.Close)
+
+		return slog.New(handler), handler.Close
+	})
+
+	logger.
+		With("key", "value").
+		WithGroup("s").
+		Info("my message", "v", logValuer{}, "secret", "password", "group_path", "")
+
+	closeHandler()
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&lines))
+}
+
+func TestHandler_Context(t *testing.T) {
+	exp := fmt.Sprintf(`{"_time":"%s","level":"INFO","s":{"key":"value"},"msg":"my message","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span":"00f067aa0ba902b7","request_id":"abc"}`,
+		time.Now().Format(time.RFC3339Nano))
+
+	var lines uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			testhelper.JSONEqExp(t, exp, s.Text(), []string{ingest.TimestampField})
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	type requestIDKey struct{}
+
+	logger, closeHandler := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
+		t.Helper()
+
+		handler, err := New(
+			SetClient(client),
+			SetDataset(dataset),
+			SetTraceKeys(DefaultTraceIDKey, "span", ""),
+			SetContextExtractors(func(ctx context.Context) []slog.Attr {
+				if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+					return []slog.Attr{slog.String("request_id", id)}
+				}
+				return nil
+			}),
+		)
+		require.NoError(t, err)
+		t.Cleanup(handler.Close)
+
+		return slog.New(handler), handler.Close
+	})
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(t.Context(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	ctx = context.WithValue(ctx, requestIDKey{}, "abc")
+
+	logger.WithGroup("s").InfoContext(ctx, "my message", "key", "value")
+
+	closeHandler()
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&lines))
+}
+
+func TestHandler_Sampler(t *testing.T) {
+	exp := []string{
+		`{"_time":"time","level":"INFO","msg":"my message","dropped_events":2}`,
+		`{"_time":"time","level":"WARN","msg":"my other message","dropped_events":2}`,
+	}
+
+	var lines []string
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			lines = append(lines, s.Text())
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	sampler, err := sampling.New(
+		sampling.SetLevelRate("debug", 0),
+		sampling.SetFirstThereafter(time.Minute, 1, 0),
+	)
+	require.NoError(t, err)
+
+	logger, closeHandler := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
+		t.Helper()
+
+		handler, err := New(
+			SetClient(client),
+			SetDataset(dataset),
+			SetLevel(slog.LevelDebug),
+			SetSampler(sampler),
+		)
+		require.NoError(t, err)
+		t.Cleanup(handler.Close)
+
+		return slog.New(handler), handler.Close
+	})
+
+	logger.Debug("my message")
+	logger.Debug("my message")
+	for range 3 {
+		logger.Info("my message")
+	}
+	logger.Warn("my other message")
+
+	closeHandler()
+
+	if assert.Len(t, lines, len(exp)) {
+		for i := range exp {
+			testhelper.JSONEqExp(t, exp[i], lines[i], []string{ingest.TimestampField})
+		}
+	}
+}
+
 func setup(t *testing.T) func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
 	return func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
 		t.Helper()
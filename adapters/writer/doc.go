@@ -0,0 +1,4 @@
+// Package writer provides an adapter for code that only writes plain text
+// lines to an [io.Writer] or a [log.Logger] of the standard library, e.g.
+// third-party libraries or the output of a subprocess.
+package writer
@@ -0,0 +1,407 @@
+package writer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/internal/ingester"
+)
+
+var _ io.WriteCloser = (*Writer)(nil)
+
+// maxLineLength is the maximum length of a line. Longer lines are split into
+// multiple events.
+const maxLineLength = 1 << 16 // 64KB
+
+// Keys of the fields the writer sets.
+const (
+	messageKey = "msg"
+	prefixKey  = "prefix"
+	callerKey  = "caller"
+)
+
+// ErrMissingDatasetName is raised when a dataset name is not provided. Set it
+// manually using the [SetDataset] option or export "AXIOM_DATASET".
+var ErrMissingDatasetName = errors.New("missing dataset name")
+
+// ErrWriterClosed is returned when writing to a [Writer] that has been closed.
+var ErrWriterClosed = errors.New("writer closed")
+
+// An Option modifies the behaviour of the Axiom writer.
+type Option func(*Writer) error
+
+// SetClient specifies the Axiom client to use for ingesting the logs.
+func SetClient(client *axiom.Client) Option {
+	return func(w *Writer) error {
+		w.client = client
+		return nil
+	}
+}
+
+// SetClientOptions specifies the Axiom client options to pass to
+// [axiom.NewClient] which is only called if no [axiom.Client] was specified by
+// the [SetClient] option.
+func SetClientOptions(options ...axiom.Option) Option {
+	return func(w *Writer) error {
+		w.clientOptions = options
+		return nil
+	}
+}
+
+// SetDataset specifies the dataset to ingest the logs into. Can also be
+// specified using the "AXIOM_DATASET" environment variable.
+func SetDataset(datasetName string) Option {
+	return func(w *Writer) error {
+		w.datasetName = datasetName
+		return nil
+	}
+}
+
+// SetIngestOptions specifies the ingestion options to use for ingesting the
+// logs.
+func SetIngestOptions(opts ...ingest.Option) Option {
+	return func(w *Writer) error {
+		w.ingestOptions = opts
+		return nil
+	}
+}
+
+// SetFields specifies static fields that are added to every event. Fields of
+// JSON lines take precedence over them.
+func SetFields(fields axiom.Event) Option {
+	return func(w *Writer) error {
+		w.fields = maps.Clone(fields)
+		return nil
+	}
+}
+
+// SetPrefix specifies a prefix every line starts with. It is stripped from the
+// message and added to the event as "prefix", if present.
+func SetPrefix(prefix string) Option {
+	return func(w *Writer) error {
+		w.prefix = prefix
+		return nil
+	}
+}
+
+// SetTimestampLayout specifies the layout of a timestamp every line starts
+// with, e.g. [time.RFC3339]. If a line starts with a timestamp in the given
+// layout, it is stripped from the message and used as the time of the event.
+// Timestamps without a time zone are parsed in the local time zone.
+func SetTimestampLayout(layout string) Option {
+	return func(w *Writer) error {
+		w.timestampLayout = layout
+		return nil
+	}
+}
+
+// SetLogFlags configures the writer to parse lines written by a [log.Logger]
+// with the given flags, e.g. [log.LstdFlags]: The date and time are used as the
+// time of the event, the file name and line number are added as "caller". Use
+// [Writer.Logger] to create a [log.Logger] with the prefix and flags of the
+// writer.
+func SetLogFlags(flags int) Option {
+	return func(w *Writer) error {
+		w.logFlags = flags
+		return nil
+	}
+}
+
+type rootWriter struct {
+	client      *axiom.Client
+	datasetName string
+
+	clientOptions []axiom.Option
+	ingestOptions []ingest.Option
+
+	ingester *ingester.Ingester
+
+	// mu guards writers and closed.
+	mu      sync.Mutex
+	writers map[*Writer]struct{}
+	closed  bool
+}
+
+// Writer implements an [io.Writer] used for shipping lines of text to Axiom.
+// Every line becomes an event carrying the line as "msg". Lines holding a JSON
+// object are detected and their fields are merged into the event instead.
+type Writer struct {
+	*rootWriter
+
+	fields          axiom.Event
+	prefix          string
+	timestampLayout string
+	logFlags        int
+	derived         bool
+
+	// mu guards buf and closed.
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+}
+
+// New creates a new writer that ingests lines into Axiom. It automatically
+// takes its configuration from the environment. To connect, export the
+// following environment variables:
+//
+//   - AXIOM_TOKEN
+//   - AXIOM_ORG_ID (only when using a personal token)
+//   - AXIOM_DATASET
+//
+// The configuration can be set manually using options which are prefixed with
+// "Set".
+//
+// An API token with "ingest" permission is sufficient enough.
+//
+// A writer needs to be closed properly to make sure all lines are sent by
+// calling [Writer.Close].
+func New(options ...Option) (*Writer, error) {
+	w := &Writer{
+		rootWriter: &rootWriter{
+			writers: make(map[*Writer]struct{}),
+		},
+	}
+
+	// Apply supplied options.
+	for _, option := range options {
+		if option == nil {
+			continue
+		} else if err := option(w); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create client, if not set.
+	if w.client == nil {
+		var err error
+		if w.client, err = axiom.NewClient(w.clientOptions...); err != nil {
+			return nil, err
+		}
+	}
+
+	// When the dataset name is not set, use "AXIOM_DATASET".
+	if w.datasetName == "" {
+		w.datasetName = os.Getenv("AXIOM_DATASET")
+		if w.datasetName == "" {
+			return nil, ErrMissingDatasetName
+		}
+	}
+
+	// Run background ingest.
+	w.ingester = ingester.New(w.client, w.datasetName, "[AXIOM|WRITER]", w.ingestOptions...)
+
+	return w, nil
+}
+
+// With returns a new writer that adds the given static fields to every event,
+// in addition to the ones of the writer. It shares the background ingestion
+// with the writer but buffers incomplete lines on its own, e.g. to write the
+// stdout and stderr of a subprocess to different writers. Its incomplete line
+// is ingested when it or the writer returned by [New] is closed.
+func (w *Writer) With(fields axiom.Event) *Writer {
+	w2 := &Writer{
+		rootWriter: w.rootWriter,
+
+		fields:          make(axiom.Event, len(w.fields)+len(fields)),
+		prefix:          w.prefix,
+		timestampLayout: w.timestampLayout,
+		logFlags:        w.logFlags,
+		derived:         true,
+	}
+	maps.Copy(w2.fields, w.fields)
+	maps.Copy(w2.fields, fields)
+
+	w.rootWriter.mu.Lock()
+	if w.rootWriter.closed {
+		w2.closed = true
+	} else {
+		w.writers[w2] = struct{}{}
+	}
+	w.rootWriter.mu.Unlock()
+
+	return w2
+}
+
+// Logger returns a [log.Logger] that writes to the writer, using the prefix
+// and flags of the writer.
+func (w *Writer) Logger() *log.Logger {
+	return log.New(w, w.prefix, w.logFlags)
+}
+
+// Write implements [io.Writer]. Every complete line is ingested as an event.
+// Incomplete lines are buffered until they are completed or the writer is
+// closed. Writing to a closed writer returns [ErrWriterClosed].
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadBytes('\n')
+		if err != nil {
+			// Put back the incomplete line, unless it is too long.
+			if len(line) >= maxLineLength {
+				w.ingestLine(line)
+			} else {
+				w.buf.Write(line)
+			}
+			break
+		}
+		w.ingestLine(line)
+	}
+
+	return len(p), nil
+}
+
+// Close ingests any incomplete line and renders the writer unusable for
+// further use. Closing the writer returned by [New] also closes all writers
+// created by [Writer.With] and makes sure all events are flushed. Closing a
+// writer created by [Writer.With] leaves the other writers usable.
+func (w *Writer) Close() error {
+	w.rootWriter.mu.Lock()
+	if w.derived {
+		delete(w.writers, w)
+		w.rootWriter.mu.Unlock()
+
+		w.close()
+		return nil
+	}
+
+	// Flush the incomplete lines of all writers before the ingester stops.
+	w.rootWriter.closed = true
+	w.close()
+	for w2 := range w.writers {
+		w2.close()
+	}
+	clear(w.writers)
+	w.rootWriter.mu.Unlock()
+
+	w.ingester.Close()
+
+	return nil
+}
+
+// close ingests the incomplete line and marks the writer as closed.
+func (w *Writer) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.closed {
+		w.closed = true
+		w.ingestLine(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+func (w *Writer) ingestLine(b []byte) {
+	line := strings.TrimRight(string(b), "\r\n")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	event := make(axiom.Event, len(w.fields)+4)
+	maps.Copy(event, w.fields)
+
+	msg := w.parse(event, line)
+
+	// Merge the fields of JSON lines, use plain lines as the message.
+	var fields axiom.Event
+	if trimmed := strings.TrimSpace(msg); strings.HasPrefix(trimmed, "{") &&
+		json.Unmarshal([]byte(trimmed), &fields) == nil {
+		maps.Copy(event, fields)
+	} else {
+		event[messageKey] = msg
+	}
+
+	if _, ok := event[ingest.TimestampField]; !ok {
+		event[ingest.TimestampField] = time.Now().Format(time.RFC3339Nano)
+	}
+
+	_ = w.ingester.Ingest(event)
+}
+
+// parse strips the prefix, timestamp and caller off the line, adds them to the
+// event and returns the remaining message.
+func (w *Writer) parse(event axiom.Event, line string) string {
+	msgPrefix := w.logFlags&log.Lmsgprefix != 0
+
+	if w.prefix != "" && !msgPrefix {
+		line = w.cutPrefix(event, line)
+	}
+
+	if layout := w.layout(); layout != "" {
+		loc := time.Local
+		if w.logFlags&log.LUTC != 0 {
+			loc = time.UTC
+		}
+
+		// The timestamp spans as many space separated fields as the layout.
+		n := strings.Count(layout, " ") + 1
+		fields := strings.SplitN(line, " ", n+1)
+		if len(fields) >= n {
+			if ts, err := time.ParseInLocation(layout, strings.Join(fields[:n], " "), loc); err == nil {
+				// Timestamps without a date are from today.
+				if ts.Year() == 0 {
+					now := time.Now().In(loc)
+					ts = ts.AddDate(now.Year(), int(now.Month())-1, now.Day()-1)
+				}
+				event[ingest.TimestampField] = ts.Format(time.RFC3339Nano)
+				line = strings.Join(fields[n:], " ")
+			}
+		}
+	}
+
+	if w.logFlags&(log.Lshortfile|log.Llongfile) != 0 {
+		if caller, rest, ok := strings.Cut(line, ": "); ok {
+			event[callerKey] = caller
+			line = rest
+		}
+	}
+
+	if w.prefix != "" && msgPrefix {
+		line = w.cutPrefix(event, line)
+	}
+
+	return line
+}
+
+func (w *Writer) cutPrefix(event axiom.Event, line string) string {
+	if rest, ok := strings.CutPrefix(line, w.prefix); ok {
+		event[prefixKey] = strings.TrimSpace(w.prefix)
+		return rest
+	}
+	return line
+}
+
+// layout returns the layout of the timestamp lines start with, if any. An
+// explicitly configured layout takes precedence over the log flags.
+func (w *Writer) layout() string {
+	if w.timestampLayout != "" {
+		return w.timestampLayout
+	}
+
+	var layout []string
+	if w.logFlags&log.Ldate != 0 {
+		layout = append(layout, "2006/01/02")
+	}
+	if w.logFlags&log.Lmicroseconds != 0 {
+		layout = append(layout, "15:04:05.000000")
+	} else if w.logFlags&log.Ltime != 0 {
+		layout = append(layout, "15:04:05")
+	}
+	return strings.Join(layout, " ")
+}
@@ -0,0 +1,30 @@
+package writer_test
+
+import (
+	"log"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/writer"
+)
+
+func Example() {
+	// Export "AXIOM_DATASET" in addition to the required environment variables.
+
+	w, err := adapter.New(
+		adapter.SetPrefix("[legacy] "),
+		adapter.SetLogFlags(log.LstdFlags),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if closeErr := w.Close(); closeErr != nil {
+			log.Fatal(closeErr)
+		}
+	}()
+
+	logger := w.Logger()
+
+	logger.Println("This is awesome!")
+	logger.Println(`{"msg":"This is not that awesome...","mood":"worried"}`)
+	logger.Println("This is rather bad.")
+}
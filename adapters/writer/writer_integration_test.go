@@ -0,0 +1,35 @@
+package writer_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/writer"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+)
+
+func Test(t *testing.T) {
+	adapters.IntegrationTest(t, "writer", func(_ context.Context, dataset string, client *axiom.Client) {
+		w, err := adapter.New(
+			adapter.SetClient(client),
+			adapter.SetDataset(dataset),
+		)
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, w.Close())
+		}()
+
+		_, err = io.WriteString(w, "This is awesome!\n")
+		require.NoError(t, err)
+		_, err = io.WriteString(w, `{"msg":"This is not that awesome...","mood":"worried"}`+"\n")
+		require.NoError(t, err)
+		_, err = io.WriteString(w, "This is rather bad.\n")
+		require.NoError(t, err)
+	})
+}
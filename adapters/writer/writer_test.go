@@ -0,0 +1,173 @@
+package writer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+	"github.com/axiomhq/axiom-go/internal/test/testhelper"
+)
+
+// TestNew makes sure New() picks up the "AXIOM_DATASET" environment variable.
+func TestNew(t *testing.T) {
+	testhelper.SafeClearEnv(t)
+
+	t.Setenv("AXIOM_TOKEN", "xaat-test")
+	t.Setenv("AXIOM_ORG_ID", "123")
+
+	w, err := New()
+	require.ErrorIs(t, err, ErrMissingDatasetName)
+	require.Nil(t, w)
+
+	t.Setenv("AXIOM_DATASET", "test")
+
+	w, err = New()
+	require.NoError(t, err)
+	require.NotNil(t, w)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "test", w.datasetName)
+}
+
+func TestWriter(t *testing.T) {
+	now := time.Now().Format(time.RFC3339Nano)
+	exp := []string{
+		fmt.Sprintf(`{"_time":"%s","app":"legacy","msg":"my message"}`, now),
+		fmt.Sprintf(`{"_time":"%s","app":"legacy","stream":"stderr","msg":"a split line"}`, now),
+		`{"_time":"2024-01-01T00:00:00Z","app":"json","level":"info","msg":"json message"}`,
+		fmt.Sprintf(`{"_time":"%s","app":"legacy","msg":"{not json"}`, now),
+		fmt.Sprintf(`{"_time":"%s","app":"legacy","msg":"incomplete"}`, now),
+	}
+
+	events := setup(t, func(w *Writer) {
+		_, _ = io.WriteString(w, "my message\n\n")
+
+		stderr := w.With(axiom.Event{"stream": "stderr"})
+		_, _ = io.WriteString(stderr, "a split ")
+		_, _ = io.WriteString(stderr, "line\r\n")
+
+		_, _ = io.WriteString(w, `{"_time":"2024-01-01T00:00:00Z","app":"json","level":"info","msg":"json message"}`+"\n")
+		_, _ = io.WriteString(w, "{not json\nincomplete")
+	}, SetFields(axiom.Event{"app": "legacy"}))
+
+	require.Len(t, events, len(exp))
+	for i, event := range events {
+		testhelper.JSONEqExp(t, exp[i], event, []string{ingest.TimestampField})
+	}
+	assert.Contains(t, events[2], `"_time":"2024-01-01T00:00:00Z"`)
+}
+
+func TestWriter_Logger(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+
+	exp := `{"_time":"2024-01-02T03:04:05.000006Z","prefix":"[legacy]","caller":"main.go:42","msg":"my message"}`
+
+	events := setup(t, func(w *Writer) {
+		logger := w.Logger()
+		assert.Equal(t, "[legacy] ", logger.Prefix())
+
+		// Write a line as the logger would, but at a fixed time.
+		line := "[legacy] " + ts.Format("2006/01/02 15:04:05.000000") + " main.go:42: my message\n"
+		_, _ = io.WriteString(w, line)
+	}, SetPrefix("[legacy] "), SetLogFlags(log.LstdFlags|log.Lmicroseconds|log.LUTC|log.Lshortfile))
+
+	require.Len(t, events, 1)
+	assert.JSONEq(t, exp, events[0])
+}
+
+func TestWriter_WriteAfterClose(t *testing.T) {
+	events := setup(t, func(w *Writer) {
+		require.NoError(t, w.Close())
+
+		_, err := io.WriteString(w, "my message\n")
+		require.ErrorIs(t, err, ErrWriterClosed)
+	})
+
+	assert.Empty(t, events)
+}
+
+func TestWriter_With_Close(t *testing.T) {
+	exp := []string{
+		`{"stream":"stderr","msg":"closed child"}`,
+		`{"msg":"parent"}`,
+		`{"stream":"stdout","msg":"open child"}`,
+	}
+
+	var stdout, stderr *Writer
+	events := setup(t, func(w *Writer) {
+		stdout = w.With(axiom.Event{"stream": "stdout"})
+		stderr = w.With(axiom.Event{"stream": "stderr"})
+
+		_, _ = io.WriteString(stdout, "open child")
+		_, _ = io.WriteString(stderr, "closed child")
+
+		// Closing a child leaves the parent usable.
+		require.NoError(t, stderr.Close())
+		_, err := io.WriteString(stderr, "more\n")
+		require.ErrorIs(t, err, ErrWriterClosed)
+
+		_, _ = io.WriteString(w, "parent")
+	})
+
+	// Closing the parent flushes and closes the children.
+	_, err := io.WriteString(stdout, "more\n")
+	require.ErrorIs(t, err, ErrWriterClosed)
+	require.NoError(t, stdout.Close())
+
+	require.Len(t, events, len(exp))
+	for i, event := range events {
+		testhelper.JSONEqExp(t, exp[i], event, []string{ingest.TimestampField})
+	}
+}
+
+// setup creates a writer that ingests into a test server, passes it to the
+// given function, closes it and returns the ingested events as JSON.
+func setup(t *testing.T, write func(*Writer), options ...Option) []string {
+	t.Helper()
+
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			mu.Lock()
+			events = append(events, strings.Clone(s.Text()))
+			mu.Unlock()
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	client := adapters.SetupClient(t, hf)
+
+	w, err := New(append([]Option{SetClient(client), SetDataset("test")}, options...)...)
+	require.NoError(t, err)
+
+	write(w)
+	require.NoError(t, w.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return events
+}
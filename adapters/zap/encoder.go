@@ -0,0 +1,303 @@
+package zap
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+var (
+	_ zapcore.ObjectEncoder = (*objectEncoder)(nil)
+	_ zapcore.ArrayEncoder  = (*arrayEncoder)(nil)
+)
+
+// objectEncoder is a [zapcore.ObjectEncoder] that turns fields directly into
+// an [axiom.Event]. Values are encoded the same way the JSON encoder of the
+// former [zapcore.WriteSyncer] based core did: Times as RFC3339 strings with
+// nanosecond precision, durations as float seconds and binary data as base64.
+type objectEncoder struct {
+	fields axiom.Event
+	// cur is the object fields are currently added to. It differs from fields
+	// once a namespace has been opened.
+	cur axiom.Event
+	// namespaces is the path of namespaces that have been opened.
+	namespaces []string
+}
+
+func newObjectEncoder(fields axiom.Event, namespaces []string) *objectEncoder {
+	enc := &objectEncoder{
+		fields:     fields,
+		cur:        fields,
+		namespaces: namespaces,
+	}
+	for _, ns := range namespaces {
+		nested, ok := enc.cur[ns].(axiom.Event)
+		if !ok {
+			nested = axiom.Event{}
+			enc.cur[ns] = nested
+		}
+		enc.cur = nested
+	}
+	return enc
+}
+
+// AddArray implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddArray(key string, v zapcore.ArrayMarshaler) error {
+	arr := &arrayEncoder{elems: make([]any, 0)}
+	err := v.MarshalLogArray(arr)
+	enc.cur[key] = arr.elems
+	return err
+}
+
+// AddObject implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddObject(key string, v zapcore.ObjectMarshaler) error {
+	obj := newObjectEncoder(axiom.Event{}, nil)
+	err := v.MarshalLogObject(obj)
+	enc.cur[key] = obj.fields
+	return err
+}
+
+// AddBinary implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddBinary(key string, v []byte) {
+	enc.cur[key] = base64.StdEncoding.EncodeToString(v)
+}
+
+// AddByteString implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddByteString(key string, v []byte) { enc.cur[key] = string(v) }
+
+// AddBool implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddBool(key string, v bool) { enc.cur[key] = v }
+
+// AddComplex128 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddComplex128(key string, v complex128) { enc.cur[key] = complexValue(v) }
+
+// AddComplex64 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddComplex64(key string, v complex64) {
+	enc.cur[key] = complexValue(complex128(v))
+}
+
+// AddDuration implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddDuration(key string, v time.Duration) { enc.cur[key] = v.Seconds() }
+
+// AddFloat64 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddFloat64(key string, v float64) { enc.cur[key] = floatValue(v) }
+
+// AddFloat32 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddFloat32(key string, v float32) { enc.cur[key] = floatValue(float64(v)) }
+
+// AddInt implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddInt(key string, v int) { enc.cur[key] = v }
+
+// AddInt64 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddInt64(key string, v int64) { enc.cur[key] = v }
+
+// AddInt32 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddInt32(key string, v int32) { enc.cur[key] = v }
+
+// AddInt16 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddInt16(key string, v int16) { enc.cur[key] = v }
+
+// AddInt8 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddInt8(key string, v int8) { enc.cur[key] = v }
+
+// AddString implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddString(key, v string) { enc.cur[key] = v }
+
+// AddTime implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddTime(key string, v time.Time) {
+	enc.cur[key] = v.Format(time.RFC3339Nano)
+}
+
+// AddUint implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddUint(key string, v uint) { enc.cur[key] = v }
+
+// AddUint64 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddUint64(key string, v uint64) { enc.cur[key] = v }
+
+// AddUint32 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddUint32(key string, v uint32) { enc.cur[key] = v }
+
+// AddUint16 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddUint16(key string, v uint16) { enc.cur[key] = v }
+
+// AddUint8 implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddUint8(key string, v uint8) { enc.cur[key] = v }
+
+// AddUintptr implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) AddUintptr(key string, v uintptr) { enc.cur[key] = uint64(v) }
+
+// AddReflected implements [zapcore.ObjectEncoder]. The value is marshaled
+// right away, as it might be modified by the caller after logging.
+func (enc *objectEncoder) AddReflected(key string, v any) error {
+	b, err := reflectedValue(v)
+	if err != nil {
+		return err
+	}
+	enc.cur[key] = b
+	return nil
+}
+
+// OpenNamespace implements [zapcore.ObjectEncoder].
+func (enc *objectEncoder) OpenNamespace(key string) {
+	ns := axiom.Event{}
+	enc.cur[key] = ns
+	enc.cur = ns
+	enc.namespaces = append(enc.namespaces, key)
+}
+
+// arrayEncoder is a [zapcore.ArrayEncoder] that collects the elements of an
+// array. It encodes values the same way [objectEncoder] does.
+type arrayEncoder struct {
+	elems []any
+}
+
+// AppendArray implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendArray(v zapcore.ArrayMarshaler) error {
+	arr := &arrayEncoder{elems: make([]any, 0)}
+	err := v.MarshalLogArray(arr)
+	enc.elems = append(enc.elems, arr.elems)
+	return err
+}
+
+// AppendObject implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendObject(v zapcore.ObjectMarshaler) error {
+	obj := newObjectEncoder(axiom.Event{}, nil)
+	err := v.MarshalLogObject(obj)
+	enc.elems = append(enc.elems, obj.fields)
+	return err
+}
+
+// AppendReflected implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendReflected(v any) error {
+	b, err := reflectedValue(v)
+	if err != nil {
+		return err
+	}
+	enc.elems = append(enc.elems, b)
+	return nil
+}
+
+// AppendBool implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendBool(v bool) { enc.elems = append(enc.elems, v) }
+
+// AppendByteString implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendByteString(v []byte) { enc.elems = append(enc.elems, string(v)) }
+
+// AppendComplex128 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendComplex128(v complex128) {
+	enc.elems = append(enc.elems, complexValue(v))
+}
+
+// AppendComplex64 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendComplex64(v complex64) {
+	enc.elems = append(enc.elems, complexValue(complex128(v)))
+}
+
+// AppendDuration implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendDuration(v time.Duration) {
+	enc.elems = append(enc.elems, v.Seconds())
+}
+
+// AppendFloat64 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendFloat64(v float64) { enc.elems = append(enc.elems, floatValue(v)) }
+
+// AppendFloat32 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendFloat32(v float32) {
+	enc.elems = append(enc.elems, floatValue(float64(v)))
+}
+
+// AppendInt implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendInt(v int) { enc.elems = append(enc.elems, v) }
+
+// AppendInt64 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendInt64(v int64) { enc.elems = append(enc.elems, v) }
+
+// AppendInt32 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendInt32(v int32) { enc.elems = append(enc.elems, v) }
+
+// AppendInt16 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendInt16(v int16) { enc.elems = append(enc.elems, v) }
+
+// AppendInt8 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendInt8(v int8) { enc.elems = append(enc.elems, v) }
+
+// AppendString implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendString(v string) { enc.elems = append(enc.elems, v) }
+
+// AppendTime implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendTime(v time.Time) {
+	enc.elems = append(enc.elems, v.Format(time.RFC3339Nano))
+}
+
+// AppendUint implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendUint(v uint) { enc.elems = append(enc.elems, v) }
+
+// AppendUint64 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendUint64(v uint64) { enc.elems = append(enc.elems, v) }
+
+// AppendUint32 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendUint32(v uint32) { enc.elems = append(enc.elems, v) }
+
+// AppendUint16 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendUint16(v uint16) { enc.elems = append(enc.elems, v) }
+
+// AppendUint8 implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendUint8(v uint8) { enc.elems = append(enc.elems, v) }
+
+// AppendUintptr implements [zapcore.ArrayEncoder].
+func (enc *arrayEncoder) AppendUintptr(v uintptr) { enc.elems = append(enc.elems, uint64(v)) }
+
+// complexValue formats a complex number like the zap JSON encoder does, e.g.
+// "1+2i".
+func complexValue(v complex128) string {
+	r, i := real(v), imag(v)
+	s := strconv.FormatFloat(r, 'f', -1, 64)
+	if i >= 0 || math.IsNaN(i) {
+		s += "+"
+	}
+	return s + strconv.FormatFloat(i, 'f', -1, 64) + "i"
+}
+
+// floatValue returns the float as is, unless it is not representable in JSON,
+// in which case it is formatted like the zap JSON encoder does.
+func floatValue(v float64) any {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return v
+}
+
+func reflectedValue(v any) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+// cloneEvent returns a deep copy of the event and all objects nested in it.
+// Arrays are not copied, as they are never modified after being encoded.
+func cloneEvent(event axiom.Event) axiom.Event {
+	clone := make(axiom.Event, len(event))
+	for k, v := range event {
+		if nested, ok := v.(axiom.Event); ok {
+			v = cloneEvent(nested)
+		}
+		clone[k] = v
+	}
+	return clone
+}
@@ -1,10 +1,10 @@
 package zap
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"os"
 	"sync"
 	"time"
@@ -12,42 +12,44 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/axiomhq/axiom-go/adapters/sampling"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 )
 
-var _ zapcore.WriteSyncer = (*WriteSyncer)(nil)
+var _ zapcore.Core = (*Core)(nil)
 
-const defaultMaxBufferCapacity = 1 << 20 // 1MB
+const (
+	defaultBufferSize  = 10_000
+	defaultSyncTimeout = time.Second * 15
+	flushInterval      = time.Second
+)
 
-// Matches https://github.com/uber-go/zap/blob/master/config.go#L98 but modifies
-// the timestamp field to be Axiom compatible.
-var encoderConfig = zapcore.EncoderConfig{
-	TimeKey:        ingest.TimestampField, // Modified
-	LevelKey:       "level",
-	NameKey:        "logger",
-	CallerKey:      "caller",
-	FunctionKey:    zapcore.OmitKey,
-	MessageKey:     "msg",
-	StacktraceKey:  "stacktrace",
-	LineEnding:     zapcore.DefaultLineEnding,
-	EncodeLevel:    zapcore.LowercaseLevelEncoder,
-	EncodeTime:     zapcore.RFC3339NanoTimeEncoder, // Modified
-	EncodeDuration: zapcore.SecondsDurationEncoder,
-	EncodeCaller:   zapcore.ShortCallerEncoder,
-}
+// Keys of the fields every event carries. They match the production
+// configuration of zap but use the Axiom timestamp field.
+const (
+	levelKey      = "level"
+	nameKey       = "logger"
+	callerKey     = "caller"
+	messageKey    = "msg"
+	stacktraceKey = "stacktrace"
+)
 
 // ErrMissingDatasetName is raised when a dataset name is not provided. Set it
 // manually using the [SetDataset] option or export "AXIOM_DATASET".
 var ErrMissingDatasetName = errors.New("missing dataset name")
 
-// An Option modifies the behaviour of the Axiom WriteSyncer.
-type Option func(*WriteSyncer) error
+// ErrCoreClosed is returned when writing to or syncing a [Core] that has been
+// closed.
+var ErrCoreClosed = errors.New("core closed")
+
+// An Option modifies the behaviour of the Axiom core.
+type Option func(*Core) error
 
 // SetClient specifies the Axiom client to use for ingesting the logs.
 func SetClient(client *axiom.Client) Option {
-	return func(ws *WriteSyncer) error {
-		ws.client = client
+	return func(c *Core) error {
+		c.client = client
 		return nil
 	}
 }
@@ -56,8 +58,8 @@ func SetClient(client *axiom.Client) Option {
 // [axiom.NewClient] which is only called if no [axiom.Client] was specified by
 // the [SetClient] option.
 func SetClientOptions(options ...axiom.Option) Option {
-	return func(ws *WriteSyncer) error {
-		ws.clientOptions = options
+	return func(c *Core) error {
+		c.clientOptions = options
 		return nil
 	}
 }
@@ -65,8 +67,8 @@ func SetClientOptions(options ...axiom.Option) Option {
 // SetDataset specifies the dataset to ingest the logs into. Can also be
 // specified using the "AXIOM_DATASET" environment variable.
 func SetDataset(datasetName string) Option {
-	return func(ws *WriteSyncer) error {
-		ws.datasetName = datasetName
+	return func(c *Core) error {
+		c.datasetName = datasetName
 		return nil
 	}
 }
@@ -74,52 +76,119 @@ func SetDataset(datasetName string) Option {
 // SetIngestOptions specifies the ingestion options to use for ingesting the
 // logs.
 func SetIngestOptions(opts ...ingest.Option) Option {
-	return func(ws *WriteSyncer) error {
-		ws.ingestOptions = opts
+	return func(c *Core) error {
+		c.ingestOptions = opts
 		return nil
 	}
 }
 
-// SetLevelEnabler sets the level enabler that the Axiom [WriteSyncer] will us
-// to determine if logs will be shipped to Axiom.
+// SetLevelEnabler sets the level enabler that the Axiom [Core] will use to
+// determine if logs will be shipped to Axiom.
 func SetLevelEnabler(levelEnabler zapcore.LevelEnabler) Option {
-	return func(ws *WriteSyncer) error {
-		ws.levelEnabler = levelEnabler
+	return func(c *Core) error {
+		c.levelEnabler = levelEnabler
 		return nil
 	}
 }
 
-// SetMaxBufferCapacity configures the maximum buffer capacity in bytes. Buffers
-// exceeding this capacity are released after syncing to prevent memory bloat
-// from traffic spikes. Set to 0 to always release the buffer. Defaults to 1MB.
+// SetBufferSize configures the maximum number of events buffered before they
+// are ingested. Once the buffer is full, logging blocks until events have been
+// ingested, which bounds the memory used by the core. Defaults to 10000.
+func SetBufferSize(size int) Option {
+	return func(c *Core) error {
+		if size <= 0 {
+			return errors.New("buffer size must be greater than 0")
+		}
+		c.bufferSize = size
+		return nil
+	}
+}
+
+// SetMaxBufferCapacity configures the maximum buffer capacity in bytes.
+//
+// Deprecated: The core no longer buffers encoded logs. Use [SetBufferSize] to
+// bound the number of buffered events instead.
 func SetMaxBufferCapacity(size int) Option {
-	return func(ws *WriteSyncer) error {
+	return func(*Core) error {
 		if size < 0 {
 			return errors.New("max buffer capacity cannot be negative")
 		}
-		ws.maxBufferCapacity = size
 		return nil
 	}
 }
 
-// WriteSyncer implements a [zapcore.WriteSyncer] used for shipping logs to
-// Axiom.
-type WriteSyncer struct {
+// SetSyncTimeout configures how long [Core.Sync] waits for the buffered events
+// to be ingested before giving up. Events not ingested by then are ingested in
+// the background. Defaults to 15 seconds.
+func SetSyncTimeout(timeout time.Duration) Option {
+	return func(c *Core) error {
+		if timeout <= 0 {
+			return errors.New("sync timeout must be greater than 0")
+		}
+		c.syncTimeout = timeout
+		return nil
+	}
+}
+
+// SetSampler specifies the sampler that decides which entries are ingested.
+// Entries are sampled by their level and message.
+func SetSampler(sampler *sampling.Sampler) Option {
+	return func(c *Core) error {
+		c.sampler = sampler
+		return nil
+	}
+}
+
+// syncRequest asks the background ingestion to ingest all buffered events.
+type syncRequest struct {
+	ctx  context.Context
+	done chan error
+}
+
+type rootCore struct {
 	client      *axiom.Client
 	datasetName string
 
-	clientOptions     []axiom.Option
-	ingestOptions     []ingest.Option
-	levelEnabler      zapcore.LevelEnabler
-	maxBufferCapacity int
+	clientOptions []axiom.Option
+	ingestOptions []ingest.Option
+	levelEnabler  zapcore.LevelEnabler
+	bufferSize    int
+	syncTimeout   time.Duration
+	sampler       *sampling.Sampler
+
+	eventCh chan axiom.Event
+	syncCh  chan syncRequest
+	closeCh chan struct{}
+	logger  *log.Logger
+
+	// mu guards closed and makes sure no event is sent on the closed event
+	// channel.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// Core implements a [zapcore.Core] used for shipping logs to Axiom. It turns
+// fields directly into events, without encoding them as JSON first, and
+// ingests them asynchronously in batches.
+type Core struct {
+	*rootCore
 
-	buf    bytes.Buffer
-	bufMtx sync.Mutex
+	// context holds the fields added by [Core.With], nested in the namespaces
+	// opened by them.
+	context    axiom.Event
+	namespaces []string
 }
 
-// New creates a new [zapcore.Core] that ingests logs into Axiom. It
-// automatically takes its configuration from the environment. To connect,
-// export the following environment variables:
+// WriteSyncer is the former name of [Core]. The adapter used to encode logs
+// as JSON and ship them from a [zapcore.WriteSyncer], which the native [Core]
+// replaced. The alias keeps options written against it working.
+//
+// Deprecated: Use [Core] instead.
+type WriteSyncer = Core
+
+// New creates a new [Core] that ingests logs into Axiom. It automatically
+// takes its configuration from the environment. To connect, export the
+// following environment variables:
 //
 //   - AXIOM_TOKEN
 //   - AXIOM_ORG_ID (only when using a personal token)
@@ -129,78 +198,256 @@ type WriteSyncer struct {
 // "Set".
 //
 // An API token with "ingest" permission is sufficient enough.
-func New(options ...Option) (zapcore.Core, error) {
-	ws := &WriteSyncer{
+//
+// Buffered events are ingested in the background. Call [Core.Sync], usually
+// by calling Sync on the [zap.Logger] before the application exits, to make
+// sure all events are sent. [Core.Close] additionally stops the background
+// ingestion.
+func New(options ...Option) (*Core, error) {
+	root := &rootCore{
 		levelEnabler: zap.LevelEnablerFunc(func(zapcore.Level) bool {
 			return true
 		}),
-		maxBufferCapacity: defaultMaxBufferCapacity,
+		bufferSize:  defaultBufferSize,
+		syncTimeout: defaultSyncTimeout,
+		logger:      log.New(os.Stderr, "[AXIOM|ZAP]", 0),
+	}
+
+	core := &Core{
+		rootCore: root,
+		context:  axiom.Event{},
 	}
 
 	// Apply supplied options.
 	for _, option := range options {
 		if option == nil {
 			continue
-		} else if err := option(ws); err != nil {
+		} else if err := option(core); err != nil {
 			return nil, err
 		}
 	}
 
 	// Create client, if not set.
-	if ws.client == nil {
+	if root.client == nil {
 		var err error
-		if ws.client, err = axiom.NewClient(ws.clientOptions...); err != nil {
+		if root.client, err = axiom.NewClient(root.clientOptions...); err != nil {
 			return nil, err
 		}
 	}
 
 	// When the dataset name is not set, use "AXIOM_DATASET".
-	if ws.datasetName == "" {
-		if ws.datasetName = os.Getenv("AXIOM_DATASET"); ws.datasetName == "" {
+	if root.datasetName == "" {
+		if root.datasetName = os.Getenv("AXIOM_DATASET"); root.datasetName == "" {
 			return nil, ErrMissingDatasetName
 		}
 	}
 
-	enc := zapcore.NewJSONEncoder(encoderConfig)
+	// Run background ingest.
+	root.eventCh = make(chan axiom.Event, root.bufferSize)
+	root.syncCh = make(chan syncRequest)
+	root.closeCh = make(chan struct{})
+	go root.run()
 
-	return zapcore.NewCore(enc, ws, ws.levelEnabler), nil
+	return core, nil
 }
 
-// Write implements [zapcore.WriteSyncer].
-func (ws *WriteSyncer) Write(p []byte) (n int, err error) {
-	ws.bufMtx.Lock()
-	defer ws.bufMtx.Unlock()
+// Enabled implements [zapcore.Core].
+func (c *Core) Enabled(level zapcore.Level) bool {
+	return c.levelEnabler.Enabled(level)
+}
 
-	return ws.buf.Write(p)
+// Level returns the minimum enabled log level of the core.
+func (c *Core) Level() zapcore.Level {
+	return zapcore.LevelOf(c.levelEnabler)
 }
 
-// Sync implements [zapcore.WriteSyncer].
-func (ws *WriteSyncer) Sync() error {
-	// Best effort context timeout. A sync should never take that long.
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
-	defer cancel()
+// With implements [zapcore.Core]. The fields are encoded once, when calling
+// With, not every time an entry is written.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	if len(fields) == 0 {
+		return c
+	}
 
-	ws.bufMtx.Lock()
-	defer ws.bufMtx.Unlock()
+	enc := newObjectEncoder(cloneEvent(c.context), c.namespaces)
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
 
-	if ws.buf.Len() == 0 {
-		return nil
+	return &Core{
+		rootCore:   c.rootCore,
+		context:    enc.fields,
+		namespaces: enc.namespaces,
+	}
+}
+
+// Check implements [zapcore.Core].
+func (c *Core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
 	}
+	return checked
+}
 
-	defer func() {
-		if ws.buf.Cap() > ws.maxBufferCapacity {
-			ws.buf = bytes.Buffer{}
-			return
+// Write implements [zapcore.Core]. The event is buffered and ingested in the
+// background. If the buffer is full, Write blocks until there is room for the
+// event.
+func (c *Core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	var dropped uint64
+	if c.sampler != nil {
+		var ok bool
+		if ok, dropped = c.sampler.Sample(entry.Level.String(), entry.Message); !ok {
+			return nil
 		}
-		ws.buf.Reset()
-	}()
+	}
 
-	r, err := axiom.ZstdEncoder()(&ws.buf)
-	if err != nil {
+	enc := newObjectEncoder(cloneEvent(c.context), c.namespaces)
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	event := enc.fields
+
+	// Fields take precedence over the entry, just as with zaps JSON encoder
+	// where the last occurrence of a key wins.
+	setDefault(event, ingest.TimestampField, entry.Time.Format(time.RFC3339Nano))
+	setDefault(event, levelKey, entry.Level.String())
+	if entry.LoggerName != "" {
+		setDefault(event, nameKey, entry.LoggerName)
+	}
+	if entry.Caller.Defined {
+		setDefault(event, callerKey, entry.Caller.TrimmedPath())
+	}
+	setDefault(event, messageKey, entry.Message)
+	if entry.Stack != "" {
+		setDefault(event, stacktraceKey, entry.Stack)
+	}
+	if dropped > 0 {
+		setDefault(event, sampling.DroppedField, dropped)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrCoreClosed
+	}
+	c.eventCh <- event
+
+	return nil
+}
+
+// Sync implements [zapcore.Core]. It ingests all buffered events and waits for
+// the ingestion to finish, but not longer than the sync timeout configured by
+// [SetSyncTimeout].
+func (c *Core) Sync() error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.syncTimeout)
+	defer cancel()
+
+	req := syncRequest{
+		ctx:  ctx,
+		done: make(chan error, 1),
+	}
+
+	select {
+	case c.syncCh <- req:
+	case <-c.closeCh:
+		return ErrCoreClosed
+	case <-ctx.Done():
+		return fmt.Errorf("sync: %w", ctx.Err())
+	}
+
+	select {
+	case err := <-req.done:
 		return err
+	case <-ctx.Done():
+		return fmt.Errorf("sync: %w", ctx.Err())
+	}
+}
+
+// Close the core and make sure all events are flushed. Closing the core
+// renders it and all cores derived from it by [Core.With] unusable for further
+// use.
+func (c *Core) Close() {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.eventCh)
+	}
+	c.mu.Unlock()
+
+	<-c.closeCh
+}
+
+// run ingests the buffered events in batches, every time the batch is full, a
+// second has passed or a sync is requested.
+func (r *rootCore) run() {
+	defer close(r.closeCh)
+
+	batch := make([]axiom.Event, 0, r.bufferSize)
+
+	flush := func(ctx context.Context) error {
+		if len(batch) == 0 {
+			return nil
+		}
+		defer func() { batch = batch[:0] }()
+		return r.ingest(ctx, batch)
+	}
+
+	// flushWithTimeout flushes the batch independently of any caller, so the
+	// events are not dropped when a sync gives up waiting for them.
+	flushWithTimeout := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), r.syncTimeout)
+		defer cancel()
+
+		return flush(ctx)
+	}
+
+	flushInBackground := func() {
+		if err := flushWithTimeout(); err != nil {
+			r.logger.Printf("failed to ingest events: %s\n", err)
+		}
+	}
+
+	t := time.NewTicker(flushInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case event, ok := <-r.eventCh:
+			if !ok {
+				flushInBackground()
+				return
+			}
+			if batch = append(batch, event); len(batch) >= r.bufferSize {
+				flushInBackground()
+			}
+		case <-t.C:
+			flushInBackground()
+		case req := <-r.syncCh:
+			// Only take the events that were buffered when the sync was
+			// requested, so a constant stream of events can't stall it.
+			var err error
+			for range len(r.eventCh) {
+				event, ok := <-r.eventCh
+				if !ok {
+					break
+				}
+				if batch = append(batch, event); len(batch) >= r.bufferSize {
+					err = errors.Join(err, flushWithTimeout())
+				}
+			}
+			err = errors.Join(err, flushWithTimeout())
+
+			// Nobody reports the error, if the sync stopped waiting for it.
+			if err != nil && req.ctx.Err() != nil {
+				r.logger.Printf("failed to ingest events: %s\n", err)
+			}
+			req.done <- err
+		}
 	}
+}
 
-	res, err := ws.client.Ingest(ctx, ws.datasetName, r, axiom.NDJSON, axiom.Zstd, ws.ingestOptions...)
+func (r *rootCore) ingest(ctx context.Context, events []axiom.Event) error {
+	res, err := r.client.IngestEvents(ctx, r.datasetName, events, r.ingestOptions...)
 	if err != nil {
 		return err
 	} else if res.Failed > 0 {
@@ -208,6 +455,11 @@ func (ws *WriteSyncer) Sync() error {
 		return fmt.Errorf("event at %s failed to ingest: %s",
 			res.Failures[0].Timestamp, res.Failures[0].Error)
 	}
-
 	return nil
 }
+
+func setDefault(event axiom.Event, key string, value any) {
+	if _, ok := event[key]; !ok {
+		event[key] = value
+	}
+}
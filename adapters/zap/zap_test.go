@@ -1,9 +1,13 @@
 package zap
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,7 +15,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
+	"github.com/axiomhq/axiom-go/adapters/sampling"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 	"github.com/axiomhq/axiom-go/internal/test/adapters"
@@ -86,3 +92,248 @@ func TestCore(t *testing.T) {
 
 	assert.True(t, hasRun)
 }
+
+func TestCore_Sampler(t *testing.T) {
+	exp := []string{
+		`{"_time":"time","level":"info","msg":"my message"}`,
+		`{"_time":"time","level":"warn","msg":"my other message","dropped_events":3}`,
+	}
+
+	var lines []string
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			lines = append(lines, s.Text())
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	sampler, err := sampling.New(sampling.SetFirstThereafter(time.Minute, 1, 0))
+	require.NoError(t, err)
+
+	logger, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*zap.Logger, func()) {
+		t.Helper()
+
+		core, err := New(
+			SetClient(client),
+			SetDataset(dataset),
+			SetSampler(sampler),
+		)
+		require.NoError(t, err)
+		t.Cleanup(core.Close)
+
+		return zap.New(core), func() {}
+	})
+
+	for range 4 {
+		logger.Info("my message")
+	}
+	logger.Warn("my other message")
+
+	require.NoError(t, logger.Sync())
+
+	if assert.Len(t, lines, len(exp)) {
+		for i := range exp {
+			testhelper.JSONEqExp(t, exp[i], lines[i], []string{ingest.TimestampField})
+		}
+	}
+}
+
+func TestCore_With(t *testing.T) {
+	exp := fmt.Sprintf(`{"_time":"%s","level":"warn","logger":"test","msg":"my message","service":"api","request":{"id":"abc","duration":1.5,"ratio":"NaN","complex":"1+2i","tags":["a","b"],"user":{"name":"john"},"payload":{"key":"value"}}}`,
+		time.Now().Format(time.RFC3339Nano))
+
+	var lines int
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(zsr)
+		require.NoError(t, err)
+
+		testhelper.JSONEqExp(t, exp, string(b), []string{ingest.TimestampField})
+
+		lines++
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	var core *Core
+	logger, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*zap.Logger, func()) {
+		t.Helper()
+
+		var err error
+		core, err = New(
+			SetClient(client),
+			SetDataset(dataset),
+		)
+		require.NoError(t, err)
+		t.Cleanup(core.Close)
+
+		return zap.New(core), func() {}
+	})
+
+	logger = logger.Named("test").With(
+		zap.String("service", "api"),
+		zap.Namespace("request"),
+		zap.String("id", "abc"),
+	)
+
+	logger.Warn("my message",
+		zap.Duration("duration", 1500*time.Millisecond),
+		zap.Float64("ratio", math.NaN()),
+		zap.Complex128("complex", 1+2i),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.Object("user", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("name", "john")
+			return nil
+		})),
+		zap.Any("payload", map[string]string{"key": "value"}),
+	)
+
+	// The derived logger must not have modified the context of its parent.
+	assert.Empty(t, core.context)
+
+	require.NoError(t, logger.Sync())
+
+	assert.Equal(t, 1, lines)
+}
+
+func TestCore_SyncTimeout(t *testing.T) {
+	release := make(chan struct{})
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*zap.Logger, func()) {
+		t.Helper()
+
+		core, err := New(
+			SetClient(client),
+			SetDataset(dataset),
+			SetSyncTimeout(time.Millisecond*50),
+		)
+		require.NoError(t, err)
+		t.Cleanup(core.Close)
+		t.Cleanup(func() { close(release) })
+
+		return zap.New(core), func() {}
+	})
+
+	logger.Info("my message")
+
+	err := logger.Sync()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// TestCore_SyncTimeout_SlowIngest makes sure events buffered when a sync times
+// out are still ingested and not dropped.
+func TestCore_SyncTimeout_SlowIngest(t *testing.T) {
+	var lines atomic.Uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		var n uint64
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			n++
+		}
+		assert.NoError(t, s.Err())
+
+		// Only count events of requests the client didn't give up on.
+		select {
+		case <-time.After(time.Millisecond * 200):
+			lines.Add(n)
+		case <-r.Context().Done():
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*zap.Logger, func()) {
+		t.Helper()
+
+		core, err := New(
+			SetClient(client),
+			SetDataset(dataset),
+			SetBufferSize(1),
+			SetSyncTimeout(time.Millisecond*300),
+		)
+		require.NoError(t, err)
+		t.Cleanup(core.Close)
+
+		return zap.New(core), func() {}
+	})
+
+	// The first event keeps the core busy ingesting, so the second one is
+	// ingested after the sync has started and only finishes after it timed out.
+	logger.Info("my message")
+	logger.Info("my other message")
+
+	err := logger.Sync()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.Eventually(t, func() bool { return lines.Load() == 2 }, time.Second, time.Millisecond*10)
+}
+
+func TestCore_Close(t *testing.T) {
+	core, err := New(
+		SetClient(adapters.SetupClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte("{}"))
+		})),
+		SetDataset("test"),
+	)
+	require.NoError(t, err)
+
+	core.Close()
+	core.Close() // Must not panic.
+
+	err = core.Write(zapcore.Entry{Message: "my message"}, nil)
+	require.ErrorIs(t, err, ErrCoreClosed)
+	require.ErrorIs(t, core.Sync(), ErrCoreClosed)
+}
+
+// TestNew_WriteSyncerOption makes sure options written against the deprecated
+// WriteSyncer still work.
+func TestNew_WriteSyncerOption(t *testing.T) {
+	var applied bool
+	option := func(ws *WriteSyncer) error {
+		applied = true
+		return nil
+	}
+
+	core, err := New(
+		SetClient(adapters.SetupClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte("{}"))
+		})),
+		SetDataset("test"),
+		option,
+	)
+	require.NoError(t, err)
+	t.Cleanup(core.Close)
+
+	assert.True(t, applied)
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	_, err := New(SetBufferSize(0))
+	require.EqualError(t, err, "buffer size must be greater than 0")
+
+	_, err = New(SetSyncTimeout(0))
+	require.EqualError(t, err, "sync timeout must be greater than 0")
+}
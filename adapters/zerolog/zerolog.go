@@ -3,16 +3,19 @@ package zerolog
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"log"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/buger/jsonparser"
 	"github.com/rs/zerolog"
 
+	"github.com/axiomhq/axiom-go/adapters/sampling"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 )
@@ -43,6 +46,7 @@ type Writer struct {
 	ingestOptions     []ingest.Option
 	levels            map[zerolog.Level]struct{}
 	maxBufferCapacity int
+	sampler           *sampling.Sampler
 
 	byteCh    chan []byte
 	closeOnce sync.Once
@@ -129,6 +133,15 @@ func SetMaxBufferCapacity(size int) Option {
 	}
 }
 
+// SetSampler configures the sampler that decides which events are ingested.
+// Events are sampled by their level and message.
+func SetSampler(sampler *sampling.Sampler) Option {
+	return func(cfg *Writer) error {
+		cfg.sampler = sampler
+		return nil
+	}
+}
+
 // New creates a new Writer that ingests logs into Axiom. It automatically takes
 // its configuration from the environment. To connect, export the following
 // environment variables:
@@ -262,10 +275,32 @@ func (w *Writer) runBackgroundJob() {
 				continue
 			}
 
+			if w.sampler != nil {
+				// A missing message is sampled as an empty one.
+				msg, _ := jsonparser.GetUnsafeString(data, zerolog.MessageFieldName)
+				ok, dropped := w.sampler.Sample(lvlStr, msg)
+				if !ok {
+					continue
+				} else if dropped > 0 {
+					data, _ = jsonparser.Set(data, strconv.AppendUint(nil, dropped, 10), sampling.DroppedField)
+				}
+			}
+
 			counter++
 
 			data, _ = jsonparser.Set(data, loggerName, "logger")
 
+			// The client only processes events it encodes itself, so the
+			// configured processors are run here.
+			if processor := w.client.EventProcessor(w.ingestOptions...); processor != nil {
+				if data, err = processEvent(processor, data); err != nil {
+					logger.Printf("failed to process event: %s\n", err)
+					continue
+				} else if data == nil {
+					continue
+				}
+			}
+
 			buffer.Write(data)
 
 			if counter >= defaultBatchSize {
@@ -280,3 +315,26 @@ func (w *Writer) runBackgroundJob() {
 		}
 	}
 }
+
+// processEvent runs the processor on the JSON encoded event. It returns nil,
+// if the event is dropped.
+func processEvent(processor ingest.Processor, data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var event axiom.Event
+	if err := dec.Decode(&event); err != nil {
+		return nil, err
+	}
+
+	processed := processor.Process(event)
+	if processed == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(processed)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
@@ -15,7 +15,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/axiomhq/axiom-go/adapters/sampling"
 	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/redact"
 	"github.com/axiomhq/axiom-go/internal/test/adapters"
 	"github.com/axiomhq/axiom-go/internal/test/testhelper"
 )
@@ -121,6 +123,96 @@ func TestHook_FlushFullBatch(t *testing.T) {
 	})
 }
 
+func TestHook_Sampler(t *testing.T) {
+	exp := []string{
+		`{"level":"info", "logger":"zerolog", "message":"my message"}`,
+		`{"level":"warn", "logger":"zerolog", "message":"my other message", "dropped_events":3}`,
+	}
+
+	var lines []string
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			lines = append(lines, s.Text())
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	sampler, err := sampling.New(sampling.SetFirstThereafter(time.Minute, 1, 0))
+	require.NoError(t, err)
+
+	client := adapters.SetupClient(t, hf)
+
+	writer, err := New(
+		SetClient(client),
+		SetDataset("test"),
+		SetSampler(sampler),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.New(writer)
+	for range 4 {
+		logger.Info().Msg("my message")
+	}
+	logger.Warn().Msg("my other message")
+
+	writer.Close()
+
+	if assert.Len(t, lines, len(exp)) {
+		for i := range exp {
+			assert.JSONEq(t, exp[i], lines[i])
+		}
+	}
+}
+
+func TestHook_Redactor(t *testing.T) {
+	exp := `{"email":"[REDACTED]", "level":"info", "logger":"zerolog", "message":"my message"}`
+
+	var lines uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			assert.JSONEq(t, exp, s.Text())
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	redactor, err := redact.New(
+		redact.DropFields("password"),
+		redact.DetectSecrets(redact.Email),
+	)
+	require.NoError(t, err)
+
+	client := adapters.SetupClient(t, hf)
+	require.NoError(t, client.Options(axiom.SetRedactor(redactor)))
+
+	writer, err := New(
+		SetClient(client),
+		SetDataset("test"),
+	)
+	require.NoError(t, err)
+
+	logger := zerolog.New(writer)
+	logger.Info().Str("email", "john@example.com").Str("password", "hunter2").Msg("my message")
+
+	writer.Close()
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&lines))
+}
+
 func setup(t *testing.T) func(dataset string, client *axiom.Client) (*zerolog.Logger, func()) {
 	return func(dataset string, client *axiom.Client) (*zerolog.Logger, func()) {
 		t.Helper()
@@ -0,0 +1,17 @@
+// Package axiommock provides mock implementations of the service interfaces
+// of the axiom package, based on [mock.Mock]. They allow code that depends on
+// the narrow interfaces, like [axiom.Ingester] or [axiom.Datasets], to be
+// tested without making HTTP requests:
+//
+//	m := axiommock.NewIngester(t)
+//	m.On("IngestEvents", mock.Anything, "my-dataset", mock.Anything, mock.Anything).
+//		Return(&ingest.Status{Ingested: 1}, nil)
+//
+// Variadic arguments, like options, are passed to [mock.Mock.Called] as a
+// single slice argument.
+//
+// [mock.Mock]: https://pkg.go.dev/github.com/stretchr/testify/mock#Mock
+// [mock.Mock.Called]: https://pkg.go.dev/github.com/stretchr/testify/mock#Mock.Called
+package axiommock
+
+//go:generate go run ../../internal/cmd/mockgen -source=../services.go -output=mocks.go -package=axiommock -import=github.com/axiomhq/axiom-go/axiom
@@ -0,0 +1,47 @@
+package axiommock_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/axiommock"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+func TestIngester(t *testing.T) {
+	m := axiommock.NewIngester(t)
+	m.On("IngestEvents", mock.Anything, "test", []axiom.Event{{"foo": "bar"}}, mock.Anything).
+		Return(&ingest.Status{Ingested: 1}, nil).
+		Once()
+
+	var ingester axiom.Ingester = m
+	status, err := ingester.IngestEvents(t.Context(), "test", []axiom.Event{{"foo": "bar"}}, ingest.SetEventLabel("env", "test"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, status.Ingested)
+}
+
+func TestDatasets(t *testing.T) {
+	errTest := errors.New("test")
+
+	m := axiommock.NewDatasets(t)
+	m.On("Get", mock.Anything, "test").Return(nil, axiom.ErrNotFound)
+	m.On("CreateMapField", mock.Anything, "test", "attributes").Return("attributes", nil)
+	m.On("Delete", mock.Anything, "test").Return(errTest)
+
+	var datasets axiom.Datasets = m
+
+	dataset, err := datasets.Get(t.Context(), "test")
+	require.ErrorIs(t, err, axiom.ErrNotFound)
+	assert.Nil(t, dataset)
+
+	mapField, err := datasets.CreateMapField(t.Context(), "test", "attributes")
+	require.NoError(t, err)
+	assert.Equal(t, "attributes", mapField)
+
+	assert.ErrorIs(t, datasets.Delete(t.Context(), "test"), errTest)
+}
@@ -0,0 +1,924 @@
+package axiomtest
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/axiom/query"
+)
+
+func (s *Server) query(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		APL       string    `json:"apl"`
+		StartTime time.Time `json:"startTime"`
+		EndTime   time.Time `json:"endTime"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	q, err := parseAPL(req.APL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid query: %s", err))
+		return
+	}
+
+	start := time.Now()
+
+	s.mu.RLock()
+	ds, ok := s.datasets[q.dataset]
+	if !ok {
+		s.mu.RUnlock()
+		writeError(w, http.StatusNotFound, fmt.Sprintf("dataset %q not found", q.dataset))
+		return
+	}
+	t := newTable(ds.events, req.StartTime, req.EndTime)
+	s.mu.RUnlock()
+
+	examined := uint64(len(ds.events))
+	for _, op := range q.ops {
+		if err = op.apply(t); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid query: %s", err))
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, aplResponse{
+		Format: "tabular",
+		Tables: []aplTable{t.encode(q.dataset)},
+		Status: aplStatus{
+			ElapsedTime:  time.Since(start).Microseconds(),
+			RowsExamined: examined,
+			RowsMatched:  uint64(len(t.rows)),
+		},
+		DatasetNames: []string{q.dataset},
+	})
+}
+
+type aplResponse struct {
+	Format       string     `json:"format"`
+	Tables       []aplTable `json:"tables"`
+	Status       aplStatus  `json:"status"`
+	DatasetNames []string   `json:"datasetNames"`
+}
+
+type aplTable struct {
+	Name    string         `json:"name"`
+	Sources []query.Source `json:"sources"`
+	Fields  []aplField     `json:"fields"`
+	Order   []query.Order  `json:"order"`
+	Groups  []query.Group  `json:"groups"`
+	Columns [][]any        `json:"columns"`
+}
+
+type aplField struct {
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Aggregation *aplAggregation `json:"agg,omitempty"`
+}
+
+type aplAggregation struct {
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+	Args   []any    `json:"args"`
+}
+
+type aplStatus struct {
+	ElapsedTime  int64  `json:"elapsedTime"`
+	MinCursor    string `json:"minCursor"`
+	MaxCursor    string `json:"maxCursor"`
+	RowsExamined uint64 `json:"rowsExamined"`
+	RowsMatched  uint64 `json:"rowsMatched"`
+}
+
+// table is the intermediate result of a query.
+type table struct {
+	fields []string
+	aggs   map[string]*aplAggregation
+	groups []string
+	order  []query.Order
+	rows   []map[string]any
+}
+
+// newTable creates a table from the events in the time range [start, end).
+// Nested objects are flattened into fields with dotted names.
+func newTable(events []axiom.Event, start, end time.Time) *table {
+	var (
+		t      = &table{}
+		fields = make(map[string]struct{})
+	)
+	for _, event := range events {
+		ts, _ := event[ingest.TimestampField].(time.Time)
+		if (!start.IsZero() && ts.Before(start)) || (!end.IsZero() && !ts.Before(end)) {
+			continue
+		}
+
+		row := make(map[string]any, len(event))
+		flatten(row, "", event)
+		for k := range row {
+			fields[k] = struct{}{}
+		}
+		t.rows = append(t.rows, row)
+	}
+
+	delete(fields, ingest.TimestampField)
+	t.fields = append([]string{ingest.TimestampField}, slices.Sorted(maps.Keys(fields))...)
+
+	return t
+}
+
+func flatten(dst map[string]any, prefix string, m map[string]any) {
+	for k, v := range m {
+		if nested, ok := v.(map[string]any); ok {
+			flatten(dst, prefix+k+".", nested)
+			continue
+		}
+		dst[prefix+k] = v
+	}
+}
+
+func (t *table) encode(dataset string) aplTable {
+	res := aplTable{
+		Name:    "0",
+		Sources: []query.Source{{Name: dataset}},
+		Fields:  make([]aplField, len(t.fields)),
+		Order:   t.order,
+		Groups:  make([]query.Group, len(t.groups)),
+		Columns: make([][]any, len(t.fields)),
+	}
+	if res.Order == nil {
+		res.Order = []query.Order{}
+	}
+	for i, name := range t.groups {
+		res.Groups[i] = query.Group{Name: name}
+	}
+	for i, name := range t.fields {
+		column := make([]any, len(t.rows))
+		for j, row := range t.rows {
+			if ts, ok := row[name].(time.Time); ok {
+				column[j] = ts.Format(time.RFC3339Nano)
+			} else {
+				column[j] = row[name]
+			}
+		}
+		res.Columns[i] = column
+		res.Fields[i] = aplField{
+			Name:        name,
+			Type:        columnType(t.rows, name),
+			Aggregation: t.aggs[name],
+		}
+	}
+	return res
+}
+
+func columnType(rows []map[string]any, name string) string {
+	typ := "unknown"
+	for _, row := range rows {
+		switch v := row[name].(type) {
+		case string:
+			return "string"
+		case bool:
+			return "boolean"
+		case time.Time:
+			return "datetime"
+		case []any:
+			return "array"
+		case float64:
+			if v != math.Trunc(v) {
+				return "float"
+			}
+			typ = "integer"
+		case int, int64, uint64:
+			typ = "integer"
+		}
+	}
+	return typ
+}
+
+// aplQuery is a parsed query in the supported subset of APL.
+type aplQuery struct {
+	dataset string
+	ops     []aplOp
+}
+
+type aplOp interface {
+	apply(t *table) error
+}
+
+type whereOp struct{ expr aplExpr }
+
+func (op whereOp) apply(t *table) error {
+	t.rows = slices.DeleteFunc(t.rows, func(row map[string]any) bool { return !op.expr.eval(row) })
+	return nil
+}
+
+type projectOp struct{ fields []string }
+
+func (op projectOp) apply(t *table) error {
+	for i, row := range t.rows {
+		projected := make(map[string]any, len(op.fields))
+		for _, field := range op.fields {
+			projected[field] = row[field]
+		}
+		t.rows[i] = projected
+	}
+	t.fields = op.fields
+	return nil
+}
+
+type countOp struct{}
+
+func (countOp) apply(t *table) error {
+	t.rows = []map[string]any{{"Count": float64(len(t.rows))}}
+	t.fields = []string{"Count"}
+	t.groups, t.aggs, t.order = nil, nil, nil
+	return nil
+}
+
+type aggregation struct {
+	name  string
+	fn    string
+	field string
+}
+
+type summarizeOp struct {
+	aggs []aggregation
+	by   []string
+}
+
+func (op summarizeOp) apply(t *table) error {
+	type group struct {
+		key  map[string]any
+		rows []map[string]any
+	}
+
+	var (
+		groups []*group
+		index  = make(map[string]*group)
+	)
+	for _, row := range t.rows {
+		key := make(map[string]any, len(op.by))
+		for _, field := range op.by {
+			key[field] = row[field]
+		}
+		b, _ := json.Marshal(key)
+		g, ok := index[string(b)]
+		if !ok {
+			g = &group{key: key}
+			index[string(b)] = g
+			groups = append(groups, g)
+		}
+		g.rows = append(g.rows, row)
+	}
+	// Without grouping, there is always exactly one result row.
+	if len(op.by) == 0 && len(groups) == 0 {
+		groups = append(groups, &group{key: map[string]any{}})
+	}
+
+	t.rows = make([]map[string]any, len(groups))
+	for i, g := range groups {
+		row := g.key
+		for _, agg := range op.aggs {
+			row[agg.name] = agg.compute(g.rows)
+		}
+		t.rows[i] = row
+	}
+
+	t.fields = slices.Clone(op.by)
+	t.groups = op.by
+	t.aggs = make(map[string]*aplAggregation, len(op.aggs))
+	for _, agg := range op.aggs {
+		t.fields = append(t.fields, agg.name)
+		a := &aplAggregation{Name: agg.fn, Fields: []string{}, Args: []any{}}
+		if agg.field != "" {
+			a.Fields = []string{agg.field}
+		}
+		if a.Name == "dcount" {
+			a.Name = query.OpDistinct.String()
+		}
+		t.aggs[agg.name] = a
+	}
+	t.order = nil
+	return nil
+}
+
+func (agg aggregation) compute(rows []map[string]any) any {
+	switch agg.fn {
+	case "count":
+		return float64(len(rows))
+	case "dcount":
+		distinct := make(map[string]struct{})
+		for _, row := range rows {
+			if v, ok := row[agg.field]; ok && v != nil {
+				distinct[fmt.Sprint(v)] = struct{}{}
+			}
+		}
+		return float64(len(distinct))
+	case "min", "max":
+		var res any
+		for _, row := range rows {
+			v := row[agg.field]
+			if v == nil {
+				continue
+			}
+			if c, ok := compareValues(v, res); res == nil || (ok && (c < 0) == (agg.fn == "min") && c != 0) {
+				res = v
+			}
+		}
+		return res
+	}
+
+	// Sum and average.
+	var (
+		sum float64
+		n   int
+	)
+	for _, row := range rows {
+		if f, ok := toFloat(row[agg.field]); ok {
+			sum += f
+			n++
+		}
+	}
+	if agg.fn == "avg" {
+		if n == 0 {
+			return nil
+		}
+		return sum / float64(n)
+	}
+	return sum
+}
+
+type orderKey struct {
+	field string
+	desc  bool
+}
+
+type orderOp struct{ keys []orderKey }
+
+func (op orderOp) apply(t *table) error {
+	slices.SortStableFunc(t.rows, func(a, b map[string]any) int {
+		for _, key := range op.keys {
+			c := compareOrder(a[key.field], b[key.field])
+			if key.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	t.order = make([]query.Order, len(op.keys))
+	for i, key := range op.keys {
+		t.order[i] = query.Order{Field: key.field, Desc: key.desc}
+	}
+	return nil
+}
+
+type takeOp struct{ n int }
+
+func (op takeOp) apply(t *table) error {
+	if len(t.rows) > op.n {
+		t.rows = t.rows[:op.n]
+	}
+	return nil
+}
+
+type aplExpr interface {
+	eval(row map[string]any) bool
+}
+
+type andExpr struct{ left, right aplExpr }
+
+func (e andExpr) eval(row map[string]any) bool { return e.left.eval(row) && e.right.eval(row) }
+
+type orExpr struct{ left, right aplExpr }
+
+func (e orExpr) eval(row map[string]any) bool { return e.left.eval(row) || e.right.eval(row) }
+
+type notExpr struct{ expr aplExpr }
+
+func (e notExpr) eval(row map[string]any) bool { return !e.expr.eval(row) }
+
+type emptyExpr struct {
+	field string
+	empty bool
+}
+
+func (e emptyExpr) eval(row map[string]any) bool {
+	v := row[e.field]
+	return (v == nil || v == "") == e.empty
+}
+
+type cmpExpr struct {
+	field string
+	op    string
+	value any
+}
+
+func (e cmpExpr) eval(row map[string]any) bool {
+	v := row[e.field]
+	switch e.op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		c, ok := compareValues(v, e.value)
+		switch e.op {
+		case "==":
+			return ok && c == 0
+		case "!=":
+			return !ok || c != 0
+		case "<":
+			return ok && c < 0
+		case "<=":
+			return ok && c <= 0
+		case ">":
+			return ok && c > 0
+		case ">=":
+			return ok && c >= 0
+		}
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		if v == nil {
+			return strings.HasPrefix(e.op, "!")
+		}
+		s = fmt.Sprint(v)
+	}
+	value := fmt.Sprint(e.value)
+	switch e.op {
+	case "=~":
+		return strings.EqualFold(s, value)
+	case "!~":
+		return !strings.EqualFold(s, value)
+	case "contains":
+		return strings.Contains(strings.ToLower(s), strings.ToLower(value))
+	case "!contains":
+		return !strings.Contains(strings.ToLower(s), strings.ToLower(value))
+	case "contains_cs":
+		return strings.Contains(s, value)
+	case "startswith":
+		return strings.HasPrefix(strings.ToLower(s), strings.ToLower(value))
+	case "endswith":
+		return strings.HasSuffix(strings.ToLower(s), strings.ToLower(value))
+	}
+	return false
+}
+
+// compareValues compares two values of compatible types. Strings are compared
+// to timestamps by parsing them. It returns false, if the values can't be
+// compared.
+func compareValues(a, b any) (int, bool) {
+	if a == nil || b == nil {
+		return 0, a == nil && b == nil
+	}
+
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return cmp.Compare(fa, fb), ok
+	}
+
+	if ta, ok := toTime(a); ok {
+		tb, ok := toTime(b)
+		return ta.Compare(tb), ok
+	}
+
+	switch a := a.(type) {
+	case string:
+		if tb, ok := b.(time.Time); ok {
+			ta, ok := toTime(a)
+			return ta.Compare(tb), ok
+		}
+		sb, ok := b.(string)
+		return strings.Compare(a, sb), ok
+	case bool:
+		bb, ok := b.(bool)
+		if !ok || a == bb {
+			return 0, ok
+		} else if a {
+			return 1, true
+		}
+		return -1, true
+	}
+	return 0, false
+}
+
+// compareOrder orders values, with missing and incomparable values first.
+func compareOrder(a, b any) int {
+	if c, ok := compareValues(a, b); ok {
+		return c
+	} else if a == nil {
+		return -1
+	} else if b == nil {
+		return 1
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toTime(v any) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// parseAPL parses a query of the form "['dataset'] | op | op ...".
+func parseAPL(apl string) (*aplQuery, error) {
+	tokens, err := lexAPL(apl)
+	if err != nil {
+		return nil, err
+	}
+	p := &aplParser{tokens: tokens}
+
+	source := p.next()
+	if source.kind != tokenIdent {
+		return nil, fmt.Errorf("expected dataset, got %q", source.value)
+	}
+	q := &aplQuery{dataset: source.value}
+
+	for p.accept("|") {
+		op, err := p.parseOp()
+		if err != nil {
+			return nil, err
+		}
+		q.ops = append(q.ops, op)
+	}
+	if t := p.next(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q", t.value)
+	}
+	return q, nil
+}
+
+type aplParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *aplParser) peek() token { return p.peekN(0) }
+
+// peekN returns the token n positions ahead without consuming any tokens. Past
+// the end of the input, it returns the trailing EOF token.
+func (p *aplParser) peekN(n int) token {
+	if i := p.pos + n; i < len(p.tokens) {
+		return p.tokens[i]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *aplParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token, if it is the given symbol or keyword.
+func (p *aplParser) accept(value string) bool {
+	if t := p.peek(); (t.kind == tokenSymbol || t.kind == tokenIdent) && t.value == value {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *aplParser) expect(value string) error {
+	if !p.accept(value) {
+		return fmt.Errorf("expected %q, got %q", value, p.peek().value)
+	}
+	return nil
+}
+
+func (p *aplParser) ident() (string, error) {
+	t := p.next()
+	if t.kind != tokenIdent {
+		return "", fmt.Errorf("expected field, got %q", t.value)
+	}
+	return t.value, nil
+}
+
+func (p *aplParser) identList() ([]string, error) {
+	var idents []string
+	for {
+		ident, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		idents = append(idents, ident)
+		if !p.accept(",") {
+			return idents, nil
+		}
+	}
+}
+
+func (p *aplParser) parseOp() (aplOp, error) {
+	t := p.next()
+	switch t.value {
+	case "where", "filter":
+		expr, err := p.parseOr()
+		return whereOp{expr: expr}, err
+	case "project":
+		fields, err := p.identList()
+		return projectOp{fields: fields}, err
+	case "count":
+		return countOp{}, nil
+	case "summarize":
+		return p.parseSummarize()
+	case "order", "sort":
+		if err := p.expect("by"); err != nil {
+			return nil, err
+		}
+		var op orderOp
+		for {
+			field, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			key := orderKey{field: field, desc: true}
+			if p.accept("asc") {
+				key.desc = false
+			} else {
+				p.accept("desc")
+			}
+			op.keys = append(op.keys, key)
+			if !p.accept(",") {
+				return op, nil
+			}
+		}
+	case "take", "limit":
+		n := p.next()
+		i, err := strconv.Atoi(n.value)
+		if n.kind != tokenNumber || err != nil || i < 0 {
+			return nil, fmt.Errorf("expected row count, got %q", n.value)
+		}
+		return takeOp{n: i}, nil
+	}
+	return nil, fmt.Errorf("unsupported operator %q", t.value)
+}
+
+func (p *aplParser) parseSummarize() (aplOp, error) {
+	var op summarizeOp
+	for {
+		var agg aggregation
+		if t := p.peekN(1); p.peek().kind == tokenIdent && t.kind == tokenSymbol && t.value == "=" {
+			agg.name = p.next().value
+			p.next()
+		}
+
+		fn, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		switch fn {
+		case "count", "dcount", "sum", "avg", "min", "max":
+		default:
+			return nil, fmt.Errorf("unsupported aggregation %q", fn)
+		}
+		agg.fn = fn
+
+		if err = p.expect("("); err != nil {
+			return nil, err
+		}
+		if fn != "count" {
+			if agg.field, err = p.ident(); err != nil {
+				return nil, err
+			}
+		}
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+
+		if agg.name == "" {
+			agg.name = fn + "_" + strings.ReplaceAll(agg.field, ".", "_")
+		}
+		op.aggs = append(op.aggs, agg)
+
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	if p.accept("by") {
+		by, err := p.identList()
+		if err != nil {
+			return nil, err
+		}
+		op.by = by
+	}
+	return op, nil
+}
+
+func (p *aplParser) parseOr() (aplExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *aplParser) parseAnd() (aplExpr, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *aplParser) parseTerm() (aplExpr, error) {
+	if p.accept("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(")")
+	}
+
+	field, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+
+	switch field {
+	case "not", "isempty", "isnotempty":
+		if p.peek().value != "(" {
+			break
+		}
+		p.next()
+		var expr aplExpr
+		if field == "not" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			expr = notExpr{expr: inner}
+		} else {
+			arg, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			expr = emptyExpr{field: arg, empty: field == "isempty"}
+		}
+		return expr, p.expect(")")
+	}
+
+	op := p.next()
+	switch op.value {
+	case "==", "!=", "<", "<=", ">", ">=", "=~", "!~",
+		"contains", "!contains", "contains_cs", "startswith", "endswith":
+	default:
+		return nil, fmt.Errorf("unsupported operator %q", op.value)
+	}
+
+	value, err := p.literal()
+	if err != nil {
+		return nil, err
+	}
+	return cmpExpr{field: field, op: op.value, value: value}, nil
+}
+
+func (p *aplParser) literal() (any, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return t.value, nil
+	case tokenNumber:
+		return strconv.ParseFloat(t.value, 64)
+	case tokenIdent:
+		switch t.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+	case tokenSymbol, tokenEOF:
+	}
+	return nil, fmt.Errorf("expected value, got %q", t.value)
+}
+
+type tokenKind uint8
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func lexAPL(s string) ([]token, error) {
+	var (
+		tokens []token
+		rs     = []rune(s)
+	)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '[':
+			// Quoted identifier, e.g. ['my-dataset'].
+			if i+1 >= len(rs) || (rs[i+1] != '\'' && rs[i+1] != '"') {
+				return nil, fmt.Errorf("invalid quoted identifier at %d", i)
+			}
+			end := slices.Index(rs[i+2:], rs[i+1])
+			if end < 0 || i+3+end >= len(rs) || rs[i+3+end] != ']' {
+				return nil, fmt.Errorf("unterminated quoted identifier at %d", i)
+			}
+			tokens = append(tokens, token{tokenIdent, string(rs[i+2 : i+2+end])})
+			i += end + 4
+		case r == '"' || r == '\'':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(rs) && rs[j] != r; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) {
+					j++
+				}
+				sb.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, token{tokenString, sb.String()})
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i + 1
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.' || rs[j] == 'e' || rs[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, token{tokenNumber, string(rs[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_' || (r == '!' && i+1 < len(rs) && unicode.IsLetter(rs[i+1])):
+			j := i + 1
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_' || rs[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokenIdent, string(rs[i:j])})
+			i = j
+		default:
+			if i+1 < len(rs) {
+				switch op := string(rs[i : i+2]); op {
+				case "==", "!=", "<=", ">=", "=~", "!~":
+					tokens = append(tokens, token{tokenSymbol, op})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("|,()<>=", r) {
+				return nil, fmt.Errorf("unexpected character %q at %d", r, i)
+			}
+			tokens = append(tokens, token{tokenSymbol, string(r)})
+			i++
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
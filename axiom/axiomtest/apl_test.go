@@ -0,0 +1,103 @@
+package axiomtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAPL(t *testing.T) {
+	tests := []struct {
+		apl string
+		exp *aplQuery
+		err string
+	}{
+		{
+			apl: "['my-dataset']",
+			exp: &aplQuery{dataset: "my-dataset"},
+		},
+		{
+			apl: `test | where (a == "x" or b != 1) and c !contains 'y' | take 10`,
+			exp: &aplQuery{
+				dataset: "test",
+				ops: []aplOp{
+					whereOp{expr: andExpr{
+						left: orExpr{
+							left:  cmpExpr{field: "a", op: "==", value: "x"},
+							right: cmpExpr{field: "b", op: "!=", value: float64(1)},
+						},
+						right: cmpExpr{field: "c", op: "!contains", value: "y"},
+					}},
+					takeOp{n: 10},
+				},
+			},
+		},
+		{
+			apl: "test | summarize n = count(), avg(req.duration) by host | sort by n asc, host",
+			exp: &aplQuery{
+				dataset: "test",
+				ops: []aplOp{
+					summarizeOp{
+						aggs: []aggregation{
+							{name: "n", fn: "count"},
+							{name: "avg_req_duration", fn: "avg", field: "req.duration"},
+						},
+						by: []string{"host"},
+					},
+					orderOp{keys: []orderKey{{field: "n"}, {field: "host", desc: true}}},
+				},
+			},
+		},
+		{
+			apl: "test | where isnotempty(a) | project a, ['b c'] | count",
+			exp: &aplQuery{
+				dataset: "test",
+				ops: []aplOp{
+					whereOp{expr: emptyExpr{field: "a"}},
+					projectOp{fields: []string{"a", "b c"}},
+					countOp{},
+				},
+			},
+		},
+		{
+			apl: "test | extend a = 1",
+			err: `unsupported operator "extend"`,
+		},
+		{
+			apl: "test | summarize percentiles(a, 95)",
+			err: `unsupported aggregation "percentiles"`,
+		},
+		{
+			apl: "test | where a == 'x",
+			err: "unterminated string at 18",
+		},
+		{
+			apl: "test | take -1",
+			err: `expected row count, got "-1"`,
+		},
+		{
+			apl: "test | summarize",
+			err: `expected field, got ""`,
+		},
+		{
+			apl: "test | summarize count(),",
+			err: `expected field, got ""`,
+		},
+		{
+			apl: "test | summarize n =",
+			err: `expected field, got ""`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.apl, func(t *testing.T) {
+			q, err := parseAPL(tt.apl)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, q)
+		})
+	}
+}
@@ -0,0 +1,444 @@
+package axiomtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+type dataset struct {
+	axiom.Dataset
+
+	events []axiom.Event
+}
+
+func (s *Server) newDataset(req axiom.DatasetCreateRequest) *dataset {
+	if req.Kind == "" {
+		req.Kind = "axiom:events:v1"
+	}
+	now := s.now().UTC()
+	return &dataset{
+		Dataset: axiom.Dataset{
+			ID:                 req.Name,
+			Name:               req.Name,
+			Kind:               req.Kind,
+			Description:        req.Description,
+			CreatedBy:          "axiomtest",
+			CreatedAt:          now,
+			UpdatedAt:          now,
+			CanWrite:           true,
+			UseRetentionPeriod: req.UseRetentionPeriod,
+			RetentionDays:      req.RetentionDays,
+			MapFields:          axiom.MapFields{},
+			EdgeDeployment:     req.EdgeDeployment,
+		},
+	}
+}
+
+func (s *Server) listDatasets(w http.ResponseWriter, _ *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]axiom.Dataset, 0, len(s.datasets))
+	for _, name := range slices.Sorted(maps.Keys(s.datasets)) {
+		res = append(res, s.datasets[name].Dataset)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) getDataset(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ds, ok := s.datasets[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	writeJSON(w, http.StatusOK, ds.Dataset)
+}
+
+func (s *Server) createDataset(w http.ResponseWriter, r *http.Request) {
+	var req axiom.DatasetCreateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	} else if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "dataset name is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.datasets[req.Name]; ok {
+		writeError(w, http.StatusConflict, "")
+		return
+	}
+	ds := s.newDataset(req)
+	s.datasets[req.Name] = ds
+
+	writeJSON(w, http.StatusOK, ds.Dataset)
+}
+
+func (s *Server) updateDataset(w http.ResponseWriter, r *http.Request) {
+	var req axiom.DatasetUpdateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ds, ok := s.datasets[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	ds.Description = req.Description
+	ds.UseRetentionPeriod = req.UseRetentionPeriod
+	ds.RetentionDays = req.RetentionDays
+	ds.UpdatedAt = s.now().UTC()
+
+	writeJSON(w, http.StatusOK, ds.Dataset)
+}
+
+func (s *Server) deleteDataset(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.datasets[id]; !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	delete(s.datasets, id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) trimDataset(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		MaxDuration string `json:"maxDuration"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	maxDuration, err := time.ParseDuration(req.MaxDuration)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid max duration: %s", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ds, ok := s.datasets[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	oldest := s.now().Add(-maxDuration)
+	ds.events = slices.DeleteFunc(ds.events, func(event axiom.Event) bool {
+		return event[ingest.TimestampField].(time.Time).Before(oldest)
+	})
+
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) listMapFields(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ds, ok := s.datasets[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	writeJSON(w, http.StatusOK, ds.MapFields)
+}
+
+func (s *Server) createMapField(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ds, ok := s.datasets[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	if !slices.Contains(ds.MapFields, req.Name) {
+		ds.MapFields = append(ds.MapFields, req.Name)
+	}
+	writeJSON(w, http.StatusOK, ds.MapFields)
+}
+
+func (s *Server) updateMapFields(w http.ResponseWriter, r *http.Request) {
+	var mapFields axiom.MapFields
+	if !decodeJSON(w, r, &mapFields) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ds, ok := s.datasets[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	ds.MapFields = append(axiom.MapFields{}, mapFields...)
+
+	writeJSON(w, http.StatusOK, ds.MapFields)
+}
+
+func (s *Server) deleteMapField(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ds, ok := s.datasets[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	name := r.PathValue("name")
+	if !slices.Contains(ds.MapFields, name) {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	ds.MapFields = slices.DeleteFunc(ds.MapFields, func(other string) bool { return other == name })
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) ingest(w http.ResponseWriter, r *http.Request) {
+	body, err := decodeBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer body.Close()
+
+	var (
+		query    = r.URL.Query()
+		counting = &countingReader{r: body}
+		events   []axiom.Event
+	)
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch ct {
+	case axiom.JSON.String():
+		events, err = decodeJSONEvents(counting)
+	case axiom.NDJSON.String():
+		events, err = decodeNDJSONEvents(counting)
+	case axiom.CSV.String():
+		events, err = decodeCSVEvents(counting, query.Get("csv-delimiter"), r.Header.Get("X-Axiom-CSV-Fields"))
+	default:
+		writeError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("unsupported content type %q", ct))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return
+	}
+
+	var labels map[string]any
+	if h := r.Header.Get("X-Axiom-Event-Labels"); h != "" {
+		if err = json.Unmarshal([]byte(h), &labels); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid event labels: %s", err))
+			return
+		}
+	}
+
+	timestampField := query.Get("timestamp-field")
+	if timestampField == "" {
+		timestampField = ingest.TimestampField
+	}
+	timestampFormat := query.Get("timestamp-format")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ds, ok := s.datasets[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+
+	now := s.now().UTC()
+	for _, event := range events {
+		for k, v := range labels {
+			if _, exists := event[k]; !exists {
+				event[k] = v
+			}
+		}
+
+		ts, ok := parseTimestamp(event[timestampField], timestampFormat)
+		if !ok {
+			ts = now
+		}
+		delete(event, timestampField)
+		event[ingest.TimestampField] = ts
+	}
+	ds.events = append(ds.events, events...)
+
+	writeJSON(w, http.StatusOK, ingest.Status{
+		Ingested:       uint64(len(events)),
+		Failures:       []*ingest.Failure{},
+		ProcessedBytes: counting.n,
+	})
+}
+
+func decodeBody(r *http.Request) (io.ReadCloser, error) {
+	switch enc := r.Header.Get("Content-Encoding"); enc {
+	case "", "identity":
+		return r.Body, nil
+	case axiom.Gzip.String():
+		return gzip.NewReader(r.Body)
+	case axiom.Zstd.String():
+		dec, err := zstd.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", enc)
+	}
+}
+
+func decodeJSONEvents(r io.Reader) ([]axiom.Event, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both a single event and an array of events are accepted.
+	if b = bytes.TrimSpace(b); len(b) > 0 && b[0] == '{' {
+		var event axiom.Event
+		if err = json.Unmarshal(b, &event); err != nil {
+			return nil, err
+		}
+		return []axiom.Event{event}, nil
+	}
+
+	var events []axiom.Event
+	if err = json.Unmarshal(b, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func decodeNDJSONEvents(r io.Reader) ([]axiom.Event, error) {
+	var (
+		dec    = json.NewDecoder(r)
+		events []axiom.Event
+	)
+	for {
+		var event axiom.Event
+		if err := dec.Decode(&event); errors.Is(err, io.EOF) {
+			return events, nil
+		} else if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+}
+
+func decodeCSVEvents(r io.Reader, delimiter, fields string) ([]axiom.Event, error) {
+	cr := csv.NewReader(r)
+	if delimiter != "" {
+		cr.Comma, _ = utf8.DecodeRuneInString(delimiter)
+	}
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var header []string
+	if fields != "" {
+		header = strings.Split(fields, ",")
+	} else if len(records) > 0 {
+		header, records = records[0], records[1:]
+	}
+
+	events := make([]axiom.Event, 0, len(records))
+	for _, record := range records {
+		event := make(axiom.Event, len(record))
+		for i, value := range record {
+			if i < len(header) {
+				event[header[i]] = value
+			}
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// parseTimestamp parses RFC 3339 strings, strings in the given format and
+// Unix timestamps in seconds, milliseconds, microseconds or nanoseconds.
+func parseTimestamp(v any, format string) (time.Time, bool) {
+	switch v := v.(type) {
+	case string:
+		if format == "" {
+			format = time.RFC3339Nano
+		}
+		if t, err := time.Parse(format, v); err == nil {
+			return t.UTC(), true
+		}
+		// CSV values are always strings, so try Unix timestamps, too.
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return parseTimestamp(f, "")
+	case float64:
+		var ns float64
+		switch abs := math.Abs(v); {
+		case abs < 1e11:
+			ns = v * 1e9
+		case abs < 1e14:
+			ns = v * 1e6
+		case abs < 1e17:
+			ns = v * 1e3
+		default:
+			ns = v
+		}
+		return time.Unix(0, int64(ns)).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+type countingReader struct {
+	r io.Reader
+	n uint64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += uint64(n) //nolint:gosec // A read never returns a negative count.
+	return n, err
+}
@@ -0,0 +1,27 @@
+// Package axiomtest provides an in-memory fake of the Axiom API for testing
+// code that uses an [axiom.Client].
+//
+// Usage:
+//
+//	import "github.com/axiomhq/axiom-go/axiom/axiomtest"
+//
+// The [Server] implements the datasets, ingest, query, monitors, notifiers,
+// annotations, tokens and virtual fields endpoints. Queries support a small
+// subset of APL: a dataset reference followed by the "where", "project",
+// "count", "summarize", "order by" and "take" operators.
+//
+//	srv := axiomtest.NewServer()
+//	defer srv.Close()
+//
+//	client, err := srv.Client()
+//	if err != nil {
+//	    // Handle error.
+//	}
+//
+//	// Use the client in the code under test and inspect the ingested events
+//	// afterwards.
+//	events := srv.Events("my-dataset")
+//
+// The server is not a full implementation of the Axiom API and should not be
+// used to verify the behaviour of the API itself.
+package axiomtest
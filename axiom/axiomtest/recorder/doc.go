@@ -0,0 +1,30 @@
+// Package recorder provides an [http.RoundTripper] that records interactions
+// with the Axiom API to a cassette file and replays them later, without network
+// access. This makes it possible to run realistic client tests in CI.
+//
+// Usage:
+//
+//	rec, err := recorder.New("testdata/datasets.json")
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer func() {
+//		if err := rec.Stop(); err != nil {
+//			t.Error(err)
+//		}
+//	}()
+//
+//	client, err := axiom.NewClient(
+//		axiom.SetClient(rec.Client()),
+//	)
+//
+// By default, interactions are replayed if the cassette exists and recorded
+// otherwise. Use [SetMode] to force a mode.
+//
+// Sensitive headers like "Authorization" and "X-Axiom-Org-Id" as well as
+// Axiom tokens in request and response bodies are scrubbed before a cassette
+// is written. Replayed token values are thus [Redacted]. Requests are matched by method, path, query and body.
+// Compressed request bodies are decompressed and timestamps are ignored, when
+// matching, so ingesting or querying events with the current time replays
+// just fine.
+package recorder
@@ -0,0 +1,369 @@
+package recorder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+//go:generate go tool stringer -type=Mode -linecomment -output=recorder_string.go
+
+// Mode of a [Recorder].
+type Mode uint8
+
+// All available [Mode]s.
+const (
+	// ModeAuto replays interactions if the cassette exists and records them
+	// otherwise.
+	ModeAuto Mode = iota // auto
+	// ModeRecord records interactions, overwriting an existing cassette.
+	ModeRecord // record
+	// ModeReplay replays interactions and fails requests that have not been
+	// recorded.
+	ModeReplay // replay
+)
+
+// Redacted replaces the values of scrubbed headers and tokens found in bodies.
+const Redacted = "[REDACTED]"
+
+// ErrNoInteraction is returned by [Recorder.RoundTrip] in replay mode, if no
+// recorded interaction matches the request.
+var ErrNoInteraction = errors.New("no matching interaction recorded")
+
+// DefaultScrubHeaders are the headers scrubbed by default.
+var DefaultScrubHeaders = []string{"Authorization", "X-Axiom-Org-Id"}
+
+// tokenRe matches Axiom API and personal tokens.
+var tokenRe = regexp.MustCompile(`xa[ap]t-[0-9A-Za-z-]+`)
+
+var timestampRe = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+
+// An Option modifies the configuration of a [Recorder].
+type Option func(*Recorder) error
+
+// SetMode sets the mode of the recorder. Defaults to [ModeAuto].
+func SetMode(mode Mode) Option {
+	return func(r *Recorder) error {
+		r.mode = mode
+		return nil
+	}
+}
+
+// SetTransport sets the transport used to send requests when recording.
+// Defaults to [http.DefaultTransport].
+func SetTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) error {
+		if rt == nil {
+			return errors.New("transport must not be nil")
+		}
+		r.transport = rt
+		return nil
+	}
+}
+
+// SetScrubHeaders sets the request and response headers whose values are
+// replaced by [Redacted] before they are written to the cassette. Defaults to
+// [DefaultScrubHeaders].
+func SetScrubHeaders(headers ...string) Option {
+	return func(r *Recorder) error {
+		r.scrubHeaders = slices.Clone(headers)
+		return nil
+	}
+}
+
+// Recorder is an [http.RoundTripper] which records interactions to a cassette
+// file or replays them from it. It is safe for concurrent use.
+type Recorder struct {
+	path         string
+	mode         Mode
+	transport    http.RoundTripper
+	scrubHeaders []string
+
+	mu           sync.Mutex
+	interactions []*interaction
+	used         []bool
+}
+
+// New creates a new [Recorder] for the cassette at the given path. In replay
+// mode, the cassette is loaded immediately. Call [Recorder.Stop] to write the
+// cassette, after recording.
+func New(path string, options ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:         path,
+		transport:    http.DefaultTransport,
+		scrubHeaders: DefaultScrubHeaders,
+	}
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.mode == ModeAuto {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+
+	if r.mode == ModeReplay {
+		b, err := os.ReadFile(path) //nolint:gosec // The path of the cassette is chosen by the test.
+		if err != nil {
+			return nil, fmt.Errorf("read cassette: %w", err)
+		}
+		var c cassette
+		if err = json.Unmarshal(b, &c); err != nil {
+			return nil, fmt.Errorf("decode cassette %q: %w", path, err)
+		}
+		r.interactions = c.Interactions
+		r.used = make([]bool, len(c.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the mode the recorder operates in. It is never [ModeAuto].
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns a new [http.Client] using the recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A round tripper must not modify the request, so the body is read from
+	// and replaced on a clone of it.
+	out := req.Clone(req.Context())
+	reqBody, err := readBody(&out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+	recReq, err := r.newRequest(req, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recReq)
+	}
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, &interaction{
+		Request: recReq,
+		Response: response{
+			StatusCode: resp.StatusCode,
+			Header:     r.scrub(resp.Header),
+			payload:    encodeBody(scrubTokens(respBody)),
+		},
+	})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Stop writes the recorded interactions to the cassette. It is a no-op in
+// replay mode.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cassette: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(r.path), 0o750); err != nil {
+		return fmt.Errorf("create cassette directory: %w", err)
+	}
+	if err = os.WriteFile(r.path, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	return nil
+}
+
+func (r *Recorder) replay(req *http.Request, recReq request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.interactions {
+		if r.used[i] || !in.Request.matches(recReq) {
+			continue
+		}
+		r.used[i] = true
+
+		body, err := in.Response.decode()
+		if err != nil {
+			return nil, fmt.Errorf("decode recorded response body: %w", err)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL)
+}
+
+// newRequest creates the recorded form of the request. Compressed bodies are
+// stored decompressed, to keep cassettes readable and matching stable.
+func (r *Recorder) newRequest(req *http.Request, body []byte) (request, error) {
+	var err error
+	switch enc := req.Header.Get("Content-Encoding"); enc {
+	case "gzip":
+		var gr *gzip.Reader
+		if gr, err = gzip.NewReader(bytes.NewReader(body)); err == nil {
+			body, err = io.ReadAll(gr)
+		}
+	case "zstd":
+		var zr *zstd.Decoder
+		if zr, err = zstd.NewReader(nil); err == nil {
+			body, err = zr.DecodeAll(body, nil)
+			zr.Close()
+		}
+	}
+	if err != nil {
+		return request{}, fmt.Errorf("decompress request body: %w", err)
+	}
+
+	return request{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Header:  r.scrub(req.Header),
+		payload: encodeBody(scrubTokens(body)),
+	}, nil
+}
+
+func (r *Recorder) scrub(header http.Header) http.Header {
+	header = header.Clone()
+	for _, key := range r.scrubHeaders {
+		if header.Get(key) != "" {
+			header.Set(key, Redacted)
+		}
+	}
+	return header
+}
+
+// scrubTokens replaces Axiom tokens in the body by [Redacted], e.g. the secret
+// values returned when creating or regenerating tokens.
+func scrubTokens(body []byte) []byte {
+	return tokenRe.ReplaceAll(body, []byte(Redacted))
+}
+
+// readBody reads the body and replaces it by a reader over the read bytes.
+func readBody(rc *io.ReadCloser) ([]byte, error) {
+	if *rc == nil || *rc == http.NoBody {
+		return nil, nil
+	}
+	b, err := io.ReadAll(*rc)
+	if closeErr := (*rc).Close(); err == nil {
+		err = closeErr
+	}
+	*rc = io.NopCloser(bytes.NewReader(b))
+	return b, err
+}
+
+type cassette struct {
+	Interactions []*interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  request  `json:"request"`
+	Response response `json:"response"`
+}
+
+// payload is stored as text, if it is valid UTF-8, and base64 encoded otherwise.
+type payload struct {
+	Body       string `json:"body,omitempty"`
+	BodyBase64 []byte `json:"bodyBase64,omitempty"`
+}
+
+func encodeBody(b []byte) payload {
+	if utf8.Valid(b) {
+		return payload{Body: string(b)}
+	}
+	return payload{BodyBase64: b}
+}
+
+func (b payload) decode() ([]byte, error) {
+	if b.BodyBase64 != nil {
+		return b.BodyBase64, nil
+	}
+	return []byte(b.Body), nil
+}
+
+func (b payload) String() string {
+	if b.BodyBase64 != nil {
+		return string(b.BodyBase64)
+	}
+	return b.Body
+}
+
+type request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	payload
+}
+
+// matches reports whether the request matches the given one. The host of the
+// URL and timestamps in the URL and body are ignored.
+func (req request) matches(other request) bool {
+	return req.Method == other.Method &&
+		normalize(requestURI(req.URL)) == normalize(requestURI(other.URL)) &&
+		normalize(req.String()) == normalize(other.String())
+}
+
+type response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	payload
+}
+
+// requestURI returns the unescaped path and query of the URL.
+func requestURI(s string) string {
+	if u, err := url.Parse(s); err == nil {
+		s = u.RequestURI()
+	}
+	if uri, err := url.QueryUnescape(s); err == nil {
+		return uri
+	}
+	return s
+}
+
+func normalize(s string) string {
+	return timestampRe.ReplaceAllString(s, "<timestamp>")
+}
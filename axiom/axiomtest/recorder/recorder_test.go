@@ -0,0 +1,174 @@
+package recorder_test
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/axiomtest"
+	"github.com/axiomhq/axiom-go/axiom/axiomtest/recorder"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/axiom/query"
+)
+
+func TestRecorder(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "cassettes", "test.json")
+		srv  = axiomtest.NewServer()
+	)
+	t.Cleanup(srv.Close)
+	srv.CreateDataset("test")
+
+	// run exercises the client. Each run uses different timestamps.
+	run := func(t *testing.T, rec *recorder.Recorder) {
+		client, err := srv.Client(
+			axiom.SetClient(rec.Client()),
+			axiom.SetOrganizationID("my-org"),
+			axiom.SetNoRetry(),
+		)
+		require.NoError(t, err)
+
+		now := time.Now()
+		status, err := client.IngestEvents(t.Context(), "test", []axiom.Event{
+			{ingest.TimestampField: now, "foo": "bar"},
+		})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, status.Ingested)
+
+		res, err := client.Query(t.Context(), "['test'] | count",
+			query.SetStartTime(now.Add(-time.Hour)),
+			query.SetEndTime(now.Add(time.Hour)),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []query.Column{{float64(1)}}, res.Tables[0].Columns)
+
+		_, err = client.Datasets.Get(t.Context(), "unknown")
+		assert.ErrorIs(t, err, axiom.ErrNotFound)
+	}
+
+	rec, err := recorder.New(path)
+	require.NoError(t, err)
+	assert.Equal(t, recorder.ModeRecord, rec.Mode())
+
+	run(t, rec)
+	require.NoError(t, rec.Stop())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), axiomtest.APIToken)
+	assert.NotContains(t, string(b), "my-org")
+	assert.Contains(t, string(b), recorder.Redacted)
+	assert.Contains(t, string(b), `\"foo\":\"bar\"`, "ingest body should be stored decompressed")
+
+	// Make sure the server isn't consulted anymore. Replaying works with any
+	// server URL.
+	srv.Close()
+	srv.URL = "http://axiom.local"
+
+	rec, err = recorder.New(path)
+	require.NoError(t, err)
+	assert.Equal(t, recorder.ModeReplay, rec.Mode())
+
+	time.Sleep(10 * time.Millisecond)
+	run(t, rec)
+	require.NoError(t, rec.Stop())
+}
+
+func TestRecorder_ScrubTokens(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "test.json")
+		srv  = axiomtest.NewServer()
+	)
+	t.Cleanup(srv.Close)
+
+	rec, err := recorder.New(path)
+	require.NoError(t, err)
+
+	client, err := srv.Client(axiom.SetClient(rec.Client()), axiom.SetNoRetry())
+	require.NoError(t, err)
+
+	token, err := client.Tokens.Create(t.Context(), axiom.CreateTokenRequest{Name: "test"})
+	require.NoError(t, err)
+	require.NotEmpty(t, token.Token)
+	require.NoError(t, rec.Stop())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), token.Token)
+	assert.Contains(t, string(b), `\"token\":\"`+recorder.Redacted+`\"`)
+
+	// The scrubbed token is replayed.
+	rec, err = recorder.New(path)
+	require.NoError(t, err)
+
+	client, err = srv.Client(axiom.SetClient(rec.Client()), axiom.SetNoRetry())
+	require.NoError(t, err)
+
+	token, err = client.Tokens.Create(t.Context(), axiom.CreateTokenRequest{Name: "test"})
+	require.NoError(t, err)
+	assert.Equal(t, recorder.Redacted, token.Token)
+}
+
+func TestRecorder_NoInteraction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"interactions":[]}`), 0o600))
+
+	rec, err := recorder.New(path, recorder.SetMode(recorder.ModeReplay))
+	require.NoError(t, err)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://axiom.local/v2/datasets", nil)
+	require.NoError(t, err)
+
+	resp, err := rec.Client().Do(req)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	assert.ErrorIs(t, err, recorder.ErrNoInteraction)
+}
+
+func TestRecorder_RoundTrip_Request(t *testing.T) {
+	var body string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = string(b)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+
+	rec, err := recorder.New(filepath.Join(t.TempDir(), "test.json"),
+		recorder.SetMode(recorder.ModeRecord),
+		recorder.SetTransport(transport),
+	)
+	require.NoError(t, err)
+
+	reqBody := io.NopCloser(strings.NewReader("my body"))
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "http://axiom.local/v1/datasets", reqBody)
+	require.NoError(t, err)
+
+	resp, err := rec.RoundTrip(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	// The body is passed on, but the request is left untouched.
+	assert.Equal(t, "my body", body)
+	assert.Equal(t, reqBody, req.Body)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestRecorder_MissingCassette(t *testing.T) {
+	_, err := recorder.New(filepath.Join(t.TempDir(), "test.json"), recorder.SetMode(recorder.ModeReplay))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
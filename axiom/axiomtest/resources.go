@@ -0,0 +1,236 @@
+package axiomtest
+
+import (
+	"crypto/rand"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// store holds resources which are kept in the form they are sent by the
+// client. This keeps the fake independent of the exact shape of each resource.
+type store struct {
+	prefix string
+	nextID int
+	items  map[string]map[string]any
+	order  []string
+}
+
+func newStore(prefix string) *store {
+	return &store{
+		prefix: prefix,
+		items:  make(map[string]map[string]any),
+	}
+}
+
+func (st *store) create(item map[string]any) map[string]any {
+	st.nextID++
+	id := st.prefix + strconv.Itoa(st.nextID)
+
+	item["id"] = id
+	st.items[id] = item
+	st.order = append(st.order, id)
+
+	return item
+}
+
+func (st *store) delete(id string) bool {
+	if _, ok := st.items[id]; !ok {
+		return false
+	}
+	delete(st.items, id)
+	st.order = slices.DeleteFunc(st.order, func(other string) bool { return other == id })
+	return true
+}
+
+// writeHook is called when a resource is created or updated.
+type writeHook func(item map[string]any, now time.Time, created bool)
+
+func setTimestamps(item map[string]any, now time.Time, created bool) {
+	ts := now.UTC().Format(time.RFC3339Nano)
+	if created {
+		item["createdAt"] = ts
+		item["createdBy"] = "axiomtest"
+	}
+	item["updatedAt"] = ts
+}
+
+func (s *Server) handleResource(mux *http.ServeMux, path, kind string, hook writeHook) {
+	mux.HandleFunc("GET "+path, s.listResources(kind))
+	mux.HandleFunc("POST "+path, s.createResource(kind, hook))
+	mux.HandleFunc("GET "+path+"/{id}", s.getResource(kind))
+	mux.HandleFunc("PUT "+path+"/{id}", s.updateResource(kind, hook))
+	mux.HandleFunc("DELETE "+path+"/{id}", s.deleteResource(kind))
+}
+
+func (s *Server) listResources(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		st := s.resources[kind]
+		res := make([]map[string]any, 0, len(st.order))
+		for _, id := range st.order {
+			if item := st.items[id]; matches(item, r.URL.Query()) {
+				res = append(res, item)
+			}
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func (s *Server) getResource(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		item, ok := s.resources[kind].items[r.PathValue("id")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "")
+			return
+		}
+		writeJSON(w, http.StatusOK, item)
+	}
+}
+
+func (s *Server) createResource(kind string, hook writeHook) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var item map[string]any
+		if !decodeJSON(w, r, &item) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if hook != nil {
+			hook(item, s.now(), true)
+		}
+		writeJSON(w, http.StatusOK, s.resources[kind].create(item))
+	}
+}
+
+func (s *Server) updateResource(kind string, hook writeHook) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var update map[string]any
+		if !decodeJSON(w, r, &update) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		item, ok := s.resources[kind].items[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "")
+			return
+		}
+
+		maps.Copy(item, update)
+		item["id"] = id
+		if hook != nil {
+			hook(item, s.now(), false)
+		}
+		writeJSON(w, http.StatusOK, item)
+	}
+}
+
+func (s *Server) deleteResource(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.resources[kind].delete(r.PathValue("id")) {
+			writeError(w, http.StatusNotFound, "")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) createToken(w http.ResponseWriter, r *http.Request) {
+	var item map[string]any
+	if !decodeJSON(w, r, &item) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, s.newToken(item))
+}
+
+func (s *Server) regenerateToken(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ExistingTokenExpiresAt any            `json:"existingTokenExpiresAt"`
+		NewToken               map[string]any `json:"newToken"`
+		NewTokenExpiresAt      any            `json:"newTokenExpiresAt"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.resources["tokens"].items[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	existing["expiresAt"] = req.ExistingTokenExpiresAt
+
+	item := req.NewToken
+	if item == nil {
+		item = maps.Clone(existing)
+		delete(item, "id")
+	}
+	if req.NewTokenExpiresAt != nil {
+		item["expiresAt"] = req.NewTokenExpiresAt
+	}
+
+	writeJSON(w, http.StatusOK, s.newToken(item))
+}
+
+// newToken stores the token and returns it together with its secret value,
+// which is only ever returned on creation.
+func (s *Server) newToken(item map[string]any) map[string]any {
+	res := maps.Clone(s.resources["tokens"].create(item))
+	res["token"] = "xaat-" + uuid()
+	return res
+}
+
+// matches reports whether the item matches the query parameters. Parameters
+// not present on the item are ignored. Array fields match if any of their
+// elements matches.
+func matches(item map[string]any, query url.Values) bool {
+	for key, values := range query {
+		switch v := item[key].(type) {
+		case string:
+			if !slices.Contains(values, v) {
+				return false
+			}
+		case []any:
+			if !slices.ContainsFunc(v, func(e any) bool {
+				s, ok := e.(string)
+				return ok && slices.Contains(values, s)
+			}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func uuid() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
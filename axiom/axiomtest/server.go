@@ -0,0 +1,187 @@
+package axiomtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+// APIToken is the API token used by clients returned by [Server.Client]. The
+// server accepts any token, though.
+const APIToken = "xaat-00000000-0000-0000-0000-000000000000"
+
+// Server is an in-memory fake of the Axiom API. It is safe for concurrent use.
+type Server struct {
+	// URL of the server, in the form "http://ipaddr:port" with no trailing
+	// slash.
+	URL string
+
+	srv *httptest.Server
+
+	mu        sync.RWMutex
+	datasets  map[string]*dataset
+	resources map[string]*store
+	now       func() time.Time
+}
+
+// NewServer starts and returns a new [Server]. The caller should call
+// [Server.Close] when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		datasets: make(map[string]*dataset),
+		resources: map[string]*store{
+			"monitors":    newStore(""),
+			"notifiers":   newStore(""),
+			"annotations": newStore("ann_"),
+			"vfields":     newStore(""),
+			"tokens":      newStore(""),
+		},
+		now: time.Now,
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v2/user", s.currentUser)
+
+	mux.HandleFunc("GET /v2/datasets", s.listDatasets)
+	mux.HandleFunc("POST /v2/datasets", s.createDataset)
+	mux.HandleFunc("GET /v2/datasets/{id}", s.getDataset)
+	mux.HandleFunc("PUT /v2/datasets/{id}", s.updateDataset)
+	mux.HandleFunc("DELETE /v2/datasets/{id}", s.deleteDataset)
+	mux.HandleFunc("POST /v2/datasets/{id}/trim", s.trimDataset)
+	mux.HandleFunc("GET /v2/datasets/{id}/mapfields", s.listMapFields)
+	mux.HandleFunc("POST /v2/datasets/{id}/mapfields", s.createMapField)
+	mux.HandleFunc("PUT /v2/datasets/{id}/mapfields", s.updateMapFields)
+	mux.HandleFunc("DELETE /v2/datasets/{id}/mapfields/{name}", s.deleteMapField)
+
+	mux.HandleFunc("POST /v1/datasets/{id}/ingest", s.ingest)
+	mux.HandleFunc("POST /v1/ingest/{id}", s.ingest)
+	mux.HandleFunc("POST /v1/datasets/_apl", s.query)
+	mux.HandleFunc("POST /v1/query/_apl", s.query)
+
+	s.handleResource(mux, "/v2/monitors", "monitors", setTimestamps)
+	s.handleResource(mux, "/v2/notifiers", "notifiers", setTimestamps)
+	s.handleResource(mux, "/v2/annotations", "annotations", nil)
+	s.handleResource(mux, "/v2/vfields", "vfields", nil)
+	mux.HandleFunc("GET /v2/tokens", s.listResources("tokens"))
+	mux.HandleFunc("GET /v2/tokens/{id}", s.getResource("tokens"))
+	mux.HandleFunc("DELETE /v2/tokens/{id}", s.deleteResource("tokens"))
+	mux.HandleFunc("POST /v2/tokens", s.createToken)
+	mux.HandleFunc("POST /v2/tokens/{id}/regenerate", s.regenerateToken)
+
+	s.srv = httptest.NewServer(authenticate(mux))
+	s.URL = s.srv.URL
+
+	return s
+}
+
+// Close shuts down the server and blocks until all outstanding requests on
+// this server have completed.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a new [axiom.Client] configured to talk to the server. The
+// given options are applied after the ones configuring the client for the
+// server.
+func (s *Server) Client(options ...axiom.Option) (*axiom.Client, error) {
+	return axiom.NewClient(append([]axiom.Option{
+		axiom.SetNoEnv(),
+		axiom.SetURL(s.URL),
+		axiom.SetToken(APIToken),
+	}, options...)...)
+}
+
+// Events returns a copy of the events ingested into the dataset identified by
+// its name, in the order they were ingested. Each event carries its timestamp
+// as [time.Time] in the "_time" field. It returns nil, if the dataset does not
+// exist.
+func (s *Server) Events(dataset string) []axiom.Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ds, ok := s.datasets[dataset]
+	if !ok {
+		return nil
+	}
+
+	events := make([]axiom.Event, len(ds.events))
+	for i, event := range ds.events {
+		events[i] = maps.Clone(event)
+	}
+	return events
+}
+
+// Datasets returns the names of all datasets, sorted alphabetically.
+func (s *Server) Datasets() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(s.datasets))
+}
+
+// CreateDataset creates the dataset with the given name, if it doesn't exist
+// already. This is a shortcut for tests that only care about ingesting and
+// querying events.
+func (s *Server) CreateDataset(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.datasets[name]; !ok {
+		s.datasets[name] = s.newDataset(axiom.DatasetCreateRequest{Name: name})
+	}
+}
+
+func (s *Server) currentUser(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, axiom.User{
+		ID:    "axiomtest",
+		Name:  "Axiom Test",
+		Email: "axiomtest@axiom.co",
+	})
+}
+
+// authenticate rejects requests without a bearer token, just like the Axiom
+// API does.
+func authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			writeError(w, http.StatusUnauthorized, "")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+// writeError writes an error in the format of the Axiom API. An empty message
+// is replaced by the status text of the code.
+func writeError(w http.ResponseWriter, code int, message string) {
+	if message == "" {
+		message = http.StatusText(code)
+	}
+	writeJSON(w, code, map[string]string{"message": message})
+}
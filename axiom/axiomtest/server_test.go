@@ -0,0 +1,334 @@
+package axiomtest_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/axiomtest"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/axiom/query"
+)
+
+func setup(t *testing.T) (*axiomtest.Server, *axiom.Client) {
+	t.Helper()
+
+	srv := axiomtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := srv.Client()
+	require.NoError(t, err)
+
+	return srv, client
+}
+
+func TestServer_Datasets(t *testing.T) {
+	srv, client := setup(t)
+
+	dataset, err := client.Datasets.Create(t.Context(), axiom.DatasetCreateRequest{
+		Name:        "test",
+		Description: "This is a test dataset",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "test", dataset.ID)
+	assert.Equal(t, "This is a test dataset", dataset.Description)
+
+	_, err = client.Datasets.Create(t.Context(), axiom.DatasetCreateRequest{Name: "test"})
+	assert.ErrorIs(t, err, axiom.ErrExists)
+
+	dataset, err = client.Datasets.Update(t.Context(), "test", axiom.DatasetUpdateRequest{
+		Description: "This is still a test dataset",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "This is still a test dataset", dataset.Description)
+
+	datasets, err := client.Datasets.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, datasets, 1)
+	assert.Equal(t, "test", datasets[0].Name)
+	assert.Equal(t, []string{"test"}, srv.Datasets())
+
+	err = client.Datasets.Delete(t.Context(), "test")
+	require.NoError(t, err)
+
+	_, err = client.Datasets.Get(t.Context(), "test")
+	assert.ErrorIs(t, err, axiom.ErrNotFound)
+	assert.Empty(t, srv.Datasets())
+}
+
+func TestServer_Ingest(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		typ     axiom.ContentType
+		enc     axiom.ContentEncoder
+		options []ingest.Option
+	}{
+		{
+			name:  "ndjson",
+			input: "{\"_time\":\"2024-01-02T03:04:05Z\",\"foo\":\"bar\"}\n{\"_time\":\"2024-01-02T03:04:06Z\",\"foo\":\"baz\"}\n",
+			typ:   axiom.NDJSON,
+		},
+		{
+			name:  "json gzip",
+			input: `[{"_time":"2024-01-02T03:04:05Z","foo":"bar"},{"_time":"2024-01-02T03:04:06Z","foo":"baz"}]`,
+			typ:   axiom.JSON,
+			enc:   axiom.GzipEncoder(),
+		},
+		{
+			name:  "csv zstd",
+			input: "ts;foo\n1704164645;bar\n1704164646;baz\n",
+			typ:   axiom.CSV,
+			enc:   axiom.ZstdEncoder(),
+			options: []ingest.Option{
+				ingest.SetTimestampField("ts"),
+				ingest.SetCSVDelimiter(";"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := setup(t)
+			srv.CreateDataset("test")
+
+			var (
+				r   = strings.NewReader(tt.input)
+				enc = axiom.Identity
+			)
+			if tt.enc != nil {
+				enc = axiom.Gzip
+				if tt.typ == axiom.CSV {
+					enc = axiom.Zstd
+				}
+				er, err := tt.enc(r)
+				require.NoError(t, err)
+
+				status, err := client.Datasets.Ingest(t.Context(), "test", er, tt.typ, enc, tt.options...)
+				require.NoError(t, err)
+				assert.EqualValues(t, 2, status.Ingested)
+			} else {
+				status, err := client.Datasets.Ingest(t.Context(), "test", r, tt.typ, enc, tt.options...)
+				require.NoError(t, err)
+				assert.EqualValues(t, 2, status.Ingested)
+			}
+
+			events := srv.Events("test")
+			require.Len(t, events, 2)
+			assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), events[0][ingest.TimestampField])
+			assert.Equal(t, "bar", events[0]["foo"])
+			assert.Equal(t, "baz", events[1]["foo"])
+		})
+	}
+}
+
+func TestServer_IngestEvents(t *testing.T) {
+	srv, client := setup(t)
+	srv.CreateDataset("test")
+
+	status, err := client.IngestEvents(t.Context(), "test", []axiom.Event{
+		{"foo": "bar", "nested": map[string]any{"n": 1}},
+	}, ingest.SetEventLabel("env", "test"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, status.Ingested)
+	assert.NotZero(t, status.ProcessedBytes)
+
+	events := srv.Events("test")
+	require.Len(t, events, 1)
+	assert.Equal(t, "bar", events[0]["foo"])
+	assert.Equal(t, "test", events[0]["env"])
+	assert.Equal(t, map[string]any{"n": float64(1)}, events[0]["nested"])
+	assert.IsType(t, time.Time{}, events[0][ingest.TimestampField])
+
+	_, err = client.IngestEvents(t.Context(), "unknown", []axiom.Event{{"foo": "bar"}})
+	assert.ErrorIs(t, err, axiom.ErrNotFound)
+}
+
+func TestServer_Query(t *testing.T) {
+	srv, client := setup(t)
+	srv.CreateDataset("test")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err := client.IngestEvents(t.Context(), "test", []axiom.Event{
+		{ingest.TimestampField: now.Add(-3 * time.Minute), "level": "info", "duration": 10, "user": map[string]any{"id": "a"}},
+		{ingest.TimestampField: now.Add(-2 * time.Minute), "level": "error", "duration": 20, "user": map[string]any{"id": "b"}},
+		{ingest.TimestampField: now.Add(-time.Minute), "level": "info", "duration": 30, "user": map[string]any{"id": "a"}},
+	})
+	require.NoError(t, err)
+
+	res, err := client.Query(t.Context(), "['test'] | where level == 'info' and duration > 15 | project level, duration, ['user.id']")
+	require.NoError(t, err)
+	require.Len(t, res.Tables, 1)
+	assert.EqualValues(t, 3, res.Status.RowsExamined)
+	assert.EqualValues(t, 1, res.Status.RowsMatched)
+
+	table := res.Tables[0]
+	assert.Equal(t, []query.Field{
+		{Name: "level", Type: "string"},
+		{Name: "duration", Type: "integer"},
+		{Name: "user.id", Type: "string"},
+	}, table.Fields)
+	assert.Equal(t, []query.Column{{"info"}, {float64(30)}, {"a"}}, table.Columns)
+
+	res, err = client.Query(t.Context(), "['test'] | summarize count(), total = sum(duration), dcount(user.id) by level | order by level asc")
+	require.NoError(t, err)
+
+	table = res.Tables[0]
+	require.Len(t, table.Fields, 4)
+	assert.Equal(t, "level", table.Fields[0].Name)
+	assert.Equal(t, "count_", table.Fields[1].Name)
+	assert.Equal(t, query.OpCount, table.Fields[1].Aggregation.Op)
+	assert.Equal(t, "total", table.Fields[2].Name)
+	assert.Equal(t, query.OpSum, table.Fields[2].Aggregation.Op)
+	assert.Equal(t, []string{"duration"}, table.Fields[2].Aggregation.Fields)
+	assert.Equal(t, "dcount_user_id", table.Fields[3].Name)
+	assert.Equal(t, query.OpDistinct, table.Fields[3].Aggregation.Op)
+	assert.Equal(t, []query.Group{{Name: "level"}}, table.Groups)
+	assert.Equal(t, []query.Column{
+		{"error", "info"},
+		{float64(1), float64(2)},
+		{float64(20), float64(40)},
+		{float64(1), float64(1)},
+	}, table.Columns)
+
+	res, err = client.Query(t.Context(), "['test'] | count",
+		query.SetStartTime(now.Add(-150*time.Second)),
+		query.SetEndTime(now),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []query.Column{{float64(2)}}, res.Tables[0].Columns)
+
+	_, err = client.Query(t.Context(), "['unknown'] | count")
+	assert.ErrorIs(t, err, axiom.ErrNotFound)
+
+	_, err = client.Query(t.Context(), "['test'] | extend foo = 1")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `unsupported operator "extend"`)
+}
+
+func TestServer_Monitors(t *testing.T) {
+	_, client := setup(t)
+
+	monitor, err := client.Monitors.Create(t.Context(), axiom.MonitorCreateRequest{Monitor: axiom.Monitor{
+		Name:     "Test",
+		APLQuery: "['test'] | count",
+		Interval: time.Minute,
+		Range:    5 * time.Minute,
+	}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, monitor.ID)
+	assert.Equal(t, "Test", monitor.Name)
+	assert.False(t, monitor.CreatedAt.IsZero())
+
+	monitor, err = client.Monitors.Update(t.Context(), monitor.ID, axiom.MonitorUpdateRequest{Monitor: axiom.Monitor{
+		Name:     "Updated",
+		APLQuery: "['test'] | count",
+		Interval: time.Minute,
+		Range:    5 * time.Minute,
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", monitor.Name)
+
+	monitors, err := client.Monitors.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, monitors, 1)
+	assert.Equal(t, monitor.ID, monitors[0].ID)
+	assert.Equal(t, time.Minute, monitors[0].Interval)
+
+	require.NoError(t, client.Monitors.Delete(t.Context(), monitor.ID))
+
+	_, err = client.Monitors.Get(t.Context(), monitor.ID)
+	assert.ErrorIs(t, err, axiom.ErrNotFound)
+}
+
+func TestServer_Notifiers(t *testing.T) {
+	_, client := setup(t)
+
+	notifier, err := client.Notifiers.Create(t.Context(), axiom.Notifier{
+		Name: "Test",
+		Properties: axiom.NotifierProperties{
+			Email: &axiom.EmailConfig{Emails: []string{"john@example.com"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, notifier.ID)
+
+	notifier, err = client.Notifiers.Get(t.Context(), notifier.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"john@example.com"}, notifier.Properties.Email.Emails)
+
+	require.NoError(t, client.Notifiers.Delete(t.Context(), notifier.ID))
+	assert.ErrorIs(t, client.Notifiers.Delete(t.Context(), notifier.ID), axiom.ErrNotFound)
+}
+
+func TestServer_Annotations(t *testing.T) {
+	_, client := setup(t)
+
+	annotation, err := client.Annotations.Create(t.Context(), &axiom.AnnotationCreateRequest{
+		Datasets: []string{"test"},
+		Type:     "deployment",
+		Title:    "v1.0.0",
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(annotation.ID, "ann_"))
+
+	annotations, err := client.Annotations.List(t.Context(), &axiom.AnnotationsFilter{Datasets: []string{"test"}})
+	require.NoError(t, err)
+	require.Len(t, annotations, 1)
+	assert.Equal(t, "v1.0.0", annotations[0].Title)
+}
+
+func TestServer_VirtualFields(t *testing.T) {
+	_, client := setup(t)
+
+	vfield, err := client.VirtualFields.Create(t.Context(), axiom.VirtualField{
+		Dataset:    "test",
+		Name:       "status_failed",
+		Expression: "response_code > 500",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, vfield.ID)
+
+	vfields, err := client.VirtualFields.List(t.Context(), "test")
+	require.NoError(t, err)
+	require.Len(t, vfields, 1)
+	assert.Equal(t, "status_failed", vfields[0].Name)
+
+	vfields, err = client.VirtualFields.List(t.Context(), "other")
+	require.NoError(t, err)
+	assert.Empty(t, vfields)
+}
+
+func TestServer_Tokens(t *testing.T) {
+	_, client := setup(t)
+
+	token, err := client.Tokens.Create(t.Context(), axiom.CreateTokenRequest{
+		Name: "Test",
+		DatasetCapabilities: map[string]axiom.DatasetCapabilities{
+			"test": {Ingest: []axiom.Action{axiom.ActionCreate}},
+		},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.ID)
+	assert.True(t, strings.HasPrefix(token.Token, "xaat-"))
+
+	regenerated, err := client.Tokens.Regenerate(t.Context(), token.ID, axiom.RegenerateTokenRequest{
+		ExistingTokenExpiresAt: time.Now(),
+		NewTokenExpiresAt:      time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, token.ID, regenerated.ID)
+	assert.NotEqual(t, token.Token, regenerated.Token)
+
+	tokens, err := client.Tokens.List(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, tokens, 2)
+
+	apiToken, err := client.Tokens.Get(t.Context(), regenerated.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Test", apiToken.Name)
+	assert.Equal(t, []axiom.Action{axiom.ActionCreate}, apiToken.DatasetCapabilities["test"].Ingest)
+}
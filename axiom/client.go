@@ -11,6 +11,8 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,6 +28,7 @@ import (
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 	"github.com/axiomhq/axiom-go/axiom/query"
 	"github.com/axiomhq/axiom-go/axiom/querylegacy"
+	"github.com/axiomhq/axiom-go/axiom/redact"
 	"github.com/axiomhq/axiom-go/internal/config"
 	"github.com/axiomhq/axiom-go/internal/version"
 )
@@ -88,10 +91,20 @@ type Client struct {
 	noEnv      bool
 	noRetry    bool
 
+	profile     string
+	profileFile string
+
+	credentialsProvider CredentialsProvider
+	credentialsTTL      time.Duration
+	credentials         *credentialsCache
+
 	strictDecoding bool
 
 	tracer trace.Tracer
 
+	processors []ingest.Processor
+	redactor   *redact.Redactor
+
 	// Services for communicating with different parts of the Axiom API.
 	Datasets      *DatasetsService
 	Dashboards    *DashboardsService
@@ -112,18 +125,25 @@ type Client struct {
 //   - AXIOM_ORG_ID (only when using a personal token)
 //
 // The configuration can be set manually using options which are prefixed with
-// "Set".
+// "Set". It can also be read from a profile of the Axiom CLI configuration
+// file, selected using [SetProfile] or the "AXIOM_DEPLOYMENT" environment
+// variable. Explicit options take precedence over the environment, which takes
+// precedence over the profile.
 //
 // The token must be an api or personal token which can be created on the
 // settings or user profile page on Axiom.
 func NewClient(options ...Option) (*Client, error) {
 	client := &Client{
-		config: config.Default(),
+		// Only holds explicitly configured values until merged with the
+		// profile and the environment below.
+		config: config.Config{},
 
 		httpClient: DefaultHTTPClient(),
 
 		userAgent: "axiom-go",
 
+		credentialsTTL: DefaultCredentialsTTL,
+
 		tracer: otel.Tracer(otelTracerName),
 	}
 
@@ -147,17 +167,55 @@ func NewClient(options ...Option) (*Client, error) {
 		return nil, err
 	}
 
-	// Make sure to populate remaining fields from the environment, if not
+	// Populate remaining fields from the profile and the environment, if not
 	// explicitly disabled.
+	cfg := config.Default()
+	if err := client.incorporateProfile(&cfg); err != nil {
+		return nil, err
+	}
 	if !client.noEnv {
-		if err := client.config.IncorporateEnvironment(); err != nil {
+		if err := cfg.IncorporateEnvironment(); err != nil {
 			return nil, err
 		}
 	}
+	cfg.Incorporate(client.config)
+	client.config = cfg
+
+	// Credentials are only known at request time, when they are taken from a
+	// provider. They are validated when they are first used.
+	if client.credentialsProvider != nil {
+		client.credentials = newCredentialsCache(client.credentialsProvider, client.credentialsTTL,
+			func(creds Credentials) error {
+				return client.config.ValidateCredentials(creds.Token, creds.OrganizationID)
+			},
+		)
+		return client, client.config.ValidateEndpoints()
+	}
 
 	return client, client.config.Validate()
 }
 
+// incorporateProfile loads the selected profile into the given configuration.
+// It is a no-op if neither a profile nor a profile file is configured.
+func (c *Client) incorporateProfile(cfg *config.Config) error {
+	name := c.profile
+	if name == "" && !c.noEnv {
+		name = os.Getenv("AXIOM_DEPLOYMENT")
+	}
+	if name == "" && c.profileFile == "" {
+		return nil
+	}
+
+	path := c.profileFile
+	if path == "" {
+		var err error
+		if path, err = config.DefaultProfileFile(); err != nil {
+			return err
+		}
+	}
+	return cfg.IncorporateProfile(path, name)
+}
+
 // Options applies options to the client.
 func (c *Client) Options(options ...Option) error {
 	for _, option := range options {
@@ -170,11 +228,45 @@ func (c *Client) Options(options ...Option) error {
 	return nil
 }
 
+// EventProcessor returns the processor that is run on every event ingested
+// using [DatasetsService.IngestEvents] and [DatasetsService.IngestChannel] with
+// the given options: the processors configured using [SetProcessors], the ones
+// passed using [ingest.AddProcessors] and the redactor configured using
+// [SetRedactor], in that order. It returns nil, if there is nothing to run.
+// Integrations that encode events themselves use it to process events just
+// like the client does.
+func (c *Client) EventProcessor(options ...ingest.Option) ingest.Processor {
+	var opts ingest.Options
+	for _, option := range options {
+		if option != nil {
+			option(&opts)
+		}
+	}
+	return c.eventProcessor(opts.Processors)
+}
+
+func (c *Client) eventProcessor(processors []ingest.Processor) ingest.Processor {
+	if len(c.processors) == 0 && len(processors) == 0 && c.redactor == nil {
+		return nil
+	}
+
+	chain := slices.Concat(c.processors, processors)
+	if c.redactor != nil {
+		chain = append(chain, c.redactor)
+	}
+	return ingest.Chain(chain...)
+}
+
 // ValidateCredentials makes sure the client can properly authenticate against
 // the configured Axiom API.
 func (c *Client) ValidateCredentials(ctx context.Context) error {
-	if config.IsPersonalToken(c.config.Token()) {
-		_, err := c.Users.Current(ctx)
+	creds, err := c.currentCredentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	if config.IsPersonalToken(creds.Token) {
+		_, err = c.Users.Current(ctx)
 		return err
 	}
 
@@ -231,15 +323,12 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body any)
 		req.Header.Set(headerContentType, defaultMediaType)
 	}
 
-	// Set authorization header, if present.
-	if c.config.Token() != "" {
-		req.Header.Set(headerAuthorization, "Bearer "+c.config.Token())
-	}
-
-	// Set organization ID header when using a personal token.
-	if config.IsPersonalToken(c.config.Token()) && c.config.OrganizationID() != "" {
-		req.Header.Set(headerOrganizationID, c.config.OrganizationID())
+	// Set authorization and organization ID header.
+	creds, err := c.currentCredentials(ctx)
+	if err != nil {
+		return nil, err
 	}
+	setCredentials(req.Header, creds)
 
 	// Set other headers.
 	req.Header.Set(headerAccept, mediaTypeJSON)
@@ -248,10 +337,85 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body any)
 	return req, nil
 }
 
+// currentCredentials returns the credentials requests are currently
+// authenticated with: the configured ones or the ones of the
+// [CredentialsProvider], if set.
+func (c *Client) currentCredentials(ctx context.Context) (Credentials, error) {
+	if c.credentials != nil {
+		return c.credentials.get(ctx)
+	}
+	return Credentials{
+		Token:          c.config.Token(),
+		OrganizationID: c.config.OrganizationID(),
+	}, nil
+}
+
+// checkEdgeCredentials makes sure requests are authenticated with an API token,
+// as edge endpoints don't support personal tokens.
+func (c *Client) checkEdgeCredentials(ctx context.Context) error {
+	creds, err := c.currentCredentials(ctx)
+	if err != nil {
+		return err
+	} else if config.IsPersonalToken(creds.Token) {
+		return config.ErrPersonalTokenNotSupportedForEdge
+	}
+	return nil
+}
+
+// setCredentials sets the authorization header, if a token is present, and the
+// organization ID header, when using a personal token.
+func setCredentials(header http.Header, creds Credentials) {
+	if creds.Token != "" {
+		header.Set(headerAuthorization, "Bearer "+creds.Token)
+	}
+	if config.IsPersonalToken(creds.Token) && creds.OrganizationID != "" {
+		header.Set(headerOrganizationID, creds.OrganizationID)
+	}
+}
+
 // Do sends an API request and returns the API response. The response body is
 // JSON decoded or directly written to v, depending on v being an [io.Writer] or
-// not.
+// not. If the client takes its credentials from a [CredentialsProvider] and the
+// request fails with status 401 or 403, it is retried once with refreshed
+// credentials.
 func (c *Client) Do(req *http.Request, v any) (*Response, error) {
+	resp, err := c.do(req, v)
+	if c.credentials == nil || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return resp, err
+	}
+
+	var httpErr HTTPError
+	if !errors.As(err, &httpErr) || (httpErr.Status != http.StatusUnauthorized && httpErr.Status != http.StatusForbidden) {
+		return resp, err
+	}
+
+	// Only retry if the credentials actually changed.
+	c.credentials.invalidate()
+	creds, credsErr := c.credentials.get(req.Context())
+	if credsErr != nil || req.Header.Get(headerAuthorization) == "Bearer "+creds.Token {
+		return resp, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, err
+		}
+	}
+
+	// Keep the organization ID header removed, if the request explicitly
+	// doesn't want it to be sent.
+	hasOrganizationID := req.Header.Get(headerOrganizationID) != ""
+	retry.Header.Del(headerOrganizationID)
+	setCredentials(retry.Header, creds)
+	if !hasOrganizationID {
+		retry.Header.Del(headerOrganizationID)
+	}
+
+	return c.do(retry, v)
+}
+
+func (c *Client) do(req *http.Request, v any) (*Response, error) {
 	var (
 		resp *Response
 		err  error
@@ -267,7 +431,8 @@ func (c *Client) Do(req *http.Request, v any) (*Response, error) {
 			//nolint:bodyclose,gosec // The response body is closed later down below. G704: URL is from trusted configuration.
 			httpResp, err = c.httpClient.Do(req)
 			switch {
-			case errors.Is(err, context.Canceled):
+			case errors.Is(err, context.Canceled), req.Context().Err() != nil:
+				// Retrying is pointless once the request context is done.
 				return backoff.Permanent(err)
 			case err != nil:
 				// Reset the request body so it can be re-read on
@@ -1,10 +1,14 @@
 package axiom
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"go.opentelemetry.io/otel/trace/noop"
 
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/axiom/redact"
 	"github.com/axiomhq/axiom-go/internal/config"
 )
 
@@ -16,14 +20,16 @@ type Option func(c *Client) error
 
 // SetURL specifies the base URL used by the [Client].
 //
-// Can also be specified using the "AXIOM_URL" environment variable.
+// Can also be specified using the "AXIOM_URL" environment variable. The option
+// takes precedence over the environment variable.
 func SetURL(baseURL string) Option {
 	return func(c *Client) error { return c.config.Options(config.SetURL(baseURL)) }
 }
 
 // SetToken specifies the token used by the [Client].
 //
-// Can also be specified using the "AXIOM_TOKEN" environment variable.
+// Can also be specified using the "AXIOM_TOKEN" environment variable. The option
+// takes precedence over the environment variable.
 func SetToken(accessToken string) Option {
 	return func(c *Client) error { return c.config.Options(config.SetToken(accessToken)) }
 }
@@ -33,7 +39,8 @@ func SetToken(accessToken string) Option {
 // When a personal token is used, this method can be used to switch between
 // organizations by passing it to the [Client.Options] method.
 //
-// Can also be specified using the "AXIOM_ORG_ID" environment variable.
+// Can also be specified using the "AXIOM_ORG_ID" environment variable. The option
+// takes precedence over the environment variable.
 func SetOrganizationID(organizationID string) Option {
 	return func(c *Client) error { return c.config.Options(config.SetOrganizationID(organizationID)) }
 }
@@ -84,6 +91,56 @@ func SetNoEnv() Option {
 	}
 }
 
+// SetCredentialsProvider specifies the [CredentialsProvider] the [Client]
+// takes its credentials from, instead of a static token and organization ID.
+// Credentials are cached for [DefaultCredentialsTTL], unless configured
+// otherwise using [SetCredentialsTTL]. If a request fails with status 401 or
+// 403, the credentials are fetched again and the request is retried once, if
+// they changed. This allows long-running services to pick up rotated tokens.
+func SetCredentialsProvider(provider CredentialsProvider) Option {
+	return func(c *Client) error {
+		c.credentialsProvider = provider
+		return nil
+	}
+}
+
+// SetCredentialsTTL specifies how long the [Client] caches the credentials of
+// the [CredentialsProvider] set using [SetCredentialsProvider]. A zero
+// duration disables caching.
+func SetCredentialsTTL(ttl time.Duration) Option {
+	return func(c *Client) error {
+		if ttl < 0 {
+			return errors.New("credentials ttl must not be negative")
+		}
+		c.credentialsTTL = ttl
+		return nil
+	}
+}
+
+// SetProfile selects the profile (called deployment by the Axiom CLI) the
+// [Client] takes its configuration from. Profiles are read from the TOML file
+// set using [SetProfileFile], which defaults to "~/.axiom.toml", the file the
+// official Axiom CLI uses. Values in the profile are overwritten by values
+// from the environment and by explicit options.
+//
+// Can also be specified using the "AXIOM_DEPLOYMENT" environment variable.
+func SetProfile(name string) Option {
+	return func(c *Client) error {
+		c.profile = name
+		return nil
+	}
+}
+
+// SetProfileFile specifies the TOML file profiles are read from. If no profile
+// is selected using [SetProfile] or the "AXIOM_DEPLOYMENT" environment
+// variable, the active deployment of the file is used.
+func SetProfileFile(path string) Option {
+	return func(c *Client) error {
+		c.profileFile = path
+		return nil
+	}
+}
+
 // SetNoRetry prevents the [Client] from auto-retrying failed HTTP requests
 // under certain circumstances.
 func SetNoRetry() Option {
@@ -111,7 +168,8 @@ func SetNoTracing() Option {
 // requests are sent to "{edgeURL}/v1/query/_apl".
 // This takes precedence over [SetEdge] if both are set.
 //
-// Can also be specified using the "AXIOM_EDGE_URL" environment variable.
+// Can also be specified using the "AXIOM_EDGE_URL" environment variable. The option
+// takes precedence over the environment variable.
 func SetEdgeURL(edgeURL string) Option {
 	return func(c *Client) error { return c.config.Options(config.SetEdgeURL(edgeURL)) }
 }
@@ -121,7 +179,33 @@ func SetEdgeURL(edgeURL string) Option {
 // When set, ingest and query requests are sent to "https://{edge}/v1/ingest/{dataset}"
 // and "https://{edge}/v1/query/_apl" respectively.
 //
-// Can also be specified using the "AXIOM_EDGE" environment variable.
+// Can also be specified using the "AXIOM_EDGE" environment variable. The option
+// takes precedence over the environment variable.
 func SetEdge(edge string) Option {
 	return func(c *Client) error { return c.config.Options(config.SetEdge(edge)) }
 }
+
+// SetProcessors specifies the processors that are run on all events ingested
+// using [DatasetsService.IngestEvents] and [DatasetsService.IngestChannel],
+// before the ones passed using [ingest.AddProcessors]. Events are never
+// modified in place. Data ingested using [DatasetsService.Ingest] is not
+// processed.
+func SetProcessors(processors ...ingest.Processor) Option {
+	return func(c *Client) error {
+		c.processors = processors
+		return nil
+	}
+}
+
+// SetRedactor specifies the [redact.Redactor] that is applied to all events
+// ingested using [DatasetsService.IngestEvents] and
+// [DatasetsService.IngestChannel] before they are sent to Axiom. It runs after
+// all other processors, so it also covers the fields they add. Events are
+// never modified in place. Data ingested using [DatasetsService.Ingest] is not
+// redacted.
+func SetRedactor(redactor *redact.Redactor) Option {
+	return func(c *Client) error {
+		c.redactor = redactor
+		return nil
+	}
+}
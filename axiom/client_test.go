@@ -2,12 +2,15 @@ package axiom
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -216,6 +219,131 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClient_Profile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".axiom.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+active_deployment = "production"
+
+[deployments.production]
+url = "https://api.axiom.co"
+token = "`+personalToken+`"
+org_id = "production"
+
+[deployments.staging]
+url = "`+endpoint+`"
+token = "`+personalToken+`"
+org_id = "staging"
+`), 0o600))
+
+	tests := []struct {
+		name        string
+		environment map[string]string
+		options     []Option
+		wantURL     string
+		wantOrgID   string
+		err         error
+	}{
+		{
+			name:      "active deployment",
+			options:   []Option{SetProfileFile(path)},
+			wantURL:   "https://api.axiom.co",
+			wantOrgID: "production",
+		},
+		{
+			name:        "deployment environment",
+			environment: map[string]string{"AXIOM_DEPLOYMENT": "staging"},
+			options:     []Option{SetProfileFile(path)},
+			wantURL:     endpoint,
+			wantOrgID:   "staging",
+		},
+		{
+			name:        "profile option takes precedence over deployment environment",
+			environment: map[string]string{"AXIOM_DEPLOYMENT": "staging"},
+			options:     []Option{SetProfileFile(path), SetProfile("production")},
+			wantURL:     "https://api.axiom.co",
+			wantOrgID:   "production",
+		},
+		{
+			name:        "environment takes precedence over profile",
+			environment: map[string]string{"AXIOM_ORG_ID": organizationID},
+			options:     []Option{SetProfileFile(path), SetProfile("staging")},
+			wantURL:     endpoint,
+			wantOrgID:   organizationID,
+		},
+		{
+			name:        "options take precedence over environment",
+			environment: map[string]string{"AXIOM_ORG_ID": "environment"},
+			options:     []Option{SetProfileFile(path), SetProfile("staging"), SetOrganizationID(organizationID)},
+			wantURL:     endpoint,
+			wantOrgID:   organizationID,
+		},
+		{
+			name:        "noEnv ignores deployment environment",
+			environment: map[string]string{"AXIOM_DEPLOYMENT": "staging"},
+			options:     []Option{SetNoEnv(), SetProfileFile(path)},
+			wantURL:     "https://api.axiom.co",
+			wantOrgID:   "production",
+		},
+		{
+			name:    "unknown profile",
+			options: []Option{SetProfileFile(path), SetProfile("development")},
+			err:     config.ErrProfileNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testhelper.SafeClearEnv(t)
+
+			for k, v := range tt.environment {
+				t.Setenv(k, v)
+			}
+
+			client, err := NewClient(tt.options...)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantURL, client.config.BaseURL().String())
+			assert.Equal(t, tt.wantOrgID, client.config.OrganizationID())
+			assert.Equal(t, personalToken, client.config.Token())
+		})
+	}
+}
+
+func TestNewClient_OptionsPrecedence(t *testing.T) {
+	testhelper.SafeClearEnv(t)
+
+	t.Setenv("AXIOM_URL", "https://dev.axiom.co")
+	t.Setenv("AXIOM_TOKEN", personalToken)
+	t.Setenv("AXIOM_ORG_ID", "environment")
+
+	client, err := NewClient(
+		SetURL(endpoint),
+		SetToken(apiToken),
+		SetOrganizationID(organizationID),
+	)
+	require.NoError(t, err)
+
+	// Explicit options take precedence over the environment.
+	assert.Equal(t, endpoint, client.config.BaseURL().String())
+	assert.Equal(t, apiToken, client.config.Token())
+	assert.Equal(t, organizationID, client.config.OrganizationID())
+}
+
+func TestNewClient_EdgePrecedence(t *testing.T) {
+	testhelper.SafeClearEnv(t)
+
+	t.Setenv("AXIOM_TOKEN", apiToken)
+	t.Setenv("AXIOM_EDGE_URL", "https://edge.example.com")
+
+	client, err := NewClient(SetEdge("eu-central-1.aws.edge.axiom.co"))
+	require.NoError(t, err)
+
+	// The explicit edge domain wins over the edge URL of the environment.
+	assert.Equal(t, "https://eu-central-1.aws.edge.axiom.co/v1/ingest/test", client.config.EdgeIngestURL("test").String())
+}
+
 func TestNewClient_Valid(t *testing.T) {
 	client := newClient(t)
 
@@ -787,6 +915,28 @@ func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
 
+func TestClient_Do_Backoff_ContextDone(t *testing.T) {
+	client := setup(t, "POST /", func(http.ResponseWriter, *http.Request) {
+		t.Error("no request expected")
+	})
+
+	var transportAttempts int
+	client.httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		transportAttempts++
+		return nil, req.Context().Err()
+	})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 0)
+	defer cancel()
+
+	req, err := client.NewRequest(ctx, http.MethodPost, "/", strings.NewReader(`{"foo":"bar"}`))
+	require.NoError(t, err)
+
+	_, err = client.Do(req, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, transportAttempts)
+}
+
 func TestClient_Do_Backoff_NoRetryOn400(t *testing.T) {
 	var currentCalls int
 	hf := func(w http.ResponseWriter, _ *http.Request) {
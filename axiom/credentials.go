@@ -0,0 +1,179 @@
+package axiom
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCredentialsTTL is the default duration credentials returned by a
+// [CredentialsProvider] are cached by the [Client].
+const DefaultCredentialsTTL = time.Minute
+
+// Credentials used to authenticate against the Axiom API.
+type Credentials struct {
+	// Token is the API or personal token.
+	Token string
+	// OrganizationID is the ID of the organization. Only required for personal
+	// tokens.
+	OrganizationID string
+}
+
+// A CredentialsProvider provides the [Credentials] used by a [Client]. It is
+// queried when a request is created, if the cached credentials expired, and
+// when a request fails with status 401 or 403. Implementations must be safe
+// for concurrent use.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsProviderFunc is a function that implements
+// [CredentialsProvider].
+type CredentialsProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials implements [CredentialsProvider].
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// StaticCredentials returns a [CredentialsProvider] that always provides the
+// given credentials.
+func StaticCredentials(token, organizationID string) CredentialsProvider {
+	return CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{Token: token, OrganizationID: organizationID}, nil
+	})
+}
+
+// EnvCredentials returns a [CredentialsProvider] that reads the credentials
+// from the "AXIOM_TOKEN" and "AXIOM_ORG_ID" environment variables every time
+// it is queried.
+func EnvCredentials() CredentialsProvider {
+	return CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{
+			Token:          os.Getenv("AXIOM_TOKEN"),
+			OrganizationID: os.Getenv("AXIOM_ORG_ID"),
+		}, nil
+	})
+}
+
+// FileCredentials returns a [CredentialsProvider] that reads the token and,
+// optionally, the organization ID from the files at the given paths, like the
+// ones of a mounted Kubernetes secret. Surrounding whitespace is trimmed. The
+// files are read again whenever they change, so rotated tokens are picked up
+// without a restart.
+func FileCredentials(tokenPath, organizationIDPath string) CredentialsProvider {
+	return &fileCredentials{
+		token:          watchedFile{path: tokenPath},
+		organizationID: watchedFile{path: organizationIDPath},
+	}
+}
+
+type fileCredentials struct {
+	mu             sync.Mutex
+	token          watchedFile
+	organizationID watchedFile
+}
+
+// Credentials implements [CredentialsProvider].
+func (fc *fileCredentials) Credentials(context.Context) (Credentials, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	token, err := fc.token.read()
+	if err != nil {
+		return Credentials{}, err
+	}
+	organizationID, err := fc.organizationID.read()
+	if err != nil {
+		return Credentials{}, err
+	}
+	return Credentials{Token: token, OrganizationID: organizationID}, nil
+}
+
+// watchedFile caches the content of a file until its modification time or
+// size changes.
+type watchedFile struct {
+	path    string
+	modTime time.Time
+	size    int64
+	content string
+}
+
+func (f *watchedFile) read() (string, error) {
+	if f.path == "" {
+		return "", nil
+	}
+
+	fi, err := os.Stat(f.path)
+	if err != nil {
+		return "", err
+	} else if fi.ModTime().Equal(f.modTime) && fi.Size() == f.size {
+		return f.content, nil
+	}
+
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", err
+	}
+	f.modTime, f.size, f.content = fi.ModTime(), fi.Size(), strings.TrimSpace(string(b))
+
+	return f.content, nil
+}
+
+// credentialsCache caches the credentials of a provider for a limited time.
+type credentialsCache struct {
+	provider CredentialsProvider
+	ttl      time.Duration
+	validate func(Credentials) error
+	now      func() time.Time
+
+	mu        sync.Mutex
+	creds     Credentials
+	expiresAt time.Time
+}
+
+// newCredentialsCache creates a cache for the credentials of the given
+// provider. Credentials are checked using the given function before they are
+// cached.
+func newCredentialsCache(provider CredentialsProvider, ttl time.Duration, validate func(Credentials) error) *credentialsCache {
+	return &credentialsCache{
+		provider: provider,
+		ttl:      ttl,
+		validate: validate,
+		now:      time.Now,
+	}
+}
+
+// get returns the cached credentials or fetches them from the provider, if
+// they expired.
+func (cc *credentialsCache) get(ctx context.Context) (Credentials, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if now := cc.now(); now.Before(cc.expiresAt) {
+		return cc.creds, nil
+	}
+
+	creds, err := cc.provider.Credentials(ctx)
+	if err == nil {
+		err = cc.validate(creds)
+	}
+	if err != nil {
+		return Credentials{}, fmt.Errorf("get credentials: %w", err)
+	}
+
+	cc.creds, cc.expiresAt = creds, cc.now().Add(cc.ttl)
+
+	return creds, nil
+}
+
+// invalidate makes sure the credentials are fetched from the provider on the
+// next call to get.
+func (cc *credentialsCache) invalidate() {
+	cc.mu.Lock()
+	cc.expiresAt = time.Time{}
+	cc.mu.Unlock()
+}
@@ -0,0 +1,210 @@
+package axiom
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/internal/config"
+)
+
+const rotatedToken = "xaat-YYYYYYYY-YYYY-YYYY-YYYY-YYYYYYYYYYYY"
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("AXIOM_TOKEN", personalToken)
+	t.Setenv("AXIOM_ORG_ID", organizationID)
+
+	creds, err := EnvCredentials().Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Token: personalToken, OrganizationID: organizationID}, creds)
+}
+
+func TestFileCredentials(t *testing.T) {
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "token")
+	orgPath := filepath.Join(dir, "org-id")
+	require.NoError(t, os.WriteFile(tokenPath, []byte(personalToken+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(orgPath, []byte(organizationID), 0o600))
+
+	provider := FileCredentials(tokenPath, orgPath)
+
+	creds, err := provider.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Token: personalToken, OrganizationID: organizationID}, creds)
+
+	// Rotate the token and make sure the change is picked up.
+	require.NoError(t, os.WriteFile(tokenPath, []byte(rotatedToken), 0o600))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(tokenPath, future, future))
+
+	creds, err = provider.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, rotatedToken, creds.Token)
+
+	creds, err = FileCredentials(tokenPath, "").Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, creds.OrganizationID)
+
+	_, err = FileCredentials(filepath.Join(dir, "missing"), "").Credentials(t.Context())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCredentialsCache(t *testing.T) {
+	var calls int
+	provider := CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		calls++
+		return Credentials{Token: apiToken}, nil
+	})
+
+	now := time.Now()
+	cc := newCredentialsCache(provider, time.Minute, validateCredentials)
+	cc.now = func() time.Time { return now }
+
+	for range 3 {
+		creds, err := cc.get(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, apiToken, creds.Token)
+	}
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(time.Minute)
+	_, err := cc.get(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	cc.invalidate()
+	_, err = cc.get(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestCredentialsCache_Error(t *testing.T) {
+	errTest := errors.New("test")
+
+	tests := []struct {
+		name   string
+		creds  Credentials
+		err    error
+		errMsg string
+	}{
+		{name: "provider error", err: errTest, errMsg: "get credentials: test"},
+		{name: "missing token", errMsg: "get credentials: missing token"},
+		{name: "invalid token", creds: Credentials{Token: "abc"}, errMsg: "get credentials: invalid token"},
+		{name: "missing organization id", creds: Credentials{Token: personalToken}, errMsg: "get credentials: missing organization id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := newCredentialsCache(CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+				return tt.creds, tt.err
+			}), time.Minute, validateCredentials)
+
+			_, err := cc.get(t.Context())
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestClient_CredentialsProvider(t *testing.T) {
+	var (
+		requests atomic.Int32
+		token    atomic.Value
+	)
+	token.Store(apiToken)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if r.Header.Get("Authorization") != "Bearer "+rotatedToken {
+			w.Header().Set("Content-Type", mediaTypeJSON)
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"invalid token"}`))
+			return
+		}
+
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), `"name":"test"`)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, _ = w.Write([]byte(`{"id":"test","name":"test"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(
+		SetNoEnv(),
+		SetURL(srv.URL),
+		SetCredentialsProvider(CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+			return Credentials{Token: token.Load().(string)}, nil
+		})),
+	)
+	require.NoError(t, err)
+
+	// The token doesn't change, so the request is not retried.
+	_, err = client.Datasets.Create(t.Context(), DatasetCreateRequest{Name: "test"})
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	assert.EqualValues(t, 1, requests.Load())
+
+	// The token is rotated but the old one is still cached. The request fails,
+	// the new token is fetched and the request retried, including its body.
+	token.Store(rotatedToken)
+	requests.Store(0)
+
+	dataset, err := client.Datasets.Create(t.Context(), DatasetCreateRequest{Name: "test"})
+	require.NoError(t, err)
+	assert.Equal(t, "test", dataset.Name)
+	assert.EqualValues(t, 2, requests.Load())
+
+	// The new token is cached.
+	requests.Store(0)
+	_, err = client.Datasets.Create(t.Context(), DatasetCreateRequest{Name: "test"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, requests.Load())
+}
+
+func TestNewClient_CredentialsProvider_Invalid(t *testing.T) {
+	provider := StaticCredentials(apiToken, "")
+
+	_, err := NewClient(SetNoEnv(), SetURL("/api"), SetCredentialsProvider(provider))
+	assert.ErrorIs(t, err, config.ErrInvalidURL)
+
+	_, err = NewClient(SetNoEnv(), SetEdge("https://eu-central-1.aws.edge.axiom.co"), SetCredentialsProvider(provider))
+	assert.ErrorIs(t, err, config.ErrInvalidEdge)
+}
+
+func TestClient_CredentialsProvider_Edge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("no request expected")
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(
+		SetNoEnv(),
+		SetURL(srv.URL),
+		SetEdgeURL(srv.URL),
+		SetCredentialsProvider(StaticCredentials(personalToken, "my-org")),
+	)
+	require.NoError(t, err)
+
+	// Edge endpoints reject the personal token of the provider.
+	_, err = client.IngestEvents(t.Context(), "test", []Event{{"foo": "bar"}})
+	assert.ErrorIs(t, err, config.ErrPersonalTokenNotSupportedForEdge)
+
+	_, err = client.Query(t.Context(), "['test']")
+	assert.ErrorIs(t, err, config.ErrPersonalTokenNotSupportedForEdge)
+}
+
+// validateCredentials validates the given credentials against the default
+// configuration.
+func validateCredentials(creds Credentials) error {
+	return config.Default().ValidateCredentials(creds.Token, creds.OrganizationID)
+}
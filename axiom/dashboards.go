@@ -5,11 +5,250 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/url"
+	"reflect"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// ChartType is the type of a [Chart]. Types unknown to this package are
+// preserved as is.
+type ChartType string
+
+// All known [ChartType]s.
+const (
+	ChartTypeTimeSeries    ChartType = "TimeSeries"
+	ChartTypeHeatmap       ChartType = "Heatmap"
+	ChartTypeLogStream     ChartType = "LogStream"
+	ChartTypePie           ChartType = "Pie"
+	ChartTypeScatter       ChartType = "Scatter"
+	ChartTypeTable         ChartType = "Table"
+	ChartTypeTopK          ChartType = "TopK"
+	ChartTypeStatistic     ChartType = "Statistic"
+	ChartTypeSectionHeader ChartType = "SectionHeader"
+	ChartTypeNote          ChartType = "Note"
+	ChartTypeMonitorList   ChartType = "MonitorList"
+	ChartTypeSmartFilter   ChartType = "SmartFilter"
+	ChartTypeSpacer        ChartType = "Spacer"
+	ChartTypePlaceholder   ChartType = "Placeholder"
+)
+
+// Dashboard is the definition of a dashboard.
+//
+// All dashboard types keep the fields of the Axiom API they don't know about
+// in their Extra field and marshal them again, so dashboards can be read,
+// modified and written back without losing data, even if the dashboard schema
+// was extended on the server.
+type Dashboard struct {
+	// Name of the dashboard.
+	Name string `json:"name"`
+	// Owner is the ID of the user or team owning the dashboard.
+	Owner string `json:"owner,omitempty"`
+	// Description of the dashboard.
+	Description string `json:"description,omitempty"`
+	// Charts of the dashboard.
+	Charts []Chart `json:"charts,omitempty"`
+	// Layout places the charts on the dashboard grid.
+	Layout []Layout `json:"layout,omitempty"`
+	// RefreshTime is the interval in seconds in which the dashboard is
+	// refreshed.
+	RefreshTime int `json:"refreshTime,omitempty"`
+	// SchemaVersion is the version of the dashboard schema.
+	SchemaVersion int `json:"schemaVersion,omitempty"`
+	// TimeWindowStart is the start of the time window shown by default, e.g.
+	// "qr-now-1h".
+	TimeWindowStart string `json:"timeWindowStart,omitempty"`
+	// TimeWindowEnd is the end of the time window shown by default, e.g.
+	// "qr-now".
+	TimeWindowEnd string `json:"timeWindowEnd,omitempty"`
+
+	// Extra holds the fields unknown to this package.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the
+// unknown fields kept in Extra.
+func (d Dashboard) MarshalJSON() ([]byte, error) {
+	type localDashboard Dashboard
+	return marshalWithExtra(localDashboard(d), d.Extra)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to keep the
+// unknown fields in Extra.
+func (d *Dashboard) UnmarshalJSON(b []byte) (err error) {
+	type localDashboard Dashboard
+	d.Extra, err = unmarshalWithExtra(b, (*localDashboard)(d))
+	return err
+}
+
+// Chart is a chart of a [Dashboard]. Which fields are used depends on its
+// type.
+type Chart struct {
+	// ID of the chart, unique within the dashboard. It is referenced by the
+	// [Layout].
+	ID string `json:"id"`
+	// Type of the chart.
+	Type ChartType `json:"type"`
+	// Name of the chart.
+	Name string `json:"name,omitempty"`
+	// DatasetID is the ID of the dataset queried by the chart.
+	DatasetID string `json:"datasetId,omitempty"`
+	// Query of the chart.
+	Query *ChartQuery `json:"query,omitempty"`
+	// Text of a [ChartTypeNote] chart.
+	Text string `json:"text,omitempty"`
+	// SelectedMonitors are the IDs of the monitors shown by a
+	// [ChartTypeMonitorList] chart.
+	SelectedMonitors []string `json:"selectedMonitors,omitempty"`
+	// Columns shown by a [ChartTypeMonitorList] chart, e.g. "status".
+	Columns map[string]bool `json:"columns,omitempty"`
+	// Filters of a [ChartTypeSmartFilter] chart.
+	Filters []map[string]any `json:"filters,omitempty"`
+
+	// Extra holds the fields unknown to this package.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the
+// unknown fields kept in Extra.
+func (c Chart) MarshalJSON() ([]byte, error) {
+	type localChart Chart
+	return marshalWithExtra(localChart(c), c.Extra)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to keep the
+// unknown fields in Extra.
+func (c *Chart) UnmarshalJSON(b []byte) (err error) {
+	type localChart Chart
+	c.Extra, err = unmarshalWithExtra(b, (*localChart)(c))
+	return err
+}
+
+// ChartQuery is the query of a [Chart].
+type ChartQuery struct {
+	// APL query of the chart.
+	APL string `json:"apl"`
+
+	// Extra holds the fields unknown to this package.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the
+// unknown fields kept in Extra.
+func (q ChartQuery) MarshalJSON() ([]byte, error) {
+	type localChartQuery ChartQuery
+	return marshalWithExtra(localChartQuery(q), q.Extra)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to keep the
+// unknown fields in Extra.
+func (q *ChartQuery) UnmarshalJSON(b []byte) (err error) {
+	type localChartQuery ChartQuery
+	q.Extra, err = unmarshalWithExtra(b, (*localChartQuery)(q))
+	return err
+}
+
+// Layout places a [Chart] on the grid of a [Dashboard].
+type Layout struct {
+	// ChartID is the ID of the chart.
+	ChartID string `json:"i"`
+	// X is the column of the top left corner of the chart.
+	X int `json:"x"`
+	// Y is the row of the top left corner of the chart.
+	Y int `json:"y"`
+	// W is the width of the chart in columns.
+	W int `json:"w"`
+	// H is the height of the chart in rows.
+	H int `json:"h"`
+
+	// Extra holds the fields unknown to this package.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the
+// unknown fields kept in Extra.
+func (l Layout) MarshalJSON() ([]byte, error) {
+	type localLayout Layout
+	return marshalWithExtra(localLayout(l), l.Extra)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to keep the
+// unknown fields in Extra.
+func (l *Layout) UnmarshalJSON(b []byte) (err error) {
+	type localLayout Layout
+	l.Extra, err = unmarshalWithExtra(b, (*localLayout)(l))
+	return err
+}
+
+// DashboardWithID is a [Dashboard] as stored by the Axiom API.
+type DashboardWithID struct {
+	// UID is the unique identifier of the dashboard, used to address it.
+	UID string `json:"uid"`
+	// ID of the dashboard.
+	ID string `json:"id"`
+	// Version of the dashboard. It is incremented by every update.
+	Version int `json:"version"`
+	// Dashboard is the definition of the dashboard.
+	Dashboard Dashboard `json:"dashboard"`
+	// CreatedAt is the time when the dashboard was created.
+	CreatedAt time.Time `json:"createdAt"`
+	// UpdatedAt is the time when the dashboard was last updated.
+	UpdatedAt time.Time `json:"updatedAt"`
+	// CreatedBy is the ID of the user who created the dashboard.
+	CreatedBy string `json:"createdBy"`
+	// UpdatedBy is the ID of the user who last updated the dashboard.
+	UpdatedBy string `json:"updatedBy"`
+
+	// Extra holds the fields unknown to this package.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the
+// unknown fields kept in Extra.
+func (d DashboardWithID) MarshalJSON() ([]byte, error) {
+	type localDashboardWithID DashboardWithID
+	return marshalWithExtra(localDashboardWithID(d), d.Extra)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to keep the
+// unknown fields in Extra.
+func (d *DashboardWithID) UnmarshalJSON(b []byte) (err error) {
+	type localDashboardWithID DashboardWithID
+	d.Extra, err = unmarshalWithExtra(b, (*localDashboardWithID)(d))
+	return err
+}
+
+// DashboardCreateRequest is a request used to create a dashboard.
+type DashboardCreateRequest struct {
+	// UID of the dashboard. If empty, the server generates one.
+	UID string `json:"uid,omitempty"`
+	// Dashboard is the definition of the dashboard.
+	Dashboard Dashboard `json:"dashboard"`
+	// Overwrite an existing dashboard with the same UID.
+	Overwrite bool `json:"overwrite,omitempty"`
+	// Message describing the change.
+	Message string `json:"message,omitempty"`
+}
+
+// DashboardUpdateRequest is a request used to update a dashboard.
+type DashboardUpdateRequest struct {
+	// Dashboard is the new definition of the dashboard.
+	Dashboard Dashboard `json:"dashboard"`
+	// Overwrite the dashboard, even if it was changed concurrently.
+	Overwrite bool `json:"overwrite,omitempty"`
+	// Message describing the change.
+	Message string `json:"message,omitempty"`
+}
+
+// dashboardResponse is the response of the Axiom API to creating or updating
+// a dashboard.
+type dashboardResponse struct {
+	Status    string          `json:"status"`
+	Dashboard DashboardWithID `json:"dashboard"`
+}
+
 // DashboardsService handles communication with dashboard related operations of
 // the Axiom API.
 //
@@ -17,12 +256,87 @@ import (
 type DashboardsService service
 
 // DashboardsListOptions configures optional pagination query parameters for
-// [DashboardsService.ListRaw].
+// [DashboardsService.List] and [DashboardsService.ListRaw].
 type DashboardsListOptions struct {
 	Limit  int `url:"limit,omitempty"`
 	Offset int `url:"offset,omitempty"`
 }
 
+// List dashboards.
+func (s *DashboardsService) List(ctx context.Context, opts *DashboardsListOptions) ([]*DashboardWithID, error) {
+	ctx, span := s.client.trace(ctx, "Dashboards.List")
+	defer span.End()
+
+	path, err := AddURLOptions(s.basePath, opts)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res []*DashboardWithID
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// Get a dashboard by uid.
+func (s *DashboardsService) Get(ctx context.Context, uid string) (*DashboardWithID, error) {
+	ctx, span := s.client.trace(ctx, "Dashboards.Get", trace.WithAttributes(
+		attribute.String("axiom.dashboard_uid", uid),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, "uid", uid)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res DashboardWithID
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Create a dashboard with the given properties.
+func (s *DashboardsService) Create(ctx context.Context, req DashboardCreateRequest) (*DashboardWithID, error) {
+	ctx, span := s.client.trace(ctx, "Dashboards.Create", trace.WithAttributes(
+		attribute.String("axiom.param.uid", req.UID),
+		attribute.String("axiom.param.name", req.Dashboard.Name),
+	))
+	defer span.End()
+
+	var res dashboardResponse
+	if err := s.client.Call(ctx, http.MethodPost, s.basePath, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res.Dashboard, nil
+}
+
+// Update the dashboard identified by the given uid with the given properties.
+func (s *DashboardsService) Update(ctx context.Context, uid string, req DashboardUpdateRequest) (*DashboardWithID, error) {
+	ctx, span := s.client.trace(ctx, "Dashboards.Update", trace.WithAttributes(
+		attribute.String("axiom.dashboard_uid", uid),
+		attribute.String("axiom.param.name", req.Dashboard.Name),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, "uid", uid)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res dashboardResponse
+	if err := s.client.Call(ctx, http.MethodPut, path, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res.Dashboard, nil
+}
+
 // ListRaw returns dashboards as raw JSON.
 func (s *DashboardsService) ListRaw(ctx context.Context, opts *DashboardsListOptions) (json.RawMessage, error) {
 	ctx, span := s.client.trace(ctx, "Dashboards.ListRaw")
@@ -118,3 +432,51 @@ func (s *DashboardsService) Delete(ctx context.Context, uid string) error {
 
 	return nil
 }
+
+// marshalWithExtra marshals v, which must marshal to a JSON object, and adds
+// the extra fields not already present.
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for k, raw := range extra {
+		if _, ok := fields[k]; !ok {
+			fields[k] = raw
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// unmarshalWithExtra unmarshals the JSON object into v, which must be a pointer
+// to a struct, and returns the fields that don't map to one of its fields.
+func unmarshalWithExtra(b []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(v).Elem()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		delete(fields, name)
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
@@ -151,6 +151,67 @@ func (s *DashboardsTestSuite) TestRawCRUD() {
 	s.True(found, "created dashboard %q was not returned by ListRaw", uid)
 }
 
+func (s *DashboardsTestSuite) TestCRUD() {
+	uid := fmt.Sprintf("dash-crud-%d", time.Now().UnixNano())
+
+	dashboard := axiom.Dashboard{
+		Name:        "typed crud dashboard",
+		Owner:       s.testUser.ID,
+		Description: "typed dashboards CRUD integration test",
+		Charts: []axiom.Chart{{
+			ID:        "timeseries-1",
+			Type:      axiom.ChartTypeTimeSeries,
+			Name:      "Count",
+			DatasetID: s.dataset.ID,
+			Query:     &axiom.ChartQuery{APL: fmt.Sprintf("['%s'] | summarize count() by bin_auto(_time)", s.dataset.ID)},
+		}},
+		Layout:          []axiom.Layout{{ChartID: "timeseries-1", W: 6, H: 4}},
+		RefreshTime:     60,
+		SchemaVersion:   2,
+		TimeWindowStart: "qr-now-1h",
+		TimeWindowEnd:   "qr-now",
+	}
+
+	created, err := s.client.Dashboards.Create(s.ctx, axiom.DashboardCreateRequest{
+		UID:       uid,
+		Dashboard: dashboard,
+		Overwrite: true,
+		Message:   "integration create",
+	})
+	s.Require().NoError(err)
+	s.dashboardUID = uid
+	s.Equal(uid, created.UID)
+
+	got, err := s.client.Dashboards.Get(s.ctx, uid)
+	s.Require().NoError(err)
+	s.Equal(dashboard.Name, got.Dashboard.Name)
+	s.Require().Len(got.Dashboard.Charts, 1)
+	s.Equal(axiom.ChartTypeTimeSeries, got.Dashboard.Charts[0].Type)
+
+	// Update the dashboard as returned by the server, so fields unknown to the
+	// client are written back untouched.
+	got.Dashboard.Name = "typed crud dashboard updated"
+	updated, err := s.client.Dashboards.Update(s.ctx, uid, axiom.DashboardUpdateRequest{
+		Dashboard: got.Dashboard,
+		Overwrite: true,
+		Message:   "integration update",
+	})
+	s.Require().NoError(err)
+	s.Equal("typed crud dashboard updated", updated.Dashboard.Name)
+
+	dashboards, err := s.client.Dashboards.List(s.ctx, nil)
+	s.Require().NoError(err)
+
+	var found bool
+	for _, dashboard := range dashboards {
+		if dashboard.UID == uid {
+			found = true
+			break
+		}
+	}
+	s.True(found, "created dashboard %q was not returned by List", uid)
+}
+
 func (s *DashboardsTestSuite) TestAllChartTypes() {
 	uid := fmt.Sprintf("dash-all-charts-%d", time.Now().UnixNano())
 
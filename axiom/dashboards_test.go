@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -174,3 +176,177 @@ func TestDashboardsService_Delete(t *testing.T) {
 	err := client.Dashboards.Delete(t.Context(), "db_test")
 	require.NoError(t, err)
 }
+
+func TestDashboardsService_Get(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"uid": "db_test",
+			"id": "dash_123",
+			"version": 2,
+			"dashboard": {
+				"name": "Test Dashboard",
+				"charts": [{"id": "c1", "type": "TimeSeries", "datasetId": "logs", "query": {"apl": "['logs'] | count"}}],
+				"layout": [{"i": "c1", "x": 0, "y": 0, "w": 6, "h": 4}],
+				"refreshTime": 60,
+				"schemaVersion": 2
+			},
+			"createdAt": "2026-01-01T00:00:00Z",
+			"updatedAt": "2026-01-02T00:00:00Z",
+			"createdBy": "usr_1",
+			"updatedBy": "usr_2"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "GET /v2/dashboards/uid/db_test", hf)
+
+	res, err := client.Dashboards.Get(t.Context(), "db_test")
+	require.NoError(t, err)
+	assert.Equal(t, &DashboardWithID{
+		UID:     "db_test",
+		ID:      "dash_123",
+		Version: 2,
+		Dashboard: Dashboard{
+			Name: "Test Dashboard",
+			Charts: []Chart{{
+				ID:        "c1",
+				Type:      ChartTypeTimeSeries,
+				DatasetID: "logs",
+				Query:     &ChartQuery{APL: "['logs'] | count"},
+			}},
+			Layout:        []Layout{{ChartID: "c1", W: 6, H: 4}},
+			RefreshTime:   60,
+			SchemaVersion: 2,
+		},
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		CreatedBy: "usr_1",
+		UpdatedBy: "usr_2",
+	}, res)
+}
+
+func TestDashboardsService_List(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "10", r.URL.Query().Get("limit"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[{"uid": "db_test", "version": 1, "dashboard": {"name": "Test Dashboard"}}]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "GET /v2/dashboards", hf)
+
+	res, err := client.Dashboards.List(t.Context(), &DashboardsListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "db_test", res[0].UID)
+	assert.Equal(t, "Test Dashboard", res[0].Dashboard.Name)
+}
+
+func TestDashboardsService_Create(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"uid": "db_test",
+			"dashboard": {
+				"name": "Service",
+				"charts": [{"id": "note", "type": "Note", "text": "hello"}],
+				"layout": [{"i": "note", "x": 0, "y": 0, "w": 4, "h": 2}]
+			},
+			"overwrite": true
+		}`, string(body))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{"status": "created", "dashboard": {"uid": "db_test", "id": "dash_123", "version": 1, "dashboard": {"name": "Service"}}}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "POST /v2/dashboards", hf)
+
+	res, err := client.Dashboards.Create(t.Context(), DashboardCreateRequest{
+		UID: "db_test",
+		Dashboard: Dashboard{
+			Name:   "Service",
+			Charts: []Chart{{ID: "note", Type: ChartTypeNote, Text: "hello"}},
+			Layout: []Layout{{ChartID: "note", W: 4, H: 2}},
+		},
+		Overwrite: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "dash_123", res.ID)
+	assert.Equal(t, 1, res.Version)
+}
+
+func TestDashboardsService_Update(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"dashboard": {"name": "Updated"}, "message": "rename"}`, string(body))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{"status": "updated", "dashboard": {"uid": "db_test", "version": 2, "dashboard": {"name": "Updated"}}}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "PUT /v2/dashboards/uid/db_test", hf)
+
+	res, err := client.Dashboards.Update(t.Context(), "db_test", DashboardUpdateRequest{
+		Dashboard: Dashboard{Name: "Updated"},
+		Message:   "rename",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Version)
+	assert.Equal(t, "Updated", res.Dashboard.Name)
+}
+
+func TestDashboardWithID_RoundTrip(t *testing.T) {
+	raw := `{
+		"uid": "db_test",
+		"id": "dash_123",
+		"version": 3,
+		"folder": "ops",
+		"dashboard": {
+			"name": "Test Dashboard",
+			"charts": [
+				{
+					"id": "c1",
+					"type": "FutureChart",
+					"query": {"apl": "['logs'] | count", "queryOptions": {"timeSeriesView": "charts"}},
+					"axis": {"y": "log"}
+				}
+			],
+			"layout": [{"i": "c1", "x": 0, "y": 0, "w": 6, "h": 4, "minW": 2}],
+			"against": "-1d"
+		},
+		"createdAt": "2026-01-01T00:00:00Z",
+		"updatedAt": "2026-01-02T00:00:00Z",
+		"createdBy": "usr_1",
+		"updatedBy": "usr_2"
+	}`
+
+	var dashboard DashboardWithID
+	require.NoError(t, json.Unmarshal([]byte(raw), &dashboard))
+
+	assert.JSONEq(t, `"ops"`, string(dashboard.Extra["folder"]))
+	assert.JSONEq(t, `"-1d"`, string(dashboard.Dashboard.Extra["against"]))
+	assert.Equal(t, ChartType("FutureChart"), dashboard.Dashboard.Charts[0].Type)
+	assert.JSONEq(t, `{"y":"log"}`, string(dashboard.Dashboard.Charts[0].Extra["axis"]))
+	assert.Contains(t, dashboard.Dashboard.Charts[0].Query.Extra, "queryOptions")
+	assert.JSONEq(t, `2`, string(dashboard.Dashboard.Layout[0].Extra["minW"]))
+
+	// Modifying known fields keeps the unknown ones.
+	dashboard.Dashboard.Name = "Renamed"
+
+	b, err := json.Marshal(dashboard)
+	require.NoError(t, err)
+	assert.JSONEq(t, strings.Replace(raw, "Test Dashboard", "Renamed", 1), string(b))
+}
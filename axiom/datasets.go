@@ -2,12 +2,12 @@ package axiom
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"strings"
@@ -21,7 +21,6 @@ import (
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 	"github.com/axiomhq/axiom-go/axiom/query"
 	"github.com/axiomhq/axiom-go/axiom/querylegacy"
-	"github.com/axiomhq/axiom-go/internal/config"
 )
 
 //go:generate go tool stringer -type=ContentType,ContentEncoding -linecomment -output=datasets_string.go
@@ -428,9 +427,8 @@ func (s *DatasetsService) Ingest(ctx context.Context, id string, r io.Reader, ty
 		err  error
 	)
 	if edgeURL := s.client.config.EdgeIngestURL(id); edgeURL != nil {
-		// Edge endpoints only support API tokens, not personal tokens.
-		if config.IsPersonalToken(s.client.config.Token()) {
-			return nil, spanError(span, config.ErrPersonalTokenNotSupportedForEdge)
+		if err = s.client.checkEdgeCredentials(ctx); err != nil {
+			return nil, spanError(span, err)
 		}
 		path = edgeURL.String()
 		if path, err = AddURLOptions(path, opts); err != nil {
@@ -497,6 +495,9 @@ func (s *DatasetsService) Ingest(ctx context.Context, id string, r io.Reader, ty
 // Restrictions for field names (JSON object keys) can be reviewed in
 // [our documentation].
 //
+// Before being encoded, events are run through the processors returned by
+// [Client.EventProcessor]. Events dropped by a processor are not sent.
+//
 // For ingesting large amounts of data, consider using the
 // [DatasetsService.Ingest] or [DatasetsService.IngestChannel] method.
 //
@@ -520,15 +521,29 @@ func (s *DatasetsService) IngestEvents(ctx context.Context, id string, events []
 		}
 	}
 
+	// Process events once, not on every retry. Processors only get to see a
+	// copy of the events.
+	if processor := s.client.eventProcessor(opts.Processors); processor != nil {
+		processed := make([]Event, 0, len(events))
+		for _, event := range events {
+			if event = processor.Process(maps.Clone(event)); event != nil {
+				processed = append(processed, event)
+			}
+		}
+		if len(processed) == 0 {
+			return &ingest.Status{}, nil
+		}
+		events = processed
+	}
+
 	// Build the ingest path - use edge URL if configured.
 	var (
 		path string
 		err  error
 	)
 	if edgeURL := s.client.config.EdgeIngestURL(id); edgeURL != nil {
-		// Edge endpoints only support API tokens, not personal tokens.
-		if config.IsPersonalToken(s.client.config.Token()) {
-			return nil, spanError(span, config.ErrPersonalTokenNotSupportedForEdge)
+		if err = s.client.checkEdgeCredentials(ctx); err != nil {
+			return nil, spanError(span, err)
 		}
 		path = edgeURL.String()
 		if path, err = AddURLOptions(path, opts); err != nil {
@@ -759,9 +774,8 @@ func (s *DatasetsService) Query(ctx context.Context, apl string, options ...quer
 		err  error
 	)
 	if edgeURL := s.client.config.EdgeQueryURL(); edgeURL != nil {
-		// Edge endpoints only support API tokens, not personal tokens.
-		if config.IsPersonalToken(s.client.config.Token()) {
-			return nil, spanError(span, config.ErrPersonalTokenNotSupportedForEdge)
+		if err = s.client.checkEdgeCredentials(ctx); err != nil {
+			return nil, spanError(span, err)
 		}
 		path = edgeURL.String()
 		if path, err = AddURLOptions(path, queryParams); err != nil {
@@ -801,6 +815,46 @@ func (s *DatasetsService) Query(ctx context.Context, apl string, options ...quer
 	return &res.Result, nil
 }
 
+// GetTrace retrieves the trace identified by its id from the OpenTelemetry
+// traces dataset (of kind "otel:traces:v1") identified by its id. The spans of
+// the trace are assembled into a tree using their parent span IDs. The trace ID
+// must be a 32 character hex string.
+//
+// Use [query.SetStartTime] and [query.SetEndTime] to restrict the time window
+// that is searched for the spans of the trace. If no spans are found,
+// [ErrTraceNotFound] is returned. At most 10000 spans are retrieved. If the
+// trace has more, [Trace.Truncated] is set.
+func (s *DatasetsService) GetTrace(ctx context.Context, id, traceID string, options ...query.Option) (*Trace, error) {
+	ctx, span := s.client.trace(ctx, "Datasets.GetTrace", trace.WithAttributes(
+		attribute.String("axiom.dataset_id", id),
+		attribute.String("axiom.param.trace_id", traceID),
+	))
+	defer span.End()
+
+	if _, err := trace.TraceIDFromHex(traceID); err != nil {
+		return nil, spanError(span, fmt.Errorf("invalid trace id %q: %w", traceID, err))
+	}
+
+	res, err := s.Query(ctx, traceAPL(id, traceID, traceSpanLimit), options...)
+	if err != nil {
+		return nil, spanError(span, err)
+	} else if len(res.Tables) == 0 {
+		return nil, spanError(span, ErrTraceNotFound)
+	}
+
+	t, err := newTrace(traceID, res.Tables[0], traceSpanLimit)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	span.SetAttributes(
+		attribute.Int("axiom.trace.span_count", len(t.Spans)),
+		attribute.Bool("axiom.trace.truncated", t.Truncated),
+	)
+
+	return t, nil
+}
+
 // QueryLegacy executes the given legacy query on the dataset identified by its
 // id.
 //
@@ -889,16 +943,10 @@ func DetectContentType(r io.Reader) (io.Reader, ContentType, error) {
 		break
 	}
 
-	// Create a new reader and prepend what we have already consumed in order to
-	// figure out the content type.
-	buf, err := br.Peek(br.Buffered())
-	if err != nil {
-		return nil, 0, err
-	}
-	alreadyRead := bytes.NewReader(buf)
-	r = io.MultiReader(alreadyRead, r)
-
-	return r, typ, nil
+	// The buffered reader still holds what we have already consumed in order
+	// to figure out the content type. It must be returned instead of the
+	// original reader, which might be the buffered reader itself.
+	return br, typ, nil
 }
 
 func setIngestStatusOnSpan(span trace.Span, status ingest.Status) {
@@ -1,6 +1,7 @@
 package axiom
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -17,6 +18,7 @@ import (
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 	"github.com/axiomhq/axiom-go/axiom/query"
 	"github.com/axiomhq/axiom-go/axiom/querylegacy"
+	"github.com/axiomhq/axiom-go/axiom/redact"
 	"github.com/axiomhq/axiom-go/internal/test/testhelper"
 )
 
@@ -834,6 +836,108 @@ func TestDatasetsService_IngestEvents_Retry(t *testing.T) {
 	assert.True(t, hasErrored)
 }
 
+func TestDatasetsService_IngestEvents_Redactor(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		events := assertValidJSON(t, zsr)
+		zsr.Close()
+
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, map[string]any{
+				"msg":  "token [REDACTED] leaked",
+				"user": map[string]any{"name": "john"},
+			}, events[0])
+		}
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{"ingested": 1}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "POST /v1/datasets/test/ingest", hf)
+
+	redactor, err := redact.New(
+		redact.DropFields("user.password"),
+		redact.DetectSecrets(redact.AxiomToken),
+	)
+	require.NoError(t, err)
+	require.NoError(t, client.Options(SetRedactor(redactor)))
+
+	event := Event{
+		"msg":  "token " + apiToken + " leaked",
+		"user": map[string]any{"name": "john", "password": "hunter2"},
+	}
+
+	_, err = client.Datasets.IngestEvents(t.Context(), "test", []Event{event})
+	require.NoError(t, err)
+
+	// The event itself is not modified.
+	assert.Equal(t, "hunter2", event["user"].(map[string]any)["password"])
+}
+
+func TestDatasetsService_IngestEvents_Processors(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		events := assertValidJSON(t, zsr)
+		zsr.Close()
+
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, map[string]any{
+				"service":  "api",
+				"msg":      "[REDACTED]",
+				"user.id":  float64(1),
+				"per_call": true,
+			}, events[0])
+		}
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{"ingested": 1}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "POST /v1/datasets/test/ingest", hf)
+
+	redactor, err := redact.New(redact.MaskFields("msg"))
+	require.NoError(t, err)
+	require.NoError(t, client.Options(
+		SetRedactor(redactor),
+		SetProcessors(
+			ingest.AddFields(map[string]any{"service": "api"}),
+			ingest.DropEvents(func(event map[string]any) bool {
+				return event["level"] == "debug"
+			}),
+		),
+	))
+
+	events := []Event{
+		{"level": "debug", "msg": "dropped"},
+		{"msg": "kept", "user": map[string]any{"id": 1}},
+	}
+
+	_, err = client.Datasets.IngestEvents(t.Context(), "test", events,
+		ingest.AddProcessors(
+			ingest.Flatten("."),
+			ingest.ProcessorFunc(func(event map[string]any) map[string]any {
+				event["per_call"] = true
+				return event
+			}),
+		),
+	)
+	require.NoError(t, err)
+
+	// The events themselves are not modified.
+	assert.Equal(t, Event{"msg": "kept", "user": map[string]any{"id": 1}}, events[1])
+
+	// Nothing is sent if all events are dropped.
+	res, err := client.Datasets.IngestEvents(t.Context(), "test", events[:1])
+	require.NoError(t, err)
+	assert.Equal(t, &ingest.Status{}, res)
+}
+
 func TestDatasetsService_IngestChannel_Unbuffered(t *testing.T) {
 	exp := &ingest.Status{
 		Ingested:       2,
@@ -1334,6 +1438,18 @@ func TestDetectContentType(t *testing.T) {
 			assert.Equal(t, tt.want.String(), got.String())
 		})
 	}
+
+	t.Run("buffered reader", func(t *testing.T) {
+		const input = `{"a":1}` + "\n" + `{"a":2}`
+
+		r, got, err := DetectContentType(bufio.NewReader(strings.NewReader(input)))
+		require.NoError(t, err)
+
+		if b, err := io.ReadAll(r); assert.NoError(t, err) {
+			assert.Equal(t, input, string(b))
+		}
+		assert.Equal(t, NDJSON, got)
+	})
 }
 
 func assertValidJSON(t *testing.T, r io.Reader) []any {
@@ -26,6 +26,10 @@ type Options struct {
 	// valid for CSV content and also completely optional. It comes in handy
 	// when the CSV content does not have a header row.
 	CSVFields []string `url:"-"`
+	// Processors are run on every event before it is encoded, after the
+	// processors configured on the client. Only valid when ingesting events,
+	// not raw content.
+	Processors []Processor `url:"-"`
 }
 
 // An Option applies optional parameters to an ingest operation.
@@ -82,3 +86,15 @@ func AddCSVField(field ...string) Option {
 func SetCSVFields(fields ...string) Option {
 	return func(o *Options) { o.CSVFields = fields }
 }
+
+// AddProcessors adds one or more processors to run on every event before it is
+// ingested.
+func AddProcessors(processors ...Processor) Option {
+	return func(o *Options) { o.Processors = append(o.Processors, processors...) }
+}
+
+// SetProcessors sets the processors to run on every event before it is
+// ingested. It will overwrite any existing processors.
+func SetProcessors(processors ...Processor) Option {
+	return func(o *Options) { o.Processors = processors }
+}
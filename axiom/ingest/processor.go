@@ -0,0 +1,43 @@
+package ingest
+
+// A Processor processes an event before it is ingested. It returns the
+// processed event or nil, if the event should be dropped.
+//
+// The event passed to a processor is a shallow copy of the event that is
+// ingested. A processor can therefore add, replace and delete top-level fields
+// of the event but must not modify nested objects or arrays in place.
+type Processor interface {
+	Process(event map[string]any) map[string]any
+}
+
+// The ProcessorFunc type is an adapter to allow the use of ordinary functions
+// as a [Processor].
+type ProcessorFunc func(event map[string]any) map[string]any
+
+// Process implements [Processor].
+func (f ProcessorFunc) Process(event map[string]any) map[string]any {
+	return f(event)
+}
+
+// Chain returns a processor that runs the given processors in order. Processing
+// stops as soon as an event is dropped. Nil processors are ignored.
+func Chain(processors ...Processor) Processor {
+	chain := make(chain, 0, len(processors))
+	for _, p := range processors {
+		if p != nil {
+			chain = append(chain, p)
+		}
+	}
+	return chain
+}
+
+type chain []Processor
+
+func (c chain) Process(event map[string]any) map[string]any {
+	for _, p := range c {
+		if event = p.Process(event); event == nil {
+			return nil
+		}
+	}
+	return event
+}
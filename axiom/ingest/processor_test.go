@@ -0,0 +1,32 @@
+package ingest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	processor := func(name string, drop bool) ingest.Processor {
+		return ingest.ProcessorFunc(func(event map[string]any) map[string]any {
+			calls = append(calls, name)
+			if drop {
+				return nil
+			}
+			event[name] = true
+			return event
+		})
+	}
+
+	p := ingest.Chain(processor("a", false), nil, processor("b", false))
+	assert.Equal(t, map[string]any{"a": true, "b": true}, p.Process(map[string]any{}))
+	assert.Equal(t, []string{"a", "b"}, calls)
+
+	calls = nil
+	p = ingest.Chain(processor("a", true), processor("b", false))
+	assert.Nil(t, p.Process(map[string]any{}))
+	assert.Equal(t, []string{"a"}, calls)
+}
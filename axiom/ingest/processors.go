@@ -0,0 +1,210 @@
+package ingest
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// serviceAccountNamespaceFile holds the namespace of a Kubernetes pod, if a
+// service account is mounted.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// AddFields returns a processor that adds the given fields to every event.
+// Fields already present on the event are not overwritten.
+func AddFields(fields map[string]any) Processor {
+	fields = maps.Clone(fields)
+	return ProcessorFunc(func(event map[string]any) map[string]any {
+		for k, v := range fields {
+			if _, ok := event[k]; !ok {
+				event[k] = v
+			}
+		}
+		return event
+	})
+}
+
+// RenameField returns a processor that moves the value of a field to another
+// field. Both fields are specified by their path: the keys of nested objects,
+// separated by dots (e.g. "user.email"). Events without the field are left
+// unchanged.
+func RenameField(from, to string) Processor {
+	fromKeys, toKeys := strings.Split(from, "."), strings.Split(to, ".")
+	return ProcessorFunc(func(event map[string]any) map[string]any {
+		value, ok := lookupPath(event, fromKeys)
+		if !ok {
+			return event
+		}
+		deletePath(event, fromKeys)
+		setPath(event, toKeys, value)
+		return event
+	})
+}
+
+// Flatten returns a processor that flattens nested objects into top-level
+// fields, joining the keys with the given separator (e.g. {"a":{"b":1}}
+// becomes {"a.b":1} for a separator of "."). Flattened fields never overwrite
+// existing top-level fields. Arrays are left unchanged.
+func Flatten(separator string) Processor {
+	return ProcessorFunc(func(event map[string]any) map[string]any {
+		var nested map[string]map[string]any
+		for k, v := range event {
+			if m, ok := asMap(v); ok {
+				if nested == nil {
+					nested = make(map[string]map[string]any)
+				}
+				nested[k] = m
+				delete(event, k)
+			}
+		}
+		for k, m := range nested {
+			flattenInto(event, k, m, separator)
+		}
+		return event
+	})
+}
+
+// DropEvents returns a processor that drops all events the given predicate
+// returns true for.
+func DropEvents(predicate func(event map[string]any) bool) Processor {
+	return ProcessorFunc(func(event map[string]any) map[string]any {
+		if predicate(event) {
+			return nil
+		}
+		return event
+	})
+}
+
+// AddHostMetadata returns a processor that adds the name, operating system and
+// architecture of the host to every event, in the "host" field.
+func AddHostMetadata() Processor {
+	host := map[string]any{
+		"os":   runtime.GOOS,
+		"arch": runtime.GOARCH,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		host["name"] = hostname
+	}
+	return AddFields(map[string]any{"host": host})
+}
+
+// AddProcessMetadata returns a processor that adds the ID and executable name
+// of the current process as well as the Go version to every event, in the
+// "process" field.
+func AddProcessMetadata() Processor {
+	process := map[string]any{
+		"pid":     os.Getpid(),
+		"runtime": runtime.Version(),
+	}
+	if executable, err := os.Executable(); err == nil {
+		process["executable"] = filepath.Base(executable)
+	}
+	return AddFields(map[string]any{"process": process})
+}
+
+// AddKubernetesMetadata returns a processor that adds the namespace, pod name
+// and node name to every event, in the "kubernetes" field. They are read from
+// the "POD_NAMESPACE", "POD_NAME" and "NODE_NAME" environment variables, which
+// are usually populated using the downward API. The namespace falls back to
+// the one of the mounted service account and the pod name to the hostname.
+// Outside of Kubernetes, the processor leaves events unchanged.
+func AddKubernetesMetadata() Processor {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return ProcessorFunc(func(event map[string]any) map[string]any { return event })
+	}
+
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		if b, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+			namespace = strings.TrimSpace(string(b))
+		}
+	}
+	podName := os.Getenv("POD_NAME")
+	if podName == "" {
+		podName, _ = os.Hostname()
+	}
+
+	kubernetes := make(map[string]any, 3)
+	if namespace != "" {
+		kubernetes["namespace"] = namespace
+	}
+	if podName != "" {
+		kubernetes["pod"] = map[string]any{"name": podName}
+	}
+	if nodeName := os.Getenv("NODE_NAME"); nodeName != "" {
+		kubernetes["node"] = map[string]any{"name": nodeName}
+	}
+	return AddFields(map[string]any{"kubernetes": kubernetes})
+}
+
+func lookupPath(m map[string]any, keys []string) (any, bool) {
+	v, ok := m[keys[0]]
+	if !ok || len(keys) == 1 {
+		return v, ok
+	}
+	nested, ok := asMap(v)
+	if !ok {
+		return nil, false
+	}
+	return lookupPath(nested, keys[1:])
+}
+
+// deletePath deletes the value at the given path. Nested objects on the path
+// are copied, not modified.
+func deletePath(m map[string]any, keys []string) {
+	if len(keys) == 1 {
+		delete(m, keys[0])
+		return
+	}
+	nested, ok := asMap(m[keys[0]])
+	if !ok {
+		return
+	}
+	nested = maps.Clone(nested)
+	deletePath(nested, keys[1:])
+	m[keys[0]] = nested
+}
+
+// setPath sets the value at the given path, creating nested objects as needed.
+// Nested objects on the path are copied, not modified.
+func setPath(m map[string]any, keys []string, value any) {
+	if len(keys) == 1 {
+		m[keys[0]] = value
+		return
+	}
+	nested, _ := asMap(m[keys[0]])
+	if nested = maps.Clone(nested); nested == nil {
+		nested = make(map[string]any)
+	}
+	setPath(nested, keys[1:], value)
+	m[keys[0]] = nested
+}
+
+func flattenInto(dst map[string]any, prefix string, m map[string]any, separator string) {
+	for k, v := range m {
+		key := prefix + separator + k
+		if nested, ok := asMap(v); ok {
+			flattenInto(dst, key, nested, separator)
+		} else if _, exists := dst[key]; !exists {
+			dst[key] = v
+		}
+	}
+}
+
+var mapType = reflect.TypeFor[map[string]any]()
+
+// asMap returns the given value as a nested object. Besides map[string]any it
+// accepts named map types of the same underlying type, most notably
+// axiom.Event, which the logging adapters use for groups and objects.
+func asMap(v any) (map[string]any, bool) {
+	if m, ok := v.(map[string]any); ok {
+		return m, true
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Map && rv.Type().ConvertibleTo(mapType) {
+		return rv.Convert(mapType).Interface().(map[string]any), true
+	}
+	return nil, false
+}
@@ -0,0 +1,133 @@
+package ingest_test
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+func TestAddFields(t *testing.T) {
+	fields := map[string]any{"env": "prod", "service": "api"}
+	p := ingest.AddFields(fields)
+
+	// Modifying the fields afterwards has no effect.
+	fields["env"] = "dev"
+
+	act := p.Process(map[string]any{"service": "worker"})
+	assert.Equal(t, map[string]any{"env": "prod", "service": "worker"}, act)
+}
+
+func TestRenameField(t *testing.T) {
+	user := map[string]any{"email": "john@example.com", "name": "john"}
+	event := map[string]any{"user": user}
+
+	act := ingest.RenameField("user.email", "contact.email").Process(event)
+	assert.Equal(t, map[string]any{
+		"user":    map[string]any{"name": "john"},
+		"contact": map[string]any{"email": "john@example.com"},
+	}, act)
+	// Nested objects are not modified in place.
+	assert.Len(t, user, 2)
+
+	act = ingest.RenameField("missing", "other").Process(map[string]any{"a": 1})
+	assert.Equal(t, map[string]any{"a": 1}, act)
+
+	// Nested objects of type axiom.Event, as produced by the adapters.
+	event = map[string]any{"user": axiom.Event{"email": "john@example.com", "name": "john"}}
+	act = ingest.RenameField("user.email", "user.contact.email").Process(event)
+	assert.Equal(t, map[string]any{
+		"user": map[string]any{
+			"name":    "john",
+			"contact": map[string]any{"email": "john@example.com"},
+		},
+	}, act)
+}
+
+func TestFlatten(t *testing.T) {
+	act := ingest.Flatten(".").Process(map[string]any{
+		"a": map[string]any{
+			"b": 1,
+			"c": map[string]any{"d": 2},
+		},
+		"a.b": "top-level",
+		"e":   []any{map[string]any{"f": 3}},
+	})
+	assert.Equal(t, map[string]any{
+		"a.b":   "top-level",
+		"a.c.d": 2,
+		"e":     []any{map[string]any{"f": 3}},
+	}, act)
+
+	// Nested objects of type axiom.Event, as produced by the adapters.
+	act = ingest.Flatten(".").Process(map[string]any{
+		"a": axiom.Event{
+			"b": 1,
+			"c": map[string]any{"d": axiom.Event{"e": 2}},
+		},
+	})
+	assert.Equal(t, map[string]any{
+		"a.b":     1,
+		"a.c.d.e": 2,
+	}, act)
+}
+
+func TestDropEvents(t *testing.T) {
+	p := ingest.DropEvents(func(event map[string]any) bool {
+		return event["level"] == "debug"
+	})
+
+	assert.Nil(t, p.Process(map[string]any{"level": "debug"}))
+	assert.Equal(t, map[string]any{"level": "info"}, p.Process(map[string]any{"level": "info"}))
+}
+
+func TestAddHostMetadata(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skip("hostname not available")
+	}
+
+	act := ingest.AddHostMetadata().Process(map[string]any{})
+	assert.Equal(t, map[string]any{
+		"host": map[string]any{
+			"name": hostname,
+			"os":   runtime.GOOS,
+			"arch": runtime.GOARCH,
+		},
+	}, act)
+}
+
+func TestAddProcessMetadata(t *testing.T) {
+	act := ingest.AddProcessMetadata().Process(map[string]any{})
+	if assert.Contains(t, act, "process") {
+		process := act["process"].(map[string]any)
+		assert.Equal(t, os.Getpid(), process["pid"])
+		assert.Equal(t, runtime.Version(), process["runtime"])
+		assert.NotEmpty(t, process["executable"])
+	}
+}
+
+func TestAddKubernetesMetadata(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	act := ingest.AddKubernetesMetadata().Process(map[string]any{})
+	assert.Empty(t, act)
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("POD_NAME", "api-0")
+	t.Setenv("NODE_NAME", "node-1")
+
+	act = ingest.AddKubernetesMetadata().Process(map[string]any{})
+	assert.Equal(t, map[string]any{
+		"kubernetes": map[string]any{
+			"namespace": "default",
+			"pod":       map[string]any{"name": "api-0"},
+			"node":      map[string]any{"name": "node-1"},
+		},
+	}, act)
+}
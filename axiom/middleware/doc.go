@@ -0,0 +1,35 @@
+// Package middleware provides middleware that ingests one event per handled
+// request or gRPC call into Axiom, giving all services uniform access logs
+// without a logger in between.
+//
+// Usage:
+//
+//	import "github.com/axiomhq/axiom-go/axiom/middleware"
+//
+// Create a [Middleware] once and wrap the handlers of a [net/http] server:
+//
+//	mw, err := middleware.New(middleware.SetDataset("access-logs"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer mw.Close()
+//
+//	http.ListenAndServe(":8080", mw.Handler(mux))
+//
+// The same [Middleware] provides interceptors for gRPC servers and clients:
+//
+//	srv := grpc.NewServer(
+//	    grpc.ChainUnaryInterceptor(mw.UnaryServerInterceptor()),
+//	    grpc.ChainStreamInterceptor(mw.StreamServerInterceptor()),
+//	)
+//
+// Events are buffered and ingested in the background. A [Middleware] needs to
+// be closed properly to make sure all events are sent by calling
+// [Middleware.Close].
+//
+// If the request context carries an OpenTelemetry span, its trace and span IDs
+// are added to the event. To make sure the span is available, wrap the handler
+// returned by [Middleware.Handler] with the OpenTelemetry instrumentation, e.g.
+// "otelhttp.NewHandler(mw.Handler(mux), ...)". For gRPC, use the "otelgrpc"
+// stats handlers which run before any interceptor.
+package middleware
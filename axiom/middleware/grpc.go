@@ -0,0 +1,330 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+// The kinds of gRPC calls.
+const (
+	rpcKindServer = "server"
+	rpcKindClient = "client"
+)
+
+// UnaryServerInterceptor returns a [grpc.UnaryServerInterceptor] that ingests
+// one event per handled call. See [Middleware.StreamServerInterceptor] for the
+// fields of the event.
+func (m *Middleware) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !m.traceRPC(ctx, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		c := m.newCall(ctx, rpcKindServer, info.FullMethod, "unary")
+		c.received(req)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			c.sent(resp)
+		}
+		c.finish(err)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a [grpc.StreamServerInterceptor] that
+// ingests one event per handled stream. The event carries the following fields:
+//
+//   - _time: The time the call was received.
+//   - kind: "server" for handled calls, "client" for issued calls.
+//   - service: The fully qualified name of the gRPC service.
+//   - method: The name of the gRPC method.
+//   - type: The type of the call: "unary", "client_stream", "server_stream" or
+//     "bidi_stream".
+//   - code: The gRPC status code of the call, e.g. "OK" or "NotFound".
+//   - error: The error message of the call, if it failed.
+//   - duration_ms: The duration of the call, in milliseconds.
+//   - peer: The address of the client, for handled calls.
+//   - target: The target of the client connection, for issued calls.
+//   - messages_sent, messages_received: The number of messages sent and
+//     received.
+//   - bytes_sent, bytes_received: The size of the protobuf messages sent and
+//     received.
+//   - trace_id, span_id: The IDs of the OpenTelemetry span in the call
+//     context, if any.
+//   - metadata: The metadata allowed by [SetHeaders], if any. For handled
+//     calls, this is the incoming metadata, for issued calls the outgoing one.
+func (m *Middleware) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		if !m.traceRPC(ctx, info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		c := m.newCall(ctx, rpcKindServer, info.FullMethod, streamType(info.IsClientStream, info.IsServerStream))
+		err := handler(srv, &serverStream{ServerStream: ss, call: c})
+		c.finish(err)
+
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns a [grpc.UnaryClientInterceptor] that ingests
+// one event per issued call. See [Middleware.StreamServerInterceptor] for the
+// fields of the event.
+func (m *Middleware) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !m.traceRPC(ctx, method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		c := m.newCall(ctx, rpcKindClient, method, "unary")
+		c.event["target"] = cc.Target()
+		c.sent(req)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			c.received(reply)
+		}
+		c.finish(err)
+
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a [grpc.StreamClientInterceptor] that
+// ingests one event per issued stream. The event is ingested once the stream
+// ends, which is when receiving a message fails or, for client streaming
+// calls, when the response has been received. Streams that are abandoned
+// before are not ingested. See [Middleware.StreamServerInterceptor] for the
+// fields of the event.
+func (m *Middleware) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if !m.traceRPC(ctx, method) {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		c := m.newCall(ctx, rpcKindClient, method, streamType(desc.ClientStreams, desc.ServerStreams))
+		c.event["target"] = cc.Target()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			c.finish(err)
+			return nil, err
+		}
+
+		return &clientStream{ClientStream: cs, call: c, serverStreams: desc.ServerStreams}, nil
+	}
+}
+
+// traceRPC reports whether the call to the given method should be ingested.
+func (m *Middleware) traceRPC(ctx context.Context, fullMethod string) bool {
+	if m.grpcFilter != nil && !m.grpcFilter(ctx, fullMethod) {
+		return false
+	}
+	return m.sample()
+}
+
+// call collects the event of a single gRPC call.
+type call struct {
+	m     *Middleware
+	start time.Time
+	event axiom.Event
+
+	mu                             sync.Mutex
+	messagesSent, messagesReceived int
+	bytesSent, bytesReceived       int
+	finishOnce                     sync.Once
+}
+
+func (m *Middleware) newCall(ctx context.Context, kind, fullMethod, typ string) *call {
+	start := time.Now()
+
+	service, method := splitFullMethod(fullMethod)
+	event := axiom.Event{
+		ingest.TimestampField: start.Format(time.RFC3339Nano),
+		"kind":                kind,
+		"service":             service,
+		"method":              method,
+		"type":                typ,
+	}
+
+	var md metadata.MD
+	if kind == rpcKindServer {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			event["peer"] = p.Addr.String()
+		}
+		md, _ = metadata.FromIncomingContext(ctx)
+	} else {
+		md, _ = metadata.FromOutgoingContext(ctx)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		event["trace_id"] = sc.TraceID().String()
+		event["span_id"] = sc.SpanID().String()
+	}
+	if md := m.allowedMetadata(md); md != nil {
+		event["metadata"] = md
+	}
+
+	return &call{
+		m:     m,
+		start: start,
+		event: event,
+	}
+}
+
+func (c *call) sent(msg any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messagesSent++
+	c.bytesSent += messageSize(msg)
+}
+
+func (c *call) received(msg any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messagesReceived++
+	c.bytesReceived += messageSize(msg)
+}
+
+// finish completes the event with the outcome of the call and ingests it. Only
+// the first call to finish has an effect.
+func (c *call) finish(err error) {
+	c.finishOnce.Do(func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.event["code"] = status.Code(err).String()
+		if err != nil {
+			c.event["error"] = status.Convert(err).Message()
+		}
+		c.event["duration_ms"] = float64(time.Since(c.start)) / float64(time.Millisecond)
+		c.event["messages_sent"] = c.messagesSent
+		c.event["messages_received"] = c.messagesReceived
+		c.event["bytes_sent"] = c.bytesSent
+		c.event["bytes_received"] = c.bytesReceived
+
+		c.m.ingest(c.event)
+	})
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	call *call
+}
+
+func (s *serverStream) SendMsg(msg any) error {
+	err := s.ServerStream.SendMsg(msg)
+	if err == nil {
+		s.call.sent(msg)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(msg any) error {
+	err := s.ServerStream.RecvMsg(msg)
+	if err == nil {
+		s.call.received(msg)
+	}
+	return err
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	call          *call
+	serverStreams bool
+}
+
+func (s *clientStream) SendMsg(msg any) error {
+	err := s.ClientStream.SendMsg(msg)
+	if err == nil {
+		s.call.sent(msg)
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(msg any) error {
+	err := s.ClientStream.RecvMsg(msg)
+	switch {
+	case errors.Is(err, io.EOF):
+		s.call.finish(nil)
+	case err != nil:
+		s.call.finish(err)
+	default:
+		s.call.received(msg)
+		// Calls without server streaming end with the first response.
+		if !s.serverStreams {
+			s.call.finish(nil)
+		}
+	}
+	return err
+}
+
+func (m *Middleware) allowedMetadata(md metadata.MD) axiom.Event {
+	if len(m.headers) == 0 || len(md) == 0 {
+		return nil
+	}
+	allowed := make(axiom.Event, len(m.headers))
+	for key, values := range md {
+		if !slices.ContainsFunc(m.headers, func(header string) bool {
+			return strings.EqualFold(header, key)
+		}) {
+			continue
+		}
+		if len(values) == 1 {
+			allowed[key] = values[0]
+		} else {
+			allowed[key] = values
+		}
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	return allowed
+}
+
+// splitFullMethod splits a full gRPC method name of the form
+// "/package.Service/Method" into service and method name.
+func splitFullMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "", fullMethod
+	}
+	return service, method
+}
+
+func streamType(clientStreams, serverStreams bool) string {
+	switch {
+	case clientStreams && serverStreams:
+		return "bidi_stream"
+	case clientStreams:
+		return "client_stream"
+	case serverStreams:
+		return "server_stream"
+	}
+	return "unary"
+}
+
+// messageSize returns the encoded size of the given message, if it is a
+// protobuf message.
+func messageSize(msg any) int {
+	if pm, ok := msg.(proto.Message); ok {
+		return proto.Size(pm)
+	}
+	return 0
+}
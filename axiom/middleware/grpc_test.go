@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+// setupGRPC starts a gRPC health server and returns a client connected to it.
+// Both ends use the given server and client interceptors.
+func setupGRPC(t *testing.T, serverOpts []grpc.ServerOption, dialOpts []grpc.DialOption) healthpb.HealthClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	hs := health.NewServer()
+	hs.SetServingStatus("test", healthpb.HealthCheckResponse_SERVING)
+
+	srv := grpc.NewServer(serverOpts...)
+	healthpb.RegisterHealthServer(srv, hs)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	dialOpts = append(dialOpts,
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufnet", dialOpts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestMiddleware_UnaryInterceptors(t *testing.T) {
+	mw, events := setup(t,
+		SetHeaders("x-request-id"),
+		SetRedactFields("peer"),
+	)
+
+	client := setupGRPC(t,
+		[]grpc.ServerOption{grpc.UnaryInterceptor(mw.UnaryServerInterceptor())},
+		[]grpc.DialOption{grpc.WithUnaryInterceptor(mw.UnaryClientInterceptor())},
+	)
+
+	ctx := metadata.AppendToOutgoingContext(t.Context(), "x-request-id", "abc", "authorization", "secret")
+
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "test"})
+	require.NoError(t, err)
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	ingested := events()
+	require.Len(t, ingested, 4)
+
+	byKindAndCode := make(map[string]axiom.Event, len(ingested))
+	for _, event := range ingested {
+		assert.NotEmpty(t, event["_time"])
+		assert.IsType(t, float64(0), event["duration_ms"])
+		assert.Equal(t, "grpc.health.v1.Health", event["service"])
+		assert.Equal(t, "Check", event["method"])
+		assert.Equal(t, "unary", event["type"])
+		assert.Equal(t, map[string]any{"x-request-id": "abc"}, event["metadata"])
+		byKindAndCode[event["kind"].(string)+"/"+event["code"].(string)] = event
+	}
+
+	server := byKindAndCode["server/OK"]
+	require.NotNil(t, server)
+	assert.Equal(t, redacted, server["peer"])
+	assert.EqualValues(t, 1, server["messages_received"])
+	assert.EqualValues(t, 1, server["messages_sent"])
+	assert.EqualValues(t, 6, server["bytes_received"])
+	assert.EqualValues(t, 2, server["bytes_sent"])
+
+	clientEvent := byKindAndCode["client/OK"]
+	require.NotNil(t, clientEvent)
+	assert.Equal(t, "passthrough:///bufnet", clientEvent["target"])
+	assert.EqualValues(t, 6, clientEvent["bytes_sent"])
+	assert.EqualValues(t, 2, clientEvent["bytes_received"])
+
+	failed := byKindAndCode["server/NotFound"]
+	require.NotNil(t, failed)
+	assert.Equal(t, "unknown service", failed["error"])
+	assert.EqualValues(t, 0, failed["messages_sent"])
+	assert.NotNil(t, byKindAndCode["client/NotFound"])
+}
+
+func TestMiddleware_StreamInterceptors(t *testing.T) {
+	mw, events := setup(t)
+
+	// Signal when the server side of the stream, which ends asynchronously
+	// after the client canceled it, has been handled by the middleware.
+	handled := make(chan struct{})
+	signal := func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		defer close(handled)
+		return handler(srv, ss)
+	}
+
+	client := setupGRPC(t,
+		[]grpc.ServerOption{grpc.ChainStreamInterceptor(signal, mw.StreamServerInterceptor())},
+		[]grpc.DialOption{grpc.WithStreamInterceptor(mw.StreamClientInterceptor())},
+	)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "test"})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	cancel()
+	_, err = stream.Recv()
+	require.Equal(t, codes.Canceled, status.Code(err))
+
+	<-handled
+
+	ingested := events()
+	require.Len(t, ingested, 2)
+
+	kinds := make([]any, 0, len(ingested))
+	for _, event := range ingested {
+		kinds = append(kinds, event["kind"])
+		assert.Equal(t, "Watch", event["method"])
+		assert.Equal(t, "server_stream", event["type"])
+		assert.Equal(t, "Canceled", event["code"])
+		assert.EqualValues(t, 1, event["messages_sent"])
+		assert.EqualValues(t, 1, event["messages_received"])
+	}
+	assert.ElementsMatch(t, []any{"server", "client"}, kinds)
+}
+
+func TestSplitFullMethod(t *testing.T) {
+	service, method := splitFullMethod("/grpc.health.v1.Health/Check")
+	assert.Equal(t, "grpc.health.v1.Health", service)
+	assert.Equal(t, "Check", method)
+
+	service, method = splitFullMethod("Check")
+	assert.Empty(t, service)
+	assert.Equal(t, "Check", method)
+}
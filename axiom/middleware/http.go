@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/felixge/httpsnoop"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+// Handler wraps the given handler and ingests one event per request. The
+// event carries the following fields:
+//
+//   - _time: The time the request was received.
+//   - method: The HTTP method of the request.
+//   - route: The pattern of the [http.ServeMux] route that matched the
+//     request, if any.
+//   - path: The URL path of the request.
+//   - host: The host the request was sent to.
+//   - proto: The HTTP protocol version of the request.
+//   - status: The HTTP status code of the response.
+//   - bytes: The number of bytes written to the response body.
+//   - request_bytes: The size of the request body, if known.
+//   - duration_ms: The time it took to handle the request, in milliseconds.
+//   - remote_ip: The IP address of the client.
+//   - user_agent: The user agent of the client.
+//   - trace_id, span_id: The IDs of the OpenTelemetry span in the request
+//     context, if any.
+//   - headers: The request headers allowed by [SetHeaders], if any.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (m.filter != nil && !m.filter(r)) || !m.sample() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		metrics := httpsnoop.CaptureMetricsFn(w, func(w http.ResponseWriter) {
+			next.ServeHTTP(w, r)
+		})
+
+		event := axiom.Event{
+			ingest.TimestampField: start.Format(time.RFC3339Nano),
+			"method":              r.Method,
+			"path":                r.URL.Path,
+			"host":                r.Host,
+			"proto":               r.Proto,
+			"status":              metrics.Code,
+			"bytes":               metrics.Written,
+			"duration_ms":         float64(metrics.Duration) / float64(time.Millisecond),
+			"remote_ip":           m.remoteIP(r),
+		}
+		// The pattern is set by [http.ServeMux] when routing the request.
+		if r.Pattern != "" {
+			event["route"] = r.Pattern
+		}
+		if r.ContentLength > 0 {
+			event["request_bytes"] = r.ContentLength
+		}
+		if ua := r.UserAgent(); ua != "" {
+			event["user_agent"] = ua
+		}
+		if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+			event["trace_id"] = sc.TraceID().String()
+			event["span_id"] = sc.SpanID().String()
+		}
+		if headers := m.allowedHeaders(r.Header); headers != nil {
+			event["headers"] = headers
+		}
+
+		m.ingest(event)
+	})
+}
+
+func (m *Middleware) remoteIP(r *http.Request) string {
+	if m.trustProxyHeaders {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			ip, _, _ := strings.Cut(xff, ",")
+			return strings.TrimSpace(ip)
+		} else if xri := r.Header.Get("X-Real-Ip"); xri != "" {
+			return xri
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+func TestMiddleware_Handler(t *testing.T) {
+	mw, events := setup(t,
+		SetHeaders("x-request-id", "Authorization"),
+		SetRedactFields("headers.Authorization"),
+		SetFilter(func(r *http.Request) bool { return r.URL.Path != "/healthz" }),
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, "hello")
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := mw.Handler(mux)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(t.Context(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/users/42", strings.NewReader("body"))
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("User-Agent", "test-agent")
+	req.Header.Set("X-Request-Id", "abc")
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Cookie", "session=secret")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/healthz", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	ingested := events()
+	require.Len(t, ingested, 1)
+
+	event := ingested[0]
+	assert.NotEmpty(t, event[ingest.TimestampField])
+	assert.IsType(t, float64(0), event["duration_ms"])
+	delete(event, ingest.TimestampField)
+	delete(event, "duration_ms")
+
+	assert.Equal(t, axiom.Event{
+		"method":        "GET",
+		"route":         "GET /users/{id}",
+		"path":          "/users/42",
+		"host":          "example.com",
+		"proto":         "HTTP/1.1",
+		"status":        float64(http.StatusCreated),
+		"bytes":         float64(5),
+		"request_bytes": float64(4),
+		"remote_ip":     "192.0.2.1",
+		"user_agent":    "test-agent",
+		"trace_id":      "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":       "00f067aa0ba902b7",
+		"headers": map[string]any{
+			"X-Request-Id":  "abc",
+			"Authorization": redacted,
+		},
+	}, event)
+}
+
+func TestMiddleware_Handler_TrustProxyHeaders(t *testing.T) {
+	mw, events := setup(t, SetTrustProxyHeaders())
+
+	handler := mw.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	ingested := events()
+	require.Len(t, ingested, 1)
+	assert.Equal(t, "203.0.113.7", ingested[0]["remote_ip"])
+	assert.EqualValues(t, http.StatusOK, ingested[0]["status"])
+}
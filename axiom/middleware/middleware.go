@@ -0,0 +1,256 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"slices"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/internal/ingester"
+)
+
+// ErrMissingDatasetName is raised when a dataset name is not provided. Set it
+// manually using the [SetDataset] option or export "AXIOM_DATASET".
+var ErrMissingDatasetName = errors.New("missing dataset name")
+
+// redacted replaces the values of redacted fields.
+const redacted = "[REDACTED]"
+
+// An Option modifies the behaviour of the Axiom middleware.
+type Option func(*Middleware) error
+
+// SetClient specifies the Axiom client to use for ingesting the events.
+func SetClient(client *axiom.Client) Option {
+	return func(m *Middleware) error {
+		m.client = client
+		return nil
+	}
+}
+
+// SetClientOptions specifies the Axiom client options to pass to
+// [axiom.NewClient] which is only called if no [axiom.Client] was specified by
+// the [SetClient] option.
+func SetClientOptions(options ...axiom.Option) Option {
+	return func(m *Middleware) error {
+		m.clientOptions = options
+		return nil
+	}
+}
+
+// SetDataset specifies the dataset to ingest the events into. Can also be
+// specified using the "AXIOM_DATASET" environment variable.
+func SetDataset(datasetName string) Option {
+	return func(m *Middleware) error {
+		m.datasetName = datasetName
+		return nil
+	}
+}
+
+// SetIngestOptions specifies the ingestion options to use for ingesting the
+// events.
+func SetIngestOptions(opts ...ingest.Option) Option {
+	return func(m *Middleware) error {
+		m.ingestOptions = opts
+		return nil
+	}
+}
+
+// SetHeaders specifies the request headers to add to the events. Headers not
+// on this allowlist are never ingested. By default, no headers are ingested.
+// For gRPC calls, the allowlist applies to the metadata, case-insensitively.
+func SetHeaders(headers ...string) Option {
+	return func(m *Middleware) error {
+		for _, header := range headers {
+			m.headers = append(m.headers, http.CanonicalHeaderKey(header))
+		}
+		return nil
+	}
+}
+
+// SetRedactFields specifies fields of the events whose values are replaced by
+// "[REDACTED]" before ingestion, e.g. "remote_ip" or "user_agent". Headers are
+// addressed by "headers.<Canonical-Header-Key>", gRPC metadata by
+// "metadata.<lowercase-key>".
+func SetRedactFields(fields ...string) Option {
+	return func(m *Middleware) error {
+		m.redactFields = append(m.redactFields, fields...)
+		return nil
+	}
+}
+
+// SetSampleRate specifies the fraction of requests that are ingested. The rate
+// must be in the range (0, 1]. Defaults to 1, which ingests all requests.
+func SetSampleRate(rate float64) Option {
+	return func(m *Middleware) error {
+		if rate <= 0 || rate > 1 {
+			return errors.New("sample rate must be in the range (0, 1]")
+		}
+		m.sampleRate = rate
+		return nil
+	}
+}
+
+// SetFilter specifies a function that decides if a request is ingested. It is
+// called before sampling, requests it returns false for are never ingested.
+// Useful to exclude health checks and other noisy endpoints.
+func SetFilter(filter func(r *http.Request) bool) Option {
+	return func(m *Middleware) error {
+		m.filter = filter
+		return nil
+	}
+}
+
+// SetGRPCFilter specifies a function that decides if a gRPC call is ingested.
+// It is called with the full method name (e.g. "/package.Service/Method")
+// before sampling, calls it returns false for are never ingested. Useful to
+// exclude health checks and reflection.
+func SetGRPCFilter(filter func(ctx context.Context, fullMethod string) bool) Option {
+	return func(m *Middleware) error {
+		m.grpcFilter = filter
+		return nil
+	}
+}
+
+// SetTrustProxyHeaders makes the middleware determine the remote IP from the
+// "X-Forwarded-For" and "X-Real-Ip" headers, if present. Only use this if the
+// service runs behind a trusted proxy that sets these headers.
+func SetTrustProxyHeaders() Option {
+	return func(m *Middleware) error {
+		m.trustProxyHeaders = true
+		return nil
+	}
+}
+
+// Middleware ingests one event per handled request or gRPC call into Axiom.
+type Middleware struct {
+	client      *axiom.Client
+	datasetName string
+
+	clientOptions []axiom.Option
+	ingestOptions []ingest.Option
+
+	headers           []string
+	redactFields      []string
+	sampleRate        float64
+	filter            func(r *http.Request) bool
+	grpcFilter        func(ctx context.Context, fullMethod string) bool
+	trustProxyHeaders bool
+
+	ingester *ingester.Ingester
+}
+
+// New creates a new middleware that ingests events into Axiom. It
+// automatically takes its configuration from the environment. To connect,
+// export the following environment variables:
+//
+//   - AXIOM_TOKEN
+//   - AXIOM_ORG_ID (only when using a personal token)
+//   - AXIOM_DATASET
+//
+// The configuration can be set manually using options which are prefixed with
+// "Set".
+//
+// An API token with "ingest" permission is sufficient enough.
+//
+// A middleware needs to be closed properly to make sure all events are sent by
+// calling [Middleware.Close].
+func New(options ...Option) (*Middleware, error) {
+	m := &Middleware{
+		sampleRate: 1,
+	}
+
+	// Apply supplied options.
+	for _, option := range options {
+		if option == nil {
+			continue
+		} else if err := option(m); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create client, if not set.
+	if m.client == nil {
+		var err error
+		if m.client, err = axiom.NewClient(m.clientOptions...); err != nil {
+			return nil, err
+		}
+	}
+
+	// When the dataset name is not set, use "AXIOM_DATASET".
+	if m.datasetName == "" {
+		m.datasetName = os.Getenv("AXIOM_DATASET")
+		if m.datasetName == "" {
+			return nil, ErrMissingDatasetName
+		}
+	}
+
+	// Run background ingest.
+	m.ingester = ingester.New(m.client, m.datasetName, "[AXIOM|MIDDLEWARE]", m.ingestOptions...)
+
+	return m, nil
+}
+
+// Close the middleware and make sure all events are flushed. Closing the
+// middleware renders it unusable for further use.
+func (m *Middleware) Close() {
+	m.ingester.Close()
+}
+
+// sample reports whether the current request or call should be ingested.
+func (m *Middleware) sample() bool {
+	//nolint:gosec // Sampling does not need a cryptographically secure source.
+	return m.sampleRate >= 1 || rand.Float64() < m.sampleRate
+}
+
+// ingest redacts the configured fields and hands the event off to the
+// background ingestion. Events are dropped once the middleware is closed.
+func (m *Middleware) ingest(event axiom.Event) {
+	for _, field := range m.redactFields {
+		redact(event, field)
+	}
+	_ = m.ingester.Ingest(event)
+}
+
+// redact replaces the value of the field with the given name. Nested fields
+// are addressed by their dot separated path, but a top-level field with the
+// full name takes precedence.
+func redact(event axiom.Event, field string) {
+	if _, ok := event[field]; ok {
+		event[field] = redacted
+		return
+	}
+	for i := range len(field) {
+		if field[i] != '.' {
+			continue
+		}
+		if nested, ok := event[field[:i]].(axiom.Event); ok {
+			redact(nested, field[i+1:])
+			return
+		}
+	}
+}
+
+func (m *Middleware) allowedHeaders(header http.Header) axiom.Event {
+	if len(m.headers) == 0 {
+		return nil
+	}
+	headers := make(axiom.Event, len(m.headers))
+	for key, values := range header {
+		if !slices.Contains(m.headers, key) {
+			continue
+		}
+		if len(values) == 1 {
+			headers[key] = values[0]
+		} else {
+			headers[key] = values
+		}
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+	"github.com/axiomhq/axiom-go/internal/test/testhelper"
+)
+
+// TestNew makes sure New() picks up the "AXIOM_DATASET" environment variable.
+func TestNew(t *testing.T) {
+	testhelper.SafeClearEnv(t)
+
+	t.Setenv("AXIOM_TOKEN", "xaat-test")
+	t.Setenv("AXIOM_ORG_ID", "123")
+
+	mw, err := New()
+	require.ErrorIs(t, err, ErrMissingDatasetName)
+	require.Nil(t, mw)
+
+	t.Setenv("AXIOM_DATASET", "test")
+
+	mw, err = New()
+	require.NoError(t, err)
+	require.NotNil(t, mw)
+	mw.Close()
+
+	assert.Equal(t, "test", mw.datasetName)
+}
+
+func TestSetSampleRate(t *testing.T) {
+	_, err := New(SetSampleRate(0))
+	require.EqualError(t, err, "sample rate must be in the range (0, 1]")
+}
+
+func TestRedact(t *testing.T) {
+	event := axiom.Event{
+		"remote_ip": "127.0.0.1",
+		"headers": axiom.Event{
+			"X-Api-Key": "secret",
+		},
+		"user.email": "john@example.com",
+	}
+
+	redact(event, "remote_ip")
+	redact(event, "headers.X-Api-Key")
+	redact(event, "user.email")
+	redact(event, "missing.field")
+
+	assert.Equal(t, axiom.Event{
+		"remote_ip": redacted,
+		"headers": axiom.Event{
+			"X-Api-Key": redacted,
+		},
+		"user.email": redacted,
+	}, event)
+}
+
+// setup returns a middleware that ingests into a test server and a function
+// that closes the middleware and returns the ingested events.
+func setup(t *testing.T, options ...Option) (*Middleware, func() []axiom.Event) {
+	t.Helper()
+
+	var (
+		mu     sync.Mutex
+		events []axiom.Event
+	)
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+		defer zsr.Close()
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			var event axiom.Event
+			if assert.NoError(t, json.Unmarshal(s.Bytes(), &event)) {
+				mu.Lock()
+				events = append(events, event)
+				mu.Unlock()
+			}
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, "{}")
+	}
+
+	client := adapters.SetupClient(t, hf)
+
+	mw, err := New(append([]Option{SetClient(client), SetDataset("test")}, options...)...)
+	require.NoError(t, err)
+
+	return mw, func() []axiom.Event {
+		mw.Close()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		return events
+	}
+}
@@ -0,0 +1,182 @@
+package otel
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/axiomhq/axiom-go/internal/config"
+)
+
+// exporterConfig is the configuration shared by the exporters of all signals.
+type exporterConfig struct {
+	config.Config
+
+	// APIEndpoint is the endpoint to use for the exporter.
+	APIEndpoint string
+	// Timeout is the timeout for a single export request.
+	Timeout time.Duration
+	// Protocol is the OTLP transport protocol used by the exporter.
+	Protocol Protocol
+	// Insecure disables transport security, even if the base URL uses the
+	// "https" scheme.
+	Insecure bool
+	// ResourceOptions configure the detection of additional resource
+	// attributes.
+	ResourceOptions []resource.Option
+	// NoEnv disables the use of "AXIOM_*" environment variables.
+	NoEnv bool
+}
+
+func defaultExporterConfig(apiEndpoint string) exporterConfig {
+	return exporterConfig{
+		Config:      config.Default(),
+		APIEndpoint: apiEndpoint,
+	}
+}
+
+// prepare populates the remaining fields from the environment, if not
+// explicitly disabled, and validates the configuration.
+func (c *exporterConfig) prepare() error {
+	if !c.NoEnv {
+		if err := c.IncorporateEnvironment(); err != nil {
+			return err
+		}
+	}
+	return c.Validate()
+}
+
+// endpointURL returns the full URL the exporter sends its data to.
+func (c exporterConfig) endpointURL() (*url.URL, error) {
+	u, err := c.BaseURL().Parse(c.APIEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parse exporter url: %w", err)
+	}
+	return u, nil
+}
+
+// grpcEndpoint returns the "host:port" target of the exporters gRPC
+// connection. If the given endpoint URL carries no port, the default port of
+// its scheme is used.
+func (c exporterConfig) grpcEndpoint(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "http" {
+		return net.JoinHostPort(u.Hostname(), "80")
+	}
+	return net.JoinHostPort(u.Hostname(), "443")
+}
+
+// insecure reports whether transport security is disabled for the exporter,
+// either explicitly or by using a plain "http" URL.
+func (c exporterConfig) insecure(u *url.URL) bool {
+	return c.Insecure || u.Scheme == "http"
+}
+
+// headers returns the headers to send with every export request.
+func (c exporterConfig) headers(dataset string) map[string]string {
+	headers := make(map[string]string)
+	if c.Token() != "" {
+		headers["Authorization"] = "Bearer " + c.Token()
+	}
+	if c.OrganizationID() != "" {
+		headers["X-Axiom-Org-Id"] = c.OrganizationID()
+	}
+	if dataset != "" {
+		headers["X-Axiom-Dataset"] = dataset
+	}
+	return headers
+}
+
+// An Option modifies the behaviour of OpenTelemetry exporters, regardless of
+// the signal they export. It is a [TraceOption] and can also be used as a
+// [MetricOption]. Nonetheless, the official "OTEL_*" environment variables are
+// preferred over the options or "AXIOM_*" environment variables.
+type Option = TraceOption
+
+// SetURL sets the base URL used by the client.
+//
+// Can also be specified using the "AXIOM_URL" environment variable.
+func SetURL(baseURL string) Option {
+	return func(c *traceConfig) error { return c.Options(config.SetURL(baseURL)) }
+}
+
+// SetToken specifies the authentication token used by the client.
+//
+// Can also be specified using the "AXIOM_TOKEN" environment variable.
+func SetToken(token string) Option {
+	return func(c *traceConfig) error { return c.Options(config.SetToken(token)) }
+}
+
+// SetOrganizationID specifies the organization ID used by the client.
+//
+// Can also be specified using the "AXIOM_ORG_ID" environment variable.
+func SetOrganizationID(organizationID string) Option {
+	return func(c *traceConfig) error { return c.Options(config.SetOrganizationID(organizationID)) }
+}
+
+// SetAPIEndpoint specifies the api endpoint used by the client.
+func SetAPIEndpoint(path string) Option {
+	return func(c *traceConfig) error {
+		c.APIEndpoint = path
+		return nil
+	}
+}
+
+// SetTimeout specifies the timeout of a single export request.
+func SetTimeout(timeout time.Duration) Option {
+	return func(c *traceConfig) error {
+		c.Timeout = timeout
+		return nil
+	}
+}
+
+// SetProtocol specifies the OTLP transport protocol used by the exporter.
+// Defaults to [HTTP]. When using [GRPC], the Axiom authentication and dataset
+// headers are sent as gRPC metadata and the connection is secured by TLS unless
+// the base URL uses the "http" scheme or [SetInsecure] is used.
+func SetProtocol(protocol Protocol) Option {
+	return func(c *traceConfig) error {
+		switch protocol {
+		case HTTP, GRPC:
+		default:
+			return fmt.Errorf("invalid protocol %s", protocol)
+		}
+		c.Protocol = protocol
+		return nil
+	}
+}
+
+// SetInsecure disables transport security for the exporter. This is only meant
+// for local testing, e.g. against a stand-in OpenTelemetry collector.
+func SetInsecure() Option {
+	return func(c *traceConfig) error {
+		c.Insecure = true
+		return nil
+	}
+}
+
+// SetResourceOptions specifies options to detect additional resource attributes,
+// e.g. [resource.WithHost], [resource.WithProcess], [resource.WithContainer] or
+// [resource.WithDetectors] together with [KubernetesDetector]. The detected
+// attributes are added to the default resource and the service name and
+// version.
+func SetResourceOptions(options ...resource.Option) Option {
+	return func(c *traceConfig) error {
+		c.ResourceOptions = append(c.ResourceOptions, options...)
+		return nil
+	}
+}
+
+// SetNoEnv prevents the client from deriving its configuration from the
+// environment (by auto reading "AXIOM_*" environment variables).
+func SetNoEnv() Option {
+	return func(c *traceConfig) error {
+		c.NoEnv = true
+		return nil
+	}
+}
@@ -17,6 +17,30 @@
 //     exporter. This sets up the exporter that sends traces to Axiom but allows
 //     for a more advanced setup of the tracer provider.
 //
+// The same levels of helpers are available for metrics:
+//
+//   - [InitMetrics]: Initializes OpenTelemetry and sets the global meter
+//     provider so the official OpenTelemetry Go SDK can be used to get a meter
+//     and record measurements.
+//   - [MeterProvider]: Configures and returns a new OpenTelemetry meter provider
+//     but does not set it as the global meter provider.
+//   - [MetricExporter]: Configures and returns a new OpenTelemetry metric
+//     exporter.
+//
+// Metrics must be sent to an OpenTelemetry metrics dataset (a dataset of kind
+// "otel:metrics:v1").
+//
+// Options that are not specific to a signal, like [SetURL] or [SetToken], are of
+// type [Option] and can be passed as a [TraceOption] as well as a
+// [MetricOption].
+//
+// The tracer provider can be tuned for production use with options like
+// [SetSampleRatio], [SetSamplingRules], the batch options (e.g.
+// [SetBatchMaxQueueSize]), [SetSpanProcessors] and [SetResourceOptions].
+//
+// By default, data is exported using OTLP over HTTP. Use [SetProtocol] with
+// [GRPC] to export using OTLP over gRPC instead.
+//
 // If you wish for traces to propagate beyond the current process, you need to
 // set the global propagator to the OpenTelemetry trace context propagator. This
 // can be done
@@ -0,0 +1,155 @@
+package otel
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// MetricExporter configures and returns a new exporter for OpenTelemetry
+// metrics. The dataset must be an OpenTelemetry metrics dataset (of kind
+// "otel:metrics:v1").
+func MetricExporter(ctx context.Context, dataset string, options ...MetricOption) (metric.Exporter, error) {
+	config, err := newMetricConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+	return metricExporter(ctx, dataset, config)
+}
+
+func newMetricConfig(options ...MetricOption) (metricConfig, error) {
+	config := defaultMetricConfig()
+
+	// Apply supplied options.
+	for _, option := range options {
+		if option == nil {
+			continue
+		} else if err := option.applyMetric(&config); err != nil {
+			return config, err
+		}
+	}
+
+	// Make sure to populate remaining fields from the environment, if not
+	// explicitly disabled, and validate the result.
+	if err := config.prepare(); err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+func metricExporter(ctx context.Context, dataset string, config metricConfig) (metric.Exporter, error) {
+	u, err := config.endpointURL()
+	if err != nil {
+		return nil, err
+	}
+
+	var temporalitySelector metric.TemporalitySelector
+	if temporality := config.Temporality; temporality != 0 {
+		temporalitySelector = func(metric.InstrumentKind) metricdata.Temporality {
+			return temporality
+		}
+	}
+
+	if config.Protocol == GRPC {
+		return metricGRPCExporter(ctx, u, dataset, config, temporalitySelector)
+	}
+	return metricHTTPExporter(ctx, u, dataset, config, temporalitySelector)
+}
+
+func metricHTTPExporter(ctx context.Context, u *url.URL, dataset string, config metricConfig, temporalitySelector metric.TemporalitySelector) (metric.Exporter, error) {
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(u.Host),
+	}
+	if u.Path != "" {
+		opts = append(opts, otlpmetrichttp.WithURLPath(u.Path))
+	}
+	if config.insecure(u) {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+	if config.Timeout > 0 {
+		opts = append(opts, otlpmetrichttp.WithTimeout(config.Timeout))
+	}
+	if headers := config.headers(dataset); len(headers) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(headers))
+	}
+	if temporalitySelector != nil {
+		opts = append(opts, otlpmetrichttp.WithTemporalitySelector(temporalitySelector))
+	}
+	return otlpmetrichttp.New(ctx, opts...)
+}
+
+func metricGRPCExporter(ctx context.Context, u *url.URL, dataset string, config metricConfig, temporalitySelector metric.TemporalitySelector) (metric.Exporter, error) {
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(config.grpcEndpoint(u)),
+	}
+	if config.insecure(u) {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if config.Timeout > 0 {
+		opts = append(opts, otlpmetricgrpc.WithTimeout(config.Timeout))
+	}
+	if headers := config.headers(dataset); len(headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(headers))
+	}
+	if temporalitySelector != nil {
+		opts = append(opts, otlpmetricgrpc.WithTemporalitySelector(temporalitySelector))
+	}
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// MeterProvider configures and returns a new OpenTelemetry meter provider. The
+// dataset must be an OpenTelemetry metrics dataset (of kind "otel:metrics:v1").
+func MeterProvider(ctx context.Context, dataset, serviceName, serviceVersion string, options ...MetricOption) (*metric.MeterProvider, error) {
+	config, err := newMetricConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := metricExporter(ctx, dataset, config)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := newResource(ctx, serviceName, serviceVersion, config.ResourceOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []metric.Option{
+		metric.WithReader(metric.NewPeriodicReader(exporter, metric.WithInterval(config.ExportInterval))),
+		metric.WithResource(rs),
+	}
+
+	return metric.NewMeterProvider(opts...), nil
+}
+
+// InitMetrics initializes OpenTelemetry metrics with the given service name,
+// version and options. The dataset must be an OpenTelemetry metrics dataset (of
+// kind "otel:metrics:v1"). If initialization succeeds, the returned cleanup
+// function must be called to shut down the meter provider and flush any
+// remaining metrics. The error returned by the cleanup function must be
+// checked, as well.
+func InitMetrics(ctx context.Context, dataset, serviceName, serviceVersion string, options ...MetricOption) (func() error, error) {
+	meterProvider, err := MeterProvider(ctx, dataset, serviceName, serviceVersion, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetMeterProvider(meterProvider)
+
+	closeFunc := func() error {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second*15)
+		defer cancel()
+
+		return meterProvider.Shutdown(ctx)
+	}
+
+	return closeFunc, nil
+}
@@ -0,0 +1,72 @@
+package otel
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+const (
+	defaultMetricAPIEndpoint    = "/v1/metrics"
+	defaultMetricExportInterval = time.Second * 10
+)
+
+type metricConfig struct {
+	exporterConfig
+
+	// Temporality is the temporality used for all instrument kinds. If unset,
+	// the OpenTelemetry SDKs default (cumulative) is used.
+	Temporality metricdata.Temporality
+	// ExportInterval is the interval at which metrics are collected and
+	// exported by the meter provider.
+	ExportInterval time.Duration
+}
+
+func defaultMetricConfig() metricConfig {
+	return metricConfig{
+		exporterConfig: defaultExporterConfig(defaultMetricAPIEndpoint),
+		ExportInterval: defaultMetricExportInterval,
+	}
+}
+
+// A MetricOption modifies the behaviour of OpenTelemetry metrics. Nonetheless,
+// the official "OTEL_*" environment variables are preferred over the options
+// or "AXIOM_*" environment variables.
+//
+// Every [Option] is also a MetricOption.
+type MetricOption interface {
+	applyMetric(c *metricConfig) error
+}
+
+// metricOptionFunc is a [MetricOption] that only applies to metrics.
+type metricOptionFunc func(c *metricConfig) error
+
+func (o metricOptionFunc) applyMetric(c *metricConfig) error { return o(c) }
+
+// SetTemporality specifies the temporality used by the metric exporter for
+// all instrument kinds. Valid values are [metricdata.CumulativeTemporality]
+// and [metricdata.DeltaTemporality].
+func SetTemporality(temporality metricdata.Temporality) MetricOption {
+	return metricOptionFunc(func(c *metricConfig) error {
+		switch temporality {
+		case metricdata.CumulativeTemporality, metricdata.DeltaTemporality:
+		default:
+			return fmt.Errorf("invalid temporality %s", temporality)
+		}
+		c.Temporality = temporality
+		return nil
+	})
+}
+
+// SetExportInterval specifies the interval at which the meter provider
+// collects and exports metrics. Defaults to 10 seconds.
+func SetExportInterval(interval time.Duration) MetricOption {
+	return metricOptionFunc(func(c *metricConfig) error {
+		if interval <= 0 {
+			return fmt.Errorf("invalid export interval %s", interval)
+		}
+		c.ExportInterval = interval
+		return nil
+	})
+}
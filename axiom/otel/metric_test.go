@@ -0,0 +1,97 @@
+package otel_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	axiotel "github.com/axiomhq/axiom-go/axiom/otel"
+)
+
+func TestMetrics(t *testing.T) {
+	var handlerCalled uint32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint32(&handlerCalled, 1)
+
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/v1/metrics", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer xaat-test-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "test-dataset", r.Header.Get("X-Axiom-Dataset"))
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := t.Context()
+
+	stop, err := axiotel.InitMetrics(ctx, "test-dataset", "axiom-go-otel-test", "v1.0.0",
+		axiotel.SetURL(srv.URL),
+		axiotel.SetToken("xaat-test-token"),
+		axiotel.SetNoEnv(),
+		axiotel.SetTemporality(metricdata.DeltaTemporality),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, stop)
+
+	counter, err := otel.Meter("main").Int64Counter("requests")
+	require.NoError(t, err)
+
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.Key("route").String("/")))
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.Key("route").String("/")))
+
+	// Stop meter provider which flushes all metrics.
+	require.NoError(t, stop())
+
+	assert.EqualValues(t, 1, atomic.LoadUint32(&handlerCalled))
+}
+
+func TestMetricOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []axiotel.MetricOption
+		err     string
+	}{
+		{
+			name:    "invalid temporality",
+			options: []axiotel.MetricOption{axiotel.SetTemporality(0)},
+			err:     "invalid temporality",
+		},
+		{
+			name:    "invalid export interval",
+			options: []axiotel.MetricOption{axiotel.SetExportInterval(0)},
+			err:     "invalid export interval 0s",
+		},
+		{
+			name:    "trace option",
+			options: []axiotel.MetricOption{axiotel.SetSampleRatio(0.5)},
+			err:     "trace option can not be used for metrics",
+		},
+		{
+			name:    "nil option",
+			options: []axiotel.MetricOption{axiotel.Option(nil)},
+			err:     "missing token",
+		},
+		{
+			name:    "missing token",
+			options: []axiotel.MetricOption{},
+			err:     "missing token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]axiotel.MetricOption{axiotel.SetNoEnv()}, tt.options...)
+
+			_, err := axiotel.MeterProvider(t.Context(), "test-dataset", "axiom-go-otel-test", "v1.0.0", options...)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package otel
+
+//go:generate go tool stringer -type=Protocol -linecomment -output=protocol_string.go
+
+// Protocol is the OTLP transport protocol used by an exporter to send data to
+// Axiom.
+type Protocol uint8
+
+// All available [Protocol]s.
+const (
+	HTTP Protocol = iota // http/protobuf
+	GRPC                 // grpc
+)
@@ -0,0 +1,25 @@
+// Code generated by "stringer -type=Protocol -linecomment -output=protocol_string.go"; DO NOT EDIT.
+
+package otel
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[HTTP-0]
+	_ = x[GRPC-1]
+}
+
+const _Protocol_name = "http/protobufgrpc"
+
+var _Protocol_index = [...]uint8{0, 13, 17}
+
+func (i Protocol) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_Protocol_index)-1 {
+		return "Protocol(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Protocol_name[_Protocol_index[idx]:_Protocol_index[idx+1]]
+}
@@ -0,0 +1,96 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	// Keep in sync with
+	// https://github.com/open-telemetry/opentelemetry-go/blob/main/sdk/resource/builtin.go#L16.
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+// newResource returns the default OpenTelemetry resource enriched with the
+// attributes detected by the given resource options, the given service name
+// and version as well as the user agent of this package.
+func newResource(ctx context.Context, serviceName, serviceVersion string, options ...resource.Option) (*resource.Resource, error) {
+	rs := resource.Default()
+
+	if len(options) > 0 {
+		detected, err := resource.New(ctx, options...)
+		// A partial resource is still valuable, so only fail on errors that
+		// are not caused by a single detector failing.
+		if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+			return nil, err
+		}
+		if rs, err = resource.Merge(rs, detected); err != nil {
+			return nil, err
+		}
+	}
+
+	return resource.Merge(rs, resource.NewWithAttributes(
+		// HINT(lukasmalkmus): [resource.Merge] will use the schema URL from the
+		// first resource, which is what we want to achieve here.
+		"",
+		semconv.ServiceNameKey.String(serviceName),
+		semconv.ServiceVersionKey.String(serviceVersion),
+		semconv.UserAgentOriginal(userAgent),
+	))
+}
+
+// kubernetesEnv maps Kubernetes resource attributes to the environment
+// variables they are read from, in order of preference. The environment
+// variables are usually populated using the Kubernetes downward API.
+var kubernetesEnv = []struct {
+	key attribute.Key
+	env []string
+}{
+	{semconv.K8SClusterNameKey, []string{"K8S_CLUSTER_NAME"}},
+	{semconv.K8SNamespaceNameKey, []string{"K8S_NAMESPACE_NAME", "POD_NAMESPACE"}},
+	{semconv.K8SNodeNameKey, []string{"K8S_NODE_NAME", "NODE_NAME"}},
+	{semconv.K8SPodNameKey, []string{"K8S_POD_NAME", "POD_NAME", "HOSTNAME"}},
+	{semconv.K8SPodUIDKey, []string{"K8S_POD_UID", "POD_UID"}},
+	{semconv.K8SContainerNameKey, []string{"K8S_CONTAINER_NAME", "CONTAINER_NAME"}},
+}
+
+type kubernetesDetector struct{}
+
+// KubernetesDetector returns a [resource.Detector] that detects Kubernetes
+// resource attributes (cluster, namespace, node, pod and container) from
+// environment variables, usually populated using the Kubernetes downward API:
+//
+//   - "k8s.cluster.name": "K8S_CLUSTER_NAME"
+//   - "k8s.namespace.name": "K8S_NAMESPACE_NAME" or "POD_NAMESPACE"
+//   - "k8s.node.name": "K8S_NODE_NAME" or "NODE_NAME"
+//   - "k8s.pod.name": "K8S_POD_NAME", "POD_NAME" or "HOSTNAME"
+//   - "k8s.pod.uid": "K8S_POD_UID" or "POD_UID"
+//   - "k8s.container.name": "K8S_CONTAINER_NAME" or "CONTAINER_NAME"
+//
+// Detection only happens when running inside a Kubernetes cluster, which is
+// determined by the presence of the "KUBERNETES_SERVICE_HOST" environment
+// variable.
+func KubernetesDetector() resource.Detector {
+	return kubernetesDetector{}
+}
+
+// Detect implements [resource.Detector].
+func (kubernetesDetector) Detect(context.Context) (*resource.Resource, error) {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return resource.Empty(), nil
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(kubernetesEnv))
+	for _, e := range kubernetesEnv {
+		for _, env := range e.env {
+			if v := os.Getenv(env); v != "" {
+				attrs = append(attrs, e.key.String(v))
+				break
+			}
+		}
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
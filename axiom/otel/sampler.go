@@ -0,0 +1,75 @@
+package otel
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// A SamplingRule selects the sampler used for spans that match the rule by
+// span name and/or attribute. A rule with neither a span name nor an attribute
+// matches all spans.
+type SamplingRule struct {
+	// SpanName matches spans with exactly this name. If empty, spans of any
+	// name are matched.
+	SpanName string
+	// Attribute matches spans started with this attribute, both key and value
+	// must be equal. If the key is empty, spans with any attributes are
+	// matched.
+	Attribute attribute.KeyValue
+	// Sampler makes the sampling decision for matched spans.
+	Sampler trace.Sampler
+}
+
+func (r SamplingRule) matches(p trace.SamplingParameters) bool {
+	if r.SpanName != "" && r.SpanName != p.Name {
+		return false
+	}
+	if r.Attribute.Key == "" {
+		return true
+	}
+	for _, attr := range p.Attributes {
+		if attr.Key == r.Attribute.Key && attr.Value == r.Attribute.Value {
+			return true
+		}
+	}
+	return false
+}
+
+type ruleSampler struct {
+	rules    []SamplingRule
+	fallback trace.Sampler
+}
+
+// NewRuleSampler returns a [trace.Sampler] that evaluates the given rules in
+// order and delegates the sampling decision to the sampler of the first
+// matching rule. Spans not matched by any rule are handled by the fallback
+// sampler.
+func NewRuleSampler(fallback trace.Sampler, rules ...SamplingRule) trace.Sampler {
+	return &ruleSampler{
+		rules:    rules,
+		fallback: fallback,
+	}
+}
+
+// ShouldSample implements [trace.Sampler].
+func (s *ruleSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	for _, rule := range s.rules {
+		if rule.matches(p) {
+			return rule.Sampler.ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+// Description implements [trace.Sampler].
+func (s *ruleSampler) Description() string {
+	rules := make([]string, len(s.rules))
+	for i, rule := range s.rules {
+		rules[i] = fmt.Sprintf("{SpanName:%q,Attribute:%s=%s,Sampler:%s}",
+			rule.SpanName, rule.Attribute.Key, rule.Attribute.Value.Emit(), rule.Sampler.Description())
+	}
+	return fmt.Sprintf("RuleSampler{Rules:[%s],Fallback:%s}", strings.Join(rules, ","), s.fallback.Description())
+}
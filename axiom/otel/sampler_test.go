@@ -0,0 +1,72 @@
+package otel_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	axiotel "github.com/axiomhq/axiom-go/axiom/otel"
+)
+
+func TestRuleSampler(t *testing.T) {
+	sampler := axiotel.NewRuleSampler(sdktrace.AlwaysSample(),
+		axiotel.SamplingRule{SpanName: "healthz", Sampler: sdktrace.NeverSample()},
+		axiotel.SamplingRule{Attribute: attribute.String("http.route", "/metrics"), Sampler: sdktrace.NeverSample()},
+		axiotel.SamplingRule{SpanName: "checkout", Attribute: attribute.Bool("debug", true), Sampler: sdktrace.NeverSample()},
+	)
+
+	tests := []struct {
+		name  string
+		span  string
+		attrs []attribute.KeyValue
+		want  sdktrace.SamplingDecision
+	}{
+		{
+			name: "no rule matches",
+			span: "foo",
+			want: sdktrace.RecordAndSample,
+		},
+		{
+			name: "span name matches",
+			span: "healthz",
+			want: sdktrace.Drop,
+		},
+		{
+			name:  "attribute matches",
+			span:  "foo",
+			attrs: []attribute.KeyValue{attribute.String("http.route", "/metrics")},
+			want:  sdktrace.Drop,
+		},
+		{
+			name:  "attribute value differs",
+			span:  "foo",
+			attrs: []attribute.KeyValue{attribute.String("http.route", "/")},
+			want:  sdktrace.RecordAndSample,
+		},
+		{
+			name:  "span name and attribute match",
+			span:  "checkout",
+			attrs: []attribute.KeyValue{attribute.Bool("debug", true)},
+			want:  sdktrace.Drop,
+		},
+		{
+			name: "span name matches but attribute missing",
+			span: "checkout",
+			want: sdktrace.RecordAndSample,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := sampler.ShouldSample(sdktrace.SamplingParameters{
+				ParentContext: t.Context(),
+				Name:          tt.span,
+				Attributes:    tt.attrs,
+			})
+			assert.Equal(t, tt.want, res.Decision)
+		})
+	}
+
+	assert.Contains(t, sampler.Description(), "RuleSampler{")
+}
@@ -3,18 +3,15 @@ package otel
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
-	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 
-	// Keep in sync with
-	// https://github.com/open-telemetry/opentelemetry-go/blob/main/sdk/resource/builtin.go#L16.
-	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
-
 	"github.com/axiomhq/axiom-go/internal/version"
 )
 
@@ -29,6 +26,14 @@ func init() {
 
 // TraceExporter configures and returns a new exporter for OpenTelemetry spans.
 func TraceExporter(ctx context.Context, dataset string, options ...TraceOption) (trace.SpanExporter, error) {
+	config, err := newTraceConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+	return traceExporter(ctx, dataset, config)
+}
+
+func newTraceConfig(options ...TraceOption) (traceConfig, error) {
 	config := defaultTraceConfig()
 
 	// Apply supplied options.
@@ -36,80 +41,93 @@ func TraceExporter(ctx context.Context, dataset string, options ...TraceOption)
 		if option == nil {
 			continue
 		} else if err := option(&config); err != nil {
-			return nil, err
+			return config, err
 		}
 	}
 
 	// Make sure to populate remaining fields from the environment, if not
-	// explicitly disabled.
-	if !config.NoEnv {
-		if err := config.IncorporateEnvironment(); err != nil {
-			return nil, err
-		}
+	// explicitly disabled, and validate the result.
+	if err := config.prepare(); err != nil {
+		return config, err
 	}
 
-	if err := config.Validate(); err != nil {
+	return config, nil
+}
+
+func traceExporter(ctx context.Context, dataset string, config traceConfig) (trace.SpanExporter, error) {
+	u, err := config.endpointURL()
+	if err != nil {
 		return nil, err
 	}
 
-	u, err := config.BaseURL().Parse(config.APIEndpoint)
-	if err != nil {
-		return nil, fmt.Errorf("parse exporter url: %w", err)
+	if config.Protocol == GRPC {
+		return otlptrace.New(ctx, traceGRPCClient(u, dataset, config))
 	}
+	return otlptrace.New(ctx, traceHTTPClient(u, dataset, config))
+}
 
+func traceHTTPClient(u *url.URL, dataset string, config traceConfig) otlptrace.Client {
 	opts := []otlptracehttp.Option{
 		otlptracehttp.WithEndpoint(u.Host),
 	}
 	if u.Path != "" {
 		opts = append(opts, otlptracehttp.WithURLPath(u.Path))
 	}
-	if u.Scheme == "http" {
+	if config.insecure(u) {
 		opts = append(opts, otlptracehttp.WithInsecure())
 	}
 	if config.Timeout > 0 {
 		opts = append(opts, otlptracehttp.WithTimeout(config.Timeout))
 	}
+	if headers := config.headers(dataset); len(headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(headers))
+	}
+	return otlptracehttp.NewClient(opts...)
+}
 
-	headers := make(map[string]string)
-	if config.Token() != "" {
-		headers["Authorization"] = "Bearer " + config.Token()
+func traceGRPCClient(u *url.URL, dataset string, config traceConfig) otlptrace.Client {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(config.grpcEndpoint(u)),
 	}
-	if config.OrganizationID() != "" {
-		headers["X-Axiom-Org-Id"] = config.OrganizationID()
+	if config.insecure(u) {
+		opts = append(opts, otlptracegrpc.WithInsecure())
 	}
-	if dataset != "" {
-		headers["X-Axiom-Dataset"] = dataset
+	if config.Timeout > 0 {
+		opts = append(opts, otlptracegrpc.WithTimeout(config.Timeout))
 	}
-	if len(headers) > 0 {
-		opts = append(opts, otlptracehttp.WithHeaders(headers))
+	if headers := config.headers(dataset); len(headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(headers))
 	}
-
-	return otlptrace.New(ctx, otlptracehttp.NewClient(opts...))
+	return otlptracegrpc.NewClient(opts...)
 }
 
 // TracerProvider configures and returns a new OpenTelemetry tracer provider.
 func TracerProvider(ctx context.Context, dataset, serviceName, serviceVersion string, options ...TraceOption) (*trace.TracerProvider, error) {
-	exporter, err := TraceExporter(ctx, dataset, options...)
+	config, err := newTraceConfig(options...)
 	if err != nil {
 		return nil, err
 	}
 
-	rs, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
-		// HINT(lukasmalkmus): [resource.Merge] will use the schema URL from the
-		// first resource, which is what we want to achieve here.
-		"",
-		semconv.ServiceNameKey.String(serviceName),
-		semconv.ServiceVersionKey.String(serviceVersion),
-		semconv.UserAgentOriginal(userAgent),
-	))
+	exporter, err := traceExporter(ctx, dataset, config)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := newResource(ctx, serviceName, serviceVersion, config.ResourceOptions...)
 	if err != nil {
 		return nil, err
 	}
 
 	opts := []trace.TracerProviderOption{
-		trace.WithBatcher(exporter, trace.WithMaxQueueSize(1024*10)),
+		trace.WithBatcher(exporter, config.BatchOptions...),
 		trace.WithResource(rs),
 	}
+	if sampler := config.sampler(); sampler != nil {
+		opts = append(opts, trace.WithSampler(sampler))
+	}
+	for _, processor := range config.SpanProcessors {
+		opts = append(opts, trace.WithSpanProcessor(processor))
+	}
 
 	return trace.NewTracerProvider(opts...), nil
 }
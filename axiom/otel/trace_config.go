@@ -1,29 +1,61 @@
 package otel
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
-	"github.com/axiomhq/axiom-go/internal/config"
+	"go.opentelemetry.io/otel/sdk/trace"
 )
 
-const defaultTraceAPIEndpoint = "/v1/traces"
+const (
+	defaultTraceAPIEndpoint = "/v1/traces"
+	defaultMaxQueueSize     = 1024 * 10
+)
 
 type traceConfig struct {
-	config.Config
-
-	// APIEndpoint is the endpoint to use for the trace exporter.
-	APIEndpoint string
-	// Timeout is the timeout for the trace exporters underlying [http.Client].
-	Timeout time.Duration
-	// NoEnv disables the use of "AXIOM_*" environment variables.
-	NoEnv bool
+	exporterConfig
+
+	// Sampler is the sampler used by the tracer provider for spans that are not
+	// matched by any of the SamplingRules. If unset, the OpenTelemetry SDKs
+	// default (parent based, always sampling root spans) is used.
+	Sampler trace.Sampler
+	// SamplingRules are evaluated in order before falling back to the Sampler.
+	SamplingRules []SamplingRule
+	// BatchOptions are passed to the batch span processor of the tracer
+	// provider.
+	BatchOptions []trace.BatchSpanProcessorOption
+	// SpanProcessors are registered with the tracer provider in addition to
+	// the batch span processor that exports spans to Axiom.
+	SpanProcessors []trace.SpanProcessor
 }
 
 func defaultTraceConfig() traceConfig {
 	return traceConfig{
-		Config:      config.Default(),
-		APIEndpoint: defaultTraceAPIEndpoint,
+		exporterConfig: defaultExporterConfig(defaultTraceAPIEndpoint),
+		BatchOptions: []trace.BatchSpanProcessorOption{
+			trace.WithMaxQueueSize(defaultMaxQueueSize),
+		},
+	}
+}
+
+// sampler returns the sampler to configure the tracer provider with or nil, if
+// the OpenTelemetry SDKs default should be used.
+func (c traceConfig) sampler() trace.Sampler {
+	if len(c.SamplingRules) == 0 {
+		return c.Sampler
+	}
+	fallback := c.Sampler
+	if fallback == nil {
+		fallback = trace.ParentBased(trace.AlwaysSample())
 	}
+	return NewRuleSampler(fallback, c.SamplingRules...)
+}
+
+// hasTraceSettings reports whether any of the settings specific to traces are
+// set.
+func (c traceConfig) hasTraceSettings() bool {
+	return c.Sampler != nil || len(c.SamplingRules) > 0 || len(c.BatchOptions) > 0 || len(c.SpanProcessors) > 0
 }
 
 // A TraceOption modifies the behaviour of OpenTelemetry traces. Nonetheless,
@@ -31,48 +63,123 @@ func defaultTraceConfig() traceConfig {
 // "AXIOM_*" environment variables.
 type TraceOption func(c *traceConfig) error
 
-// SetURL sets the base URL used by the client.
-//
-// Can also be specified using the "AXIOM_URL" environment variable.
-func SetURL(baseURL string) TraceOption {
-	return func(c *traceConfig) error { return c.Options(config.SetURL(baseURL)) }
+// applyMetric makes every [Option] usable as a [MetricOption]. Options that
+// are specific to traces are rejected.
+func (o TraceOption) applyMetric(c *metricConfig) error {
+	if o == nil {
+		return nil
+	}
+	tc := traceConfig{exporterConfig: c.exporterConfig}
+	if err := o(&tc); err != nil {
+		return err
+	} else if tc.hasTraceSettings() {
+		return errors.New("trace option can not be used for metrics")
+	}
+	c.exporterConfig = tc.exporterConfig
+	return nil
+}
+
+// SetSampler specifies the sampler used by the tracer provider. Use
+// [trace.ParentBased] to respect the sampling decision of the parent span.
+// Defaults to a parent based sampler that samples all root spans.
+func SetSampler(sampler trace.Sampler) TraceOption {
+	return func(c *traceConfig) error {
+		if sampler == nil {
+			return errors.New("sampler must not be nil")
+		}
+		c.Sampler = sampler
+		return nil
+	}
+}
+
+// SetSampleRatio configures the tracer provider to sample the given fraction of
+// root spans, while child spans follow the sampling decision of their parent.
+// The ratio must be in the range [0, 1].
+func SetSampleRatio(ratio float64) TraceOption {
+	return func(c *traceConfig) error {
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("invalid sample ratio %g", ratio)
+		}
+		c.Sampler = trace.ParentBased(trace.TraceIDRatioBased(ratio))
+		return nil
+	}
+}
+
+// SetSamplingRules specifies rules that select a sampler by span name or
+// attribute. The rules are evaluated in order and the sampler of the first
+// matching rule decides. Spans not matched by any rule are handled by the
+// sampler configured with [SetSampler] or [SetSampleRatio].
+func SetSamplingRules(rules ...SamplingRule) TraceOption {
+	return func(c *traceConfig) error {
+		for _, rule := range rules {
+			if rule.Sampler == nil {
+				return errors.New("sampling rule sampler must not be nil")
+			}
+		}
+		c.SamplingRules = append(c.SamplingRules, rules...)
+		return nil
+	}
 }
 
-// SetToken specifies the authentication token used by the client.
-//
-// Can also be specified using the "AXIOM_TOKEN" environment variable.
-func SetToken(token string) TraceOption {
-	return func(c *traceConfig) error { return c.Options(config.SetToken(token)) }
+// SetBatchMaxQueueSize specifies the maximum number of spans buffered by the
+// tracer provider before they are dropped. Defaults to 10240.
+func SetBatchMaxQueueSize(size int) TraceOption {
+	return func(c *traceConfig) error {
+		if size <= 0 {
+			return fmt.Errorf("invalid batch max queue size %d", size)
+		}
+		c.BatchOptions = append(c.BatchOptions, trace.WithMaxQueueSize(size))
+		return nil
+	}
 }
 
-// SetOrganizationID specifies the organization ID used by the client.
-//
-// Can also be specified using the "AXIOM_ORG_ID" environment variable.
-func SetOrganizationID(organizationID string) TraceOption {
-	return func(c *traceConfig) error { return c.Options(config.SetOrganizationID(organizationID)) }
+// SetBatchMaxExportBatchSize specifies the maximum number of spans exported to
+// Axiom in a single request.
+func SetBatchMaxExportBatchSize(size int) TraceOption {
+	return func(c *traceConfig) error {
+		if size <= 0 {
+			return fmt.Errorf("invalid batch max export batch size %d", size)
+		}
+		c.BatchOptions = append(c.BatchOptions, trace.WithMaxExportBatchSize(size))
+		return nil
+	}
 }
 
-// SetAPIEndpoint specifies the api endpoint used by the client.
-func SetAPIEndpoint(path string) TraceOption {
+// SetBatchTimeout specifies the maximum delay before buffered spans are
+// exported to Axiom.
+func SetBatchTimeout(timeout time.Duration) TraceOption {
 	return func(c *traceConfig) error {
-		c.APIEndpoint = path
+		if timeout <= 0 {
+			return fmt.Errorf("invalid batch timeout %s", timeout)
+		}
+		c.BatchOptions = append(c.BatchOptions, trace.WithBatchTimeout(timeout))
 		return nil
 	}
 }
 
-// SetTimeout specifies the http timeout used by the client.
-func SetTimeout(timeout time.Duration) TraceOption {
+// SetBatchExportTimeout specifies how long the tracer provider waits for a
+// single export of spans to Axiom before giving up.
+func SetBatchExportTimeout(timeout time.Duration) TraceOption {
 	return func(c *traceConfig) error {
-		c.Timeout = timeout
+		if timeout <= 0 {
+			return fmt.Errorf("invalid batch export timeout %s", timeout)
+		}
+		c.BatchOptions = append(c.BatchOptions, trace.WithExportTimeout(timeout))
 		return nil
 	}
 }
 
-// SetNoEnv prevents the client from deriving its configuration from the
-// environment (by auto reading "AXIOM_*" environment variables).
-func SetNoEnv() TraceOption {
+// SetSpanProcessors registers additional span processors with the tracer
+// provider. They are invoked in addition to the processor that exports spans to
+// Axiom.
+func SetSpanProcessors(processors ...trace.SpanProcessor) TraceOption {
 	return func(c *traceConfig) error {
-		c.NoEnv = true
+		for _, processor := range processors {
+			if processor == nil {
+				return errors.New("span processor must not be nil")
+			}
+		}
+		c.SpanProcessors = append(c.SpanProcessors, processors...)
 		return nil
 	}
 }
@@ -2,6 +2,7 @@ package otel_test
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"sync/atomic"
@@ -12,6 +13,13 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	collectortracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	axiotel "github.com/axiomhq/axiom-go/axiom/otel"
 )
@@ -65,3 +73,150 @@ func TestTracing(t *testing.T) {
 
 	assert.EqualValues(t, 1, atomic.LoadUint32(&handlerCalled))
 }
+
+type traceCollector struct {
+	collectortracepb.UnimplementedTraceServiceServer
+
+	exportCalled uint32
+	md           atomic.Pointer[metadata.MD]
+}
+
+func (c *traceCollector) Export(ctx context.Context, _ *collectortracepb.ExportTraceServiceRequest) (*collectortracepb.ExportTraceServiceResponse, error) {
+	atomic.AddUint32(&c.exportCalled, 1)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		c.md.Store(&md)
+	}
+	return &collectortracepb.ExportTraceServiceResponse{}, nil
+}
+
+func TestTracing_GRPC(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	collector := new(traceCollector)
+	srv := grpc.NewServer()
+	collectortracepb.RegisterTraceServiceServer(srv, collector)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	ctx := t.Context()
+
+	tp, err := axiotel.TracerProvider(ctx, "test-dataset", "axiom-go-otel-test", "v1.0.0",
+		axiotel.SetURL("https://"+lis.Addr().String()),
+		axiotel.SetToken("xaat-test-token"),
+		axiotel.SetOrganizationID("test-org"),
+		axiotel.SetProtocol(axiotel.GRPC),
+		axiotel.SetInsecure(),
+		axiotel.SetNoEnv(),
+	)
+	require.NoError(t, err)
+
+	_, span := tp.Tracer("main").Start(ctx, "foo")
+	span.End()
+
+	// Shutdown tracer provider which flushes all spans.
+	require.NoError(t, tp.Shutdown(ctx))
+
+	assert.EqualValues(t, 1, atomic.LoadUint32(&collector.exportCalled))
+	if md := collector.md.Load(); assert.NotNil(t, md) {
+		assert.Equal(t, []string{"Bearer xaat-test-token"}, md.Get("authorization"))
+		assert.Equal(t, []string{"test-org"}, md.Get("x-axiom-org-id"))
+		assert.Equal(t, []string{"test-dataset"}, md.Get("x-axiom-dataset"))
+	}
+}
+
+func TestSetProtocol(t *testing.T) {
+	_, err := axiotel.TraceExporter(t.Context(), "test-dataset",
+		axiotel.SetToken("xaat-test-token"),
+		axiotel.SetProtocol(axiotel.Protocol(42)),
+		axiotel.SetNoEnv(),
+	)
+	assert.EqualError(t, err, "invalid protocol Protocol(42)")
+}
+
+func TestTracerProvider_Options(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("K8S_POD_NAME", "axiom-go-otel-test-0")
+
+	ctx := t.Context()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp, err := axiotel.TracerProvider(ctx, "test-dataset", "axiom-go-otel-test", "v1.0.0",
+		axiotel.SetURL(srv.URL),
+		axiotel.SetToken("xaat-test-token"),
+		axiotel.SetNoEnv(),
+		axiotel.SetSampleRatio(1),
+		axiotel.SetSamplingRules(
+			axiotel.SamplingRule{SpanName: "healthz", Sampler: sdktrace.NeverSample()},
+			axiotel.SamplingRule{Attribute: attribute.Bool("debug", true), Sampler: sdktrace.NeverSample()},
+		),
+		axiotel.SetBatchMaxQueueSize(16),
+		axiotel.SetBatchMaxExportBatchSize(8),
+		axiotel.SetBatchExportTimeout(time.Second),
+		axiotel.SetResourceOptions(sdkresource.WithDetectors(axiotel.KubernetesDetector())),
+		axiotel.SetSpanProcessors(recorder),
+	)
+	require.NoError(t, err)
+
+	tr := tp.Tracer("main")
+
+	_, span := tr.Start(ctx, "healthz")
+	span.End()
+	_, span = tr.Start(ctx, "foo", trace.WithAttributes(attribute.Bool("debug", true)))
+	span.End()
+	_, span = tr.Start(ctx, "foo")
+	span.End()
+
+	require.NoError(t, tp.Shutdown(ctx))
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "foo", spans[0].Name())
+		assert.Contains(t, spans[0].Resource().Attributes(), attribute.String("k8s.pod.name", "axiom-go-otel-test-0"))
+		assert.Contains(t, spans[0].Resource().Attributes(), attribute.String("service.name", "axiom-go-otel-test"))
+	}
+}
+
+func TestTracerProvider_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		option axiotel.TraceOption
+		err    string
+	}{
+		{
+			name:   "nil sampler",
+			option: axiotel.SetSampler(nil),
+			err:    "sampler must not be nil",
+		},
+		{
+			name:   "invalid sample ratio",
+			option: axiotel.SetSampleRatio(1.5),
+			err:    "invalid sample ratio 1.5",
+		},
+		{
+			name:   "invalid batch max queue size",
+			option: axiotel.SetBatchMaxQueueSize(0),
+			err:    "invalid batch max queue size 0",
+		},
+		{
+			name:   "invalid batch export timeout",
+			option: axiotel.SetBatchExportTimeout(-time.Second),
+			err:    "invalid batch export timeout -1s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := axiotel.TracerProvider(t.Context(), "test-dataset", "axiom-go-otel-test", "v1.0.0",
+				axiotel.SetToken("xaat-test-token"),
+				axiotel.SetNoEnv(),
+				tt.option,
+			)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
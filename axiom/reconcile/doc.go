@@ -0,0 +1,31 @@
+// Package reconcile manages monitors, notifiers and virtual fields as code. A
+// [Reconciler] compares a desired [State], defined in Go or loaded from YAML or
+// JSON files, with the resources of an organization and computes a [Plan] of
+// creates, updates and deletes. Applying the plan is idempotent: once applied,
+// planning the same state again results in an empty plan.
+//
+// Only resources labeled as managed by the owner of the reconciler are updated
+// or deleted. Resources managed by hand are left alone.
+//
+// Usage:
+//
+//	state, err := reconcile.LoadFiles("monitors.yaml", "vfields.yaml")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	r, err := reconcile.New("my-service", reconcile.SetClient(client))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	plan, err := r.Plan(ctx, state)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Print(plan) // Dry run.
+//
+//	if err = r.Apply(ctx, plan); err != nil {
+//		log.Fatal(err)
+//	}
+package reconcile
@@ -0,0 +1,218 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+//go:generate go tool stringer -type=Action,Kind -linecomment -output=plan_string.go
+
+// Action is the action a [Change] performs on a resource.
+type Action uint8
+
+// All available [Action]s.
+const (
+	ActionCreate Action = iota + 1 // create
+	ActionUpdate                   // update
+	ActionDelete                   // delete
+)
+
+// Kind is the kind of resource a [Change] applies to.
+type Kind uint8
+
+// All available [Kind]s.
+const (
+	KindNotifier     Kind = iota + 1 // notifier
+	KindMonitor                      // monitor
+	KindVirtualField                 // virtual field
+)
+
+// metadataFields are set by the server and never compared.
+var metadataFields = []string{"id", "createdAt", "createdBy", "updatedAt"}
+
+// Plan is the set of changes needed to reconcile the actual state with the
+// desired one. It is created by [Reconciler.Plan] and applied by
+// [Reconciler.Apply].
+type Plan struct {
+	// Changes in the order they are applied: creates and updates of notifiers,
+	// monitors and virtual fields, followed by deletes of monitors, virtual
+	// fields and notifiers.
+	Changes []Change
+}
+
+// Empty reports whether the plan has no changes, i.e. the actual state matches
+// the desired one.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String returns a human-readable diff of the plan, suitable for a dry run.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes.\n"
+	}
+
+	var sb strings.Builder
+	for _, c := range p.Changes {
+		sb.WriteString(c.String())
+		sb.WriteByte('\n')
+		for _, f := range c.Fields {
+			fmt.Fprintf(&sb, "    %s\n", f)
+		}
+	}
+
+	var created, updated, deleted int
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ActionCreate:
+			created++
+		case ActionUpdate:
+			updated++
+		case ActionDelete:
+			deleted++
+		}
+	}
+	fmt.Fprintf(&sb, "\nPlan: %d to create, %d to update, %d to delete.\n", created, updated, deleted)
+
+	return sb.String()
+}
+
+// Change is a single create, update or delete of a resource.
+type Change struct {
+	// Action performed by the change.
+	Action Action
+	// Kind of the resource.
+	Kind Kind
+	// Name identifies the resource. For virtual fields, it is prefixed with
+	// the dataset, e.g. "logs/status".
+	Name string
+	// ID of the existing resource. Empty for creates.
+	ID string
+	// Fields that change by an update.
+	Fields []FieldChange
+
+	apply func(ctx context.Context) error
+}
+
+// String returns a string representation of the change, e.g.
+// `~ update monitor "Errors"`.
+func (c Change) String() string {
+	var sign string
+	switch c.Action {
+	case ActionCreate:
+		sign = "+"
+	case ActionUpdate:
+		sign = "~"
+	case ActionDelete:
+		sign = "-"
+	}
+	return fmt.Sprintf("%s %s %s %q", sign, c.Action, c.Kind, c.Name)
+}
+
+// FieldChange is the change of a single field by an update. Fields are named
+// and their values are represented like in the JSON representation of the
+// resource.
+type FieldChange struct {
+	// Field is the name of the field.
+	Field string
+	// Old is the current value of the field.
+	Old any
+	// New is the desired value of the field.
+	New any
+	// Sensitive is true if the values might contain secrets. They are hidden
+	// from the string representation.
+	Sensitive bool
+}
+
+// String returns a string representation of the field change, e.g.
+// "threshold: 5 -> 10".
+func (f FieldChange) String() string {
+	if f.Sensitive {
+		return f.Field + ": (sensitive value changed)"
+	}
+	return fmt.Sprintf("%s: %s -> %s", f.Field, formatValue(f.Old), formatValue(f.New))
+}
+
+func formatValue(v any) string {
+	if v == nil {
+		return "null"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// diffFields returns the changes between the JSON representations of the
+// given resources. Server managed metadata is ignored and empty values are
+// considered equal.
+func diffFields(existing, desired any, sensitive []string) ([]FieldChange, error) {
+	oldFields, err := toFields(existing)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := toFields(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := slices.Collect(maps.Keys(oldFields))
+	for k := range newFields {
+		if _, ok := oldFields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var changes []FieldChange
+	for _, k := range keys {
+		if slices.Contains(metadataFields, k) {
+			continue
+		}
+		oldValue, newValue := oldFields[k], newFields[k]
+		if (isEmpty(oldValue) && isEmpty(newValue)) || reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, FieldChange{
+			Field:     k,
+			Old:       oldValue,
+			New:       newValue,
+			Sensitive: slices.Contains(sensitive, k),
+		})
+	}
+	return changes, nil
+}
+
+func toFields(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// isEmpty reports whether the JSON value is null or an empty string, array or
+// object.
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
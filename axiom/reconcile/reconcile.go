@@ -0,0 +1,501 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+// ErrUnmanaged is returned by [Reconciler.Plan] if a desired resource has the
+// same name as an existing resource that is not managed by the owner. The
+// reconciler never touches resources it doesn't own.
+var ErrUnmanaged = errors.New("resource exists but is not managed")
+
+// An Option modifies the configuration of a [Reconciler].
+type Option func(*Reconciler) error
+
+// SetClient makes the reconciler manage monitors, notifiers and virtual fields
+// using the services of the given client. Managed virtual fields are looked up
+// in the datasets of the desired virtual fields and the given ones.
+func SetClient(client *axiom.Client, datasets ...string) Option {
+	return func(r *Reconciler) error {
+		r.monitors = client.Monitors
+		r.notifiers = client.Notifiers
+		r.vfields = client.VirtualFields
+		r.datasets = append(r.datasets, datasets...)
+		return nil
+	}
+}
+
+// SetMonitors makes the reconciler manage monitors using the given service.
+func SetMonitors(monitors axiom.Monitors) Option {
+	return func(r *Reconciler) error {
+		r.monitors = monitors
+		return nil
+	}
+}
+
+// SetNotifiers makes the reconciler manage notifiers using the given service.
+func SetNotifiers(notifiers axiom.Notifiers) Option {
+	return func(r *Reconciler) error {
+		r.notifiers = notifiers
+		return nil
+	}
+}
+
+// SetVirtualFields makes the reconciler manage virtual fields using the given
+// service. Managed virtual fields are looked up in the datasets of the desired
+// virtual fields and the given ones, so virtual fields are deleted from
+// datasets that no longer have any desired ones.
+func SetVirtualFields(vfields axiom.VirtualFields, datasets ...string) Option {
+	return func(r *Reconciler) error {
+		r.vfields = vfields
+		r.datasets = append(r.datasets, datasets...)
+		return nil
+	}
+}
+
+// Reconciler reconciles monitors, notifiers and virtual fields with a desired
+// [State].
+//
+// Resources are marked as managed by an owner using a label of the form
+// "[managed-by:owner]". It is appended to the description of monitors and
+// virtual fields and to the name of notifiers, which have no description.
+// Resources without the label of the owner are left alone, so multiple owners
+// can manage resources of the same organization side by side with the ones
+// managed by hand.
+type Reconciler struct {
+	owner string
+	label string
+
+	monitors  axiom.Monitors
+	notifiers axiom.Notifiers
+	vfields   axiom.VirtualFields
+	datasets  []string
+}
+
+// New creates a new [Reconciler] for the resources of the given owner. At
+// least one of [SetClient], [SetMonitors], [SetNotifiers] or
+// [SetVirtualFields] must be passed. Resources of kinds without a service are
+// neither created nor deleted.
+func New(owner string, options ...Option) (*Reconciler, error) {
+	if owner == "" {
+		return nil, errors.New("owner must not be empty")
+	} else if strings.ContainsAny(owner, "[]") {
+		return nil, fmt.Errorf("owner %q must not contain brackets", owner)
+	}
+
+	r := &Reconciler{
+		owner: owner,
+		label: "[managed-by:" + owner + "]",
+	}
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.monitors == nil && r.notifiers == nil && r.vfields == nil {
+		return nil, errors.New("no services to reconcile")
+	}
+
+	return r, nil
+}
+
+// Reconcile plans and applies the changes needed to reach the desired state.
+// It returns the applied plan. Reconciling the same state again results in an
+// empty plan.
+func (r *Reconciler) Reconcile(ctx context.Context, state *State) (*Plan, error) {
+	plan, err := r.Plan(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	return plan, r.Apply(ctx, plan)
+}
+
+// Plan computes the changes needed to reach the desired state without
+// applying them.
+func (r *Reconciler) Plan(ctx context.Context, state *State) (*Plan, error) {
+	var upserts, deletes [3][]Change
+
+	// notifierIDs maps the names of the managed notifiers to their IDs. It is
+	// completed by the notifier creates of the plan, which are applied before
+	// the monitors referencing them by name.
+	notifierIDs := make(map[string]string)
+
+	if len(state.Notifiers) > 0 && r.notifiers == nil {
+		return nil, errors.New("notifiers desired, but no notifiers service set")
+	} else if r.notifiers != nil {
+		existing, err := r.notifiers.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list notifiers: %w", err)
+		}
+		for _, n := range deref(existing) {
+			if key := r.withoutLabel(n.Name); r.hasLabel(n.Name) && notifierIDs[key] == "" {
+				notifierIDs[key] = n.ID
+			}
+		}
+		if upserts[0], deletes[2], err = plan(r.notifierSpec(notifierIDs), state.Notifiers, deref(existing)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(state.Monitors) > 0 && r.monitors == nil {
+		return nil, errors.New("monitors desired, but no monitors service set")
+	} else if r.monitors != nil {
+		desired, refs, err := resolveMonitors(state, notifierIDs)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := r.monitors.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list monitors: %w", err)
+		}
+		if upserts[1], deletes[0], err = plan(r.monitorSpec(refs, notifierIDs), desired, deref(existing)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(state.VirtualFields) > 0 && r.vfields == nil {
+		return nil, errors.New("virtual fields desired, but no virtual fields service set")
+	} else if r.vfields != nil {
+		datasets := slices.Clone(r.datasets)
+		desired := make([]axiom.VirtualFieldWithID, len(state.VirtualFields))
+		for i, vfield := range state.VirtualFields {
+			desired[i] = axiom.VirtualFieldWithID{VirtualField: vfield}
+			datasets = append(datasets, vfield.Dataset)
+		}
+		slices.Sort(datasets)
+
+		var existing []axiom.VirtualFieldWithID
+		for _, dataset := range slices.Compact(datasets) {
+			vfields, err := r.vfields.List(ctx, dataset)
+			if err != nil {
+				return nil, fmt.Errorf("list virtual fields of dataset %q: %w", dataset, err)
+			}
+			existing = append(existing, deref(vfields)...)
+		}
+
+		var err error
+		if upserts[2], deletes[1], err = plan(r.virtualFieldSpec(), desired, existing); err != nil {
+			return nil, err
+		}
+	}
+
+	p := new(Plan)
+	for _, changes := range upserts {
+		p.Changes = append(p.Changes, changes...)
+	}
+	for _, changes := range deletes {
+		p.Changes = append(p.Changes, changes...)
+	}
+	return p, nil
+}
+
+// Apply applies the changes of the plan in order. It stops at the first change
+// that fails. As every change is applied at most once, a failed plan can be
+// continued by planning and applying again.
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan) error {
+	for _, c := range plan.Changes {
+		if c.apply == nil {
+			return fmt.Errorf("%s: change not planned by a reconciler", c)
+		}
+		if err := c.apply(ctx); err != nil {
+			return fmt.Errorf("%s %s %q: %w", c.Action, c.Kind, c.Name, err)
+		}
+	}
+	return nil
+}
+
+// spec describes how resources of type T are reconciled.
+type spec[T any] struct {
+	kind      Kind
+	sensitive []string
+
+	// key identifies the resource, regardless of the ownership label.
+	key func(T) string
+	// id returns the ID of an existing resource.
+	id func(T) string
+	// owned reports whether the resource carries the ownership label.
+	owned func(T) bool
+	// label returns the resource with the ownership label applied.
+	label func(T) T
+
+	create func(context.Context, T) error
+	update func(context.Context, string, T) error
+	delete func(context.Context, string) error
+}
+
+// plan returns the creates and updates as well as the deletes needed to
+// reconcile the existing resources with the desired ones.
+func plan[T any](s spec[T], desired, existing []T) (upserts, deletes []Change, err error) {
+	var (
+		managed   = make(map[string][]T)
+		unmanaged = make(map[string]bool)
+		keys      []string
+	)
+	for _, res := range existing {
+		key := s.key(res)
+		if !s.owned(res) {
+			unmanaged[key] = true
+			continue
+		}
+		if _, ok := managed[key]; !ok {
+			keys = append(keys, key)
+		}
+		managed[key] = append(managed[key], res)
+	}
+
+	seen := make(map[string]bool, len(desired))
+	for _, res := range desired {
+		key := s.key(res)
+		if seen[key] {
+			return nil, nil, fmt.Errorf("duplicate %s %q", s.kind, key)
+		}
+		seen[key] = true
+
+		want := s.label(res)
+
+		candidates := managed[key]
+		if len(candidates) == 0 {
+			if unmanaged[key] {
+				return nil, nil, fmt.Errorf("%s %q: %w", s.kind, key, ErrUnmanaged)
+			}
+			upserts = append(upserts, Change{
+				Action: ActionCreate,
+				Kind:   s.kind,
+				Name:   key,
+				apply:  func(ctx context.Context) error { return s.create(ctx, want) },
+			})
+			continue
+		}
+
+		current := candidates[0]
+		fields, err := diffFields(current, want, s.sensitive)
+		if err != nil {
+			return nil, nil, fmt.Errorf("compare %s %q: %w", s.kind, key, err)
+		}
+		if len(fields) > 0 {
+			id := s.id(current)
+			upserts = append(upserts, Change{
+				Action: ActionUpdate,
+				Kind:   s.kind,
+				Name:   key,
+				ID:     id,
+				Fields: fields,
+				apply:  func(ctx context.Context) error { return s.update(ctx, id, want) },
+			})
+		}
+
+		// Duplicates of managed resources, e.g. created by an interrupted
+		// apply, are removed.
+		for _, res := range candidates[1:] {
+			deletes = append(deletes, deleteChange(s, key, res))
+		}
+	}
+
+	slices.Sort(keys)
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		for _, res := range managed[key] {
+			deletes = append(deletes, deleteChange(s, key, res))
+		}
+	}
+
+	return upserts, deletes, nil
+}
+
+func deleteChange[T any](s spec[T], key string, res T) Change {
+	id := s.id(res)
+	return Change{
+		Action: ActionDelete,
+		Kind:   s.kind,
+		Name:   key,
+		ID:     id,
+		apply:  func(ctx context.Context) error { return s.delete(ctx, id) },
+	}
+}
+
+// resolveMonitors returns the desired monitors with the notifiers referenced by
+// name added to their notifier IDs, as far as the notifiers already exist.
+// Notifiers that don't exist yet are added by name, so the change shows up in
+// the plan. The monitors referencing notifiers by name are returned by name,
+// to resolve the IDs again once the notifiers are created.
+func resolveMonitors(state *State, notifierIDs map[string]string) ([]axiom.Monitor, map[string]Monitor, error) {
+	desired := make([]string, len(state.Notifiers))
+	for i, n := range state.Notifiers {
+		desired[i] = n.Name
+	}
+
+	var (
+		monitors = make([]axiom.Monitor, len(state.Monitors))
+		refs     = make(map[string]Monitor)
+	)
+	for i, m := range state.Monitors {
+		for _, name := range m.Notifiers {
+			if !slices.Contains(desired, name) {
+				return nil, nil, fmt.Errorf("%s %q: unknown notifier %q", KindMonitor, m.Name, name)
+			}
+		}
+		if len(m.Notifiers) > 0 {
+			refs[m.Name] = m
+		}
+
+		monitors[i] = m.Monitor
+		monitors[i].NotifierIDs = m.notifierIDs(func(name string) string {
+			if id, ok := notifierIDs[name]; ok {
+				return id
+			}
+			return name
+		})
+	}
+	return monitors, refs, nil
+}
+
+// notifierIDs returns the IDs of the notifiers referenced by ID followed by
+// the ones of the notifiers referenced by name, as returned by the given
+// function.
+func (m Monitor) notifierIDs(id func(name string) string) []string {
+	ids := slices.Clone(m.NotifierIDs)
+	for _, name := range m.Notifiers {
+		ids = append(ids, id(name))
+	}
+	return ids
+}
+
+func (r *Reconciler) monitorSpec(refs map[string]Monitor, notifierIDs map[string]string) spec[axiom.Monitor] {
+	// resolve sets the IDs of the notifiers referenced by name, which are
+	// known once the notifiers are created.
+	resolve := func(m axiom.Monitor) (axiom.Monitor, error) {
+		ref, ok := refs[m.Name]
+		if !ok {
+			return m, nil
+		}
+		for _, name := range ref.Notifiers {
+			if _, ok := notifierIDs[name]; !ok {
+				return m, fmt.Errorf("%s %q not found", KindNotifier, name)
+			}
+		}
+		m.NotifierIDs = ref.notifierIDs(func(name string) string { return notifierIDs[name] })
+		return m, nil
+	}
+
+	return spec[axiom.Monitor]{
+		kind:  KindMonitor,
+		key:   func(m axiom.Monitor) string { return m.Name },
+		id:    func(m axiom.Monitor) string { return m.ID },
+		owned: func(m axiom.Monitor) bool { return r.hasLabel(m.Description) },
+		label: func(m axiom.Monitor) axiom.Monitor {
+			m.ID = ""
+			m.Description = r.withLabel(m.Description)
+			return m
+		},
+		create: func(ctx context.Context, m axiom.Monitor) error {
+			m, err := resolve(m)
+			if err != nil {
+				return err
+			}
+			_, err = r.monitors.Create(ctx, axiom.MonitorCreateRequest{Monitor: m})
+			return err
+		},
+		update: func(ctx context.Context, id string, m axiom.Monitor) error {
+			m, err := resolve(m)
+			if err != nil {
+				return err
+			}
+			_, err = r.monitors.Update(ctx, id, axiom.MonitorUpdateRequest{Monitor: m})
+			return err
+		},
+		delete: func(ctx context.Context, id string) error {
+			return r.monitors.Delete(ctx, id)
+		},
+	}
+}
+
+func (r *Reconciler) notifierSpec(notifierIDs map[string]string) spec[axiom.Notifier] {
+	return spec[axiom.Notifier]{
+		kind:      KindNotifier,
+		sensitive: []string{"properties"},
+		key:       func(n axiom.Notifier) string { return r.withoutLabel(n.Name) },
+		id:        func(n axiom.Notifier) string { return n.ID },
+		owned:     func(n axiom.Notifier) bool { return r.hasLabel(n.Name) },
+		label: func(n axiom.Notifier) axiom.Notifier {
+			n.ID = ""
+			n.Name = r.withLabel(n.Name)
+			return n
+		},
+		create: func(ctx context.Context, n axiom.Notifier) error {
+			created, err := r.notifiers.Create(ctx, n)
+			if err != nil {
+				return err
+			}
+			notifierIDs[r.withoutLabel(n.Name)] = created.ID
+			return nil
+		},
+		update: func(ctx context.Context, id string, n axiom.Notifier) error {
+			_, err := r.notifiers.Update(ctx, id, n)
+			return err
+		},
+		delete: func(ctx context.Context, id string) error {
+			return r.notifiers.Delete(ctx, id)
+		},
+	}
+}
+
+func (r *Reconciler) virtualFieldSpec() spec[axiom.VirtualFieldWithID] {
+	return spec[axiom.VirtualFieldWithID]{
+		kind:  KindVirtualField,
+		key:   func(vf axiom.VirtualFieldWithID) string { return vf.Dataset + "/" + vf.Name },
+		id:    func(vf axiom.VirtualFieldWithID) string { return vf.ID },
+		owned: func(vf axiom.VirtualFieldWithID) bool { return r.hasLabel(vf.Description) },
+		label: func(vf axiom.VirtualFieldWithID) axiom.VirtualFieldWithID {
+			vf.ID = ""
+			vf.Description = r.withLabel(vf.Description)
+			return vf
+		},
+		create: func(ctx context.Context, vf axiom.VirtualFieldWithID) error {
+			_, err := r.vfields.Create(ctx, vf.VirtualField)
+			return err
+		},
+		update: func(ctx context.Context, id string, vf axiom.VirtualFieldWithID) error {
+			_, err := r.vfields.Update(ctx, id, vf.VirtualField)
+			return err
+		},
+		delete: func(ctx context.Context, id string) error {
+			return r.vfields.Delete(ctx, id)
+		},
+	}
+}
+
+// withLabel appends the ownership label to the given text, unless it already
+// carries it.
+func (r *Reconciler) withLabel(s string) string {
+	if s = r.withoutLabel(s); s == "" {
+		return r.label
+	}
+	return s + " " + r.label
+}
+
+func (r *Reconciler) withoutLabel(s string) string {
+	return strings.TrimSpace(strings.TrimSuffix(s, r.label))
+}
+
+func (r *Reconciler) hasLabel(s string) bool {
+	return strings.HasSuffix(s, r.label)
+}
+
+func deref[T any](items []*T) []T {
+	res := make([]T, 0, len(items))
+	for _, item := range items {
+		if item != nil {
+			res = append(res, *item)
+		}
+	}
+	return res
+}
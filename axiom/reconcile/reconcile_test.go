@@ -0,0 +1,210 @@
+package reconcile_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/axiomtest"
+	"github.com/axiomhq/axiom-go/axiom/reconcile"
+)
+
+func setup(t *testing.T) (*axiom.Client, *reconcile.Reconciler) {
+	t.Helper()
+
+	srv := axiomtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := srv.Client()
+	require.NoError(t, err)
+
+	r, err := reconcile.New("test", reconcile.SetClient(client, "logs", "traces"))
+	require.NoError(t, err)
+
+	return client, r
+}
+
+func desiredState() *reconcile.State {
+	return &reconcile.State{
+		Monitors: []reconcile.Monitor{{
+			Monitor: axiom.Monitor{
+				Name:      "Errors",
+				APLQuery:  "['logs'] | where level == 'error' | count",
+				Operator:  axiom.Above,
+				Threshold: 10,
+				Interval:  5 * time.Minute,
+				Range:     5 * time.Minute,
+			},
+			Notifiers: []string{"On-call"},
+		}},
+		Notifiers: []axiom.Notifier{{
+			Name: "On-call",
+			Properties: axiom.NotifierProperties{
+				Slack: &axiom.SlackConfig{SlackURL: "https://hooks.slack.com/services/secret"},
+			},
+		}},
+		VirtualFields: []axiom.VirtualField{{
+			Dataset:    "logs",
+			Name:       "status",
+			Expression: "toint(status_code)",
+		}},
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := reconcile.New("")
+	assert.EqualError(t, err, "owner must not be empty")
+
+	_, err = reconcile.New("[x]")
+	assert.EqualError(t, err, `owner "[x]" must not contain brackets`)
+
+	_, err = reconcile.New("test")
+	assert.EqualError(t, err, "no services to reconcile")
+}
+
+func TestReconciler(t *testing.T) {
+	client, r := setup(t)
+
+	// Resources managed by hand are left alone.
+	manual, err := client.Monitors.Create(t.Context(), axiom.MonitorCreateRequest{Monitor: axiom.Monitor{
+		Name:     "Manual",
+		APLQuery: "['logs'] | count",
+	}})
+	require.NoError(t, err)
+
+	// Managed resources that are no longer desired are deleted, even from
+	// datasets without desired virtual fields.
+	_, err = client.VirtualFields.Create(t.Context(), axiom.VirtualField{
+		Dataset:     "traces",
+		Name:        "old",
+		Expression:  "1",
+		Description: "[managed-by:test]",
+	})
+	require.NoError(t, err)
+
+	plan, err := r.Plan(t.Context(), desiredState())
+	require.NoError(t, err)
+	assert.Equal(t, `+ create notifier "On-call"
++ create monitor "Errors"
++ create virtual field "logs/status"
+- delete virtual field "traces/old"
+
+Plan: 3 to create, 0 to update, 1 to delete.
+`, plan.String())
+
+	// Planning has no side effects.
+	vfields, err := client.VirtualFields.List(t.Context(), "logs")
+	require.NoError(t, err)
+	assert.Empty(t, vfields)
+
+	require.NoError(t, r.Apply(t.Context(), plan))
+
+	notifiers, err := client.Notifiers.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, notifiers, 1)
+	assert.Equal(t, "On-call [managed-by:test]", notifiers[0].Name)
+
+	monitors, err := client.Monitors.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, monitors, 2)
+	assert.Equal(t, manual.ID, monitors[0].ID)
+	assert.Equal(t, "[managed-by:test]", monitors[1].Description)
+
+	// Notifiers referenced by name are resolved once they are created.
+	assert.Equal(t, []string{notifiers[0].ID}, monitors[1].NotifierIDs)
+
+	vfields, err = client.VirtualFields.List(t.Context(), "traces")
+	require.NoError(t, err)
+	assert.Empty(t, vfields)
+
+	// Reconciling is idempotent.
+	plan, err = r.Reconcile(t.Context(), desiredState())
+	require.NoError(t, err)
+	assert.True(t, plan.Empty())
+	assert.Equal(t, "No changes.\n", plan.String())
+
+	// Changes are detected field by field, hiding secrets.
+	state := desiredState()
+	state.Monitors[0].Threshold = 20
+	state.Monitors[0].Description = "Too many errors"
+	state.Notifiers[0].Properties.Slack.SlackURL = "https://hooks.slack.com/services/other-secret"
+	state.VirtualFields = nil
+
+	plan, err = r.Reconcile(t.Context(), state)
+	require.NoError(t, err)
+	assert.Equal(t, `~ update notifier "On-call"
+    properties: (sensitive value changed)
+~ update monitor "Errors"
+    description: "[managed-by:test]" -> "Too many errors [managed-by:test]"
+    threshold: 10 -> 20
+- delete virtual field "logs/status"
+
+Plan: 0 to create, 2 to update, 1 to delete.
+`, plan.String())
+
+	monitor, err := client.Monitors.Get(t.Context(), monitors[1].ID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 20, monitor.Threshold)
+
+	// Removing everything from the desired state deletes all managed
+	// resources, monitors before the notifiers they might use.
+	plan, err = r.Reconcile(t.Context(), new(reconcile.State))
+	require.NoError(t, err)
+	assert.Equal(t, `- delete monitor "Errors"
+- delete notifier "On-call"
+
+Plan: 0 to create, 0 to update, 2 to delete.
+`, plan.String())
+
+	monitors, err = client.Monitors.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, monitors, 1)
+	assert.Equal(t, manual.ID, monitors[0].ID)
+}
+
+func TestReconciler_Plan_Unmanaged(t *testing.T) {
+	client, r := setup(t)
+
+	_, err := client.Monitors.Create(t.Context(), axiom.MonitorCreateRequest{Monitor: axiom.Monitor{
+		Name:     "Errors",
+		APLQuery: "['logs'] | count",
+	}})
+	require.NoError(t, err)
+
+	_, err = r.Plan(t.Context(), desiredState())
+	assert.ErrorIs(t, err, reconcile.ErrUnmanaged)
+	assert.EqualError(t, err, `monitor "Errors": resource exists but is not managed`)
+}
+
+func TestReconciler_Plan_Duplicate(t *testing.T) {
+	_, r := setup(t)
+
+	state := desiredState()
+	state.VirtualFields = append(state.VirtualFields, state.VirtualFields[0])
+
+	_, err := r.Plan(t.Context(), state)
+	assert.EqualError(t, err, `duplicate virtual field "logs/status"`)
+}
+
+func TestReconciler_Plan_UnknownNotifier(t *testing.T) {
+	_, r := setup(t)
+
+	state := desiredState()
+	state.Monitors[0].Notifiers = []string{"Pager"}
+
+	_, err := r.Plan(t.Context(), state)
+	assert.EqualError(t, err, `monitor "Errors": unknown notifier "Pager"`)
+}
+
+func TestReconciler_Plan_MissingService(t *testing.T) {
+	client, _ := setup(t)
+
+	r, err := reconcile.New("test", reconcile.SetVirtualFields(client.VirtualFields))
+	require.NoError(t, err)
+
+	_, err = r.Plan(t.Context(), desiredState())
+	assert.EqualError(t, err, "notifiers desired, but no notifiers service set")
+}
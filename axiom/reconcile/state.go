@@ -0,0 +1,156 @@
+package reconcile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+// State is the desired state of the resources managed by a [Reconciler].
+// Resources are identified by their name, virtual fields by their dataset and
+// name. Fields set by the server, like IDs and timestamps, are ignored.
+//
+// Monitors reference existing notifiers by ID or the notifiers of the state by
+// name, so a monitor can be declared together with the notifier it alerts.
+type State struct {
+	// Monitors is the desired set of monitors.
+	Monitors []Monitor `json:"monitors,omitempty"`
+	// Notifiers is the desired set of notifiers.
+	Notifiers []axiom.Notifier `json:"notifiers,omitempty"`
+	// VirtualFields is the desired set of virtual fields.
+	VirtualFields []axiom.VirtualField `json:"virtualFields,omitempty"`
+}
+
+// Monitor is a desired monitor. In addition to the notifiers referenced by ID,
+// it is attached to the notifiers of the desired state referenced by name.
+// Their IDs are only known once they are created, so the names are resolved
+// when the plan is applied.
+type Monitor struct {
+	axiom.Monitor
+
+	// Notifiers are the names of the desired notifiers the monitor is
+	// attached to.
+	Notifiers []string
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to add the names of
+// the notifiers to the JSON representation of the monitor as "notifiers".
+func (m Monitor) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(m.Monitor)
+	if err != nil || len(m.Notifiers) == 0 {
+		return b, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	if fields["notifiers"], err = json.Marshal(m.Notifiers); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to read the
+// names of the notifiers from "notifiers", next to the fields of the monitor.
+func (m *Monitor) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &m.Monitor); err != nil {
+		return err
+	}
+
+	var refs struct {
+		Notifiers []string `json:"notifiers"`
+	}
+	if err := json.Unmarshal(b, &refs); err != nil {
+		return err
+	}
+	m.Notifiers = refs.Notifiers
+
+	return nil
+}
+
+// Merge appends the resources of the other state to the state.
+func (s *State) Merge(other *State) {
+	s.Monitors = append(s.Monitors, other.Monitors...)
+	s.Notifiers = append(s.Notifiers, other.Notifiers...)
+	s.VirtualFields = append(s.VirtualFields, other.VirtualFields...)
+}
+
+// Load reads a [State] from YAML or JSON. Resources are described using the
+// same fields as in the JSON representation of the Axiom API, e.g.:
+//
+//	monitors:
+//	  - name: Errors
+//	    aplQuery: "['logs'] | where level == 'error' | summarize count() by bin_auto(_time)"
+//	    operator: Above
+//	    threshold: 10
+//	    intervalMinutes: 5
+//	    rangeMinutes: 5
+//	    notifiers: [On-call]
+//	notifiers:
+//	  - name: On-call
+//	    properties:
+//	      email:
+//	        emails: [oncall@example.com]
+//	virtualFields:
+//	  - dataset: logs
+//	    name: status
+//	    expression: "toint(status_code)"
+func Load(r io.Reader) (*State, error) {
+	dec := yaml.NewDecoder(r)
+
+	var v any
+	if err := dec.Decode(&v); errors.Is(err, io.EOF) {
+		return new(State), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("parse state: %w", err)
+	}
+
+	// The resources only know how to unmarshal from JSON, so YAML is converted
+	// to JSON first.
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("convert state: %w", err)
+	}
+
+	state := new(State)
+	if err = json.NewDecoder(bytes.NewReader(b)).Decode(state); err != nil {
+		return nil, fmt.Errorf("decode state: %w", err)
+	}
+	return state, nil
+}
+
+// LoadFiles reads and merges the states from the YAML or JSON files at the
+// given paths.
+func LoadFiles(paths ...string) (*State, error) {
+	state := new(State)
+	for _, path := range paths {
+		if err := loadFile(state, path); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+func loadFile(state *State, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s, err := Load(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	state.Merge(s)
+
+	return nil
+}
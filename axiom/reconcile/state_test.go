@@ -0,0 +1,83 @@
+package reconcile_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/reconcile"
+)
+
+func TestLoad(t *testing.T) {
+	state, err := reconcile.Load(strings.NewReader(`
+monitors:
+  - name: Errors
+    aplQuery: "['logs'] | where level == 'error' | count"
+    operator: Above
+    threshold: 10
+    intervalMinutes: 5
+    rangeMinutes: 10
+    notifiers: [On-call]
+notifiers:
+  - name: On-call
+    properties:
+      email:
+        emails: [oncall@example.com]
+virtualFields:
+  - dataset: logs
+    name: status
+    expression: toint(status_code)
+`))
+	require.NoError(t, err)
+
+	require.Len(t, state.Monitors, 1)
+	assert.Equal(t, "Errors", state.Monitors[0].Name)
+	assert.Equal(t, axiom.Above, state.Monitors[0].Operator)
+	assert.EqualValues(t, 10, state.Monitors[0].Threshold)
+	assert.Equal(t, 5*time.Minute, state.Monitors[0].Interval)
+	assert.Equal(t, 10*time.Minute, state.Monitors[0].Range)
+	assert.Equal(t, []string{"On-call"}, state.Monitors[0].Notifiers)
+
+	require.Len(t, state.Notifiers, 1)
+	require.NotNil(t, state.Notifiers[0].Properties.Email)
+	assert.Equal(t, []string{"oncall@example.com"}, state.Notifiers[0].Properties.Email.Emails)
+
+	assert.Equal(t, []axiom.VirtualField{{
+		Dataset:    "logs",
+		Name:       "status",
+		Expression: "toint(status_code)",
+	}}, state.VirtualFields)
+
+	state, err = reconcile.Load(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Equal(t, new(reconcile.State), state)
+
+	_, err = reconcile.Load(strings.NewReader(`monitors: [{operator: Sideways}]`))
+	assert.EqualError(t, err, `decode state: unknown operator "Sideways"`)
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := filepath.Join(dir, "monitors.yaml")
+	err := os.WriteFile(yamlPath, []byte("monitors:\n  - name: Errors\n"), 0o600)
+	require.NoError(t, err)
+
+	jsonPath := filepath.Join(dir, "vfields.json")
+	err = os.WriteFile(jsonPath, []byte(`{"virtualFields":[{"dataset":"logs","name":"status"}]}`), 0o600)
+	require.NoError(t, err)
+
+	state, err := reconcile.LoadFiles(yamlPath, jsonPath)
+	require.NoError(t, err)
+	assert.Len(t, state.Monitors, 1)
+	assert.Len(t, state.VirtualFields, 1)
+
+	_, err = reconcile.LoadFiles(filepath.Join(dir, "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,97 @@
+package redact
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/axiomhq/axiom-go/internal/config"
+)
+
+// A Detector finds secrets in string values. Every match is replaced by the
+// mask of the [Redactor].
+type Detector struct {
+	// Name of the detector.
+	Name string
+
+	pattern  *regexp.Regexp
+	validate func(match string) bool
+}
+
+// NewDetector returns a detector that replaces all matches of the given
+// pattern.
+func NewDetector(name string, pattern *regexp.Regexp) Detector {
+	return Detector{Name: name, pattern: pattern}
+}
+
+// Built-in detectors for common secrets and personally identifiable
+// information.
+var (
+	// AxiomToken detects Axiom API ("xaat-") and personal ("xapt-") tokens.
+	AxiomToken = Detector{
+		Name:     "axiom_token",
+		pattern:  regexp.MustCompile(`xa[ap]t-[0-9A-Za-z-]+`),
+		validate: config.IsValidToken,
+	}
+	// Email detects email addresses.
+	Email = Detector{
+		Name:    "email",
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	}
+	// CreditCard detects credit card numbers, optionally separated by spaces
+	// or dashes. Only numbers with a valid Luhn checksum are matched.
+	CreditCard = Detector{
+		Name:     "credit_card",
+		pattern:  regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		validate: luhn,
+	}
+	// BearerToken detects bearer tokens as used in "Authorization" headers.
+	BearerToken = Detector{
+		Name:    "bearer_token",
+		pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]+=*`),
+	}
+	// JWT detects JSON Web Tokens.
+	JWT = Detector{
+		Name:    "jwt",
+		pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	}
+	// AWSAccessKeyID detects AWS access key IDs.
+	AWSAccessKeyID = Detector{
+		Name:    "aws_access_key_id",
+		pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	}
+)
+
+// DefaultDetectors returns all built-in detectors.
+func DefaultDetectors() []Detector {
+	return []Detector{AxiomToken, Email, CreditCard, BearerToken, JWT, AWSAccessKeyID}
+}
+
+// replace replaces all matches of the detector in s with mask.
+func (d Detector) replace(s, mask string) string {
+	if d.validate == nil {
+		return d.pattern.ReplaceAllLiteralString(s, mask)
+	}
+	return d.pattern.ReplaceAllStringFunc(s, func(match string) string {
+		if d.validate(match) {
+			return mask
+		}
+		return match
+	})
+}
+
+// luhn reports whether the digits in s have a valid Luhn checksum.
+func luhn(s string) bool {
+	s = strings.NewReplacer(" ", "", "-", "").Replace(s)
+
+	var sum int
+	for i := range len(s) {
+		d := int(s[len(s)-1-i] - '0')
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
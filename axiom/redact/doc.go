@@ -0,0 +1,20 @@
+// Package redact provides a [Redactor] that removes personally identifiable
+// information and secrets from events before they are ingested into Axiom.
+//
+// Usage:
+//
+//	import "github.com/axiomhq/axiom-go/axiom/redact"
+//
+// A redactor is configured with rules by field path and detectors for secrets
+// in string values:
+//
+//	redactor, err := redact.New(
+//	    redact.DropFields("password"),
+//	    redact.HashFields(salt, "user.email"),
+//	    redact.MaskFields("request.headers.authorization"),
+//	    redact.DetectSecrets(redact.DefaultDetectors()...),
+//	)
+//
+// It is applied to all events ingested by a client using the
+// [github.com/axiomhq/axiom-go/axiom.SetRedactor] option.
+package redact
@@ -0,0 +1,275 @@
+package redact
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+var _ ingest.Processor = (*Redactor)(nil)
+
+// DefaultMask is the value that masked fields and detected secrets are replaced
+// with by default.
+const DefaultMask = "[REDACTED]"
+
+// wildcard matches any key in a field path.
+const wildcard = "*"
+
+// action is the action applied to the value of a field matched by a rule. When
+// a field is matched by multiple rules, the greatest action is applied.
+type action uint8
+
+const (
+	actionMask action = iota + 1
+	actionHash
+	actionDrop
+)
+
+// An Option configures a [Redactor].
+type Option func(*Redactor) error
+
+// DropFields removes the fields at the given paths from the event. A path
+// consists of the keys of nested objects, separated by dots (e.g.
+// "user.email"). A "*" matches any key. Paths are applied to all elements of
+// arrays they lead through.
+func DropFields(paths ...string) Option {
+	return func(r *Redactor) error {
+		return r.addRule(actionDrop, nil, paths)
+	}
+}
+
+// HashFields replaces the values of the fields at the given paths with their
+// hex encoded HMAC-SHA256, keyed by the given salt. This keeps values
+// comparable without revealing them. See [DropFields] for the path syntax.
+func HashFields(salt []byte, paths ...string) Option {
+	return func(r *Redactor) error {
+		if len(salt) == 0 {
+			return errors.New("salt cannot be empty")
+		}
+		return r.addRule(actionHash, salt, paths)
+	}
+}
+
+// MaskFields replaces the values of the fields at the given paths with the
+// mask. See [DropFields] for the path syntax.
+func MaskFields(paths ...string) Option {
+	return func(r *Redactor) error {
+		return r.addRule(actionMask, nil, paths)
+	}
+}
+
+// ScrubPattern replaces all matches of the given pattern in string values with
+// the mask.
+func ScrubPattern(pattern *regexp.Regexp) Option {
+	return func(r *Redactor) error {
+		if pattern == nil {
+			return errors.New("pattern cannot be nil")
+		}
+		r.detectors = append(r.detectors, NewDetector("pattern", pattern))
+		return nil
+	}
+}
+
+// DetectSecrets replaces all secrets found by the given detectors in string
+// values with the mask. Use [DefaultDetectors] to enable all built-in
+// detectors.
+func DetectSecrets(detectors ...Detector) Option {
+	return func(r *Redactor) error {
+		for _, d := range detectors {
+			if d.pattern == nil {
+				return fmt.Errorf("detector %q has no pattern", d.Name)
+			}
+		}
+		r.detectors = append(r.detectors, detectors...)
+		return nil
+	}
+}
+
+// SetMask specifies the value that masked fields and detected secrets are
+// replaced with. Defaults to [DefaultMask].
+func SetMask(mask string) Option {
+	return func(r *Redactor) error {
+		r.mask = mask
+		return nil
+	}
+}
+
+// Redactor removes personally identifiable information and secrets from
+// events. It is safe for concurrent use.
+type Redactor struct {
+	root      *node
+	detectors []Detector
+	mask      string
+}
+
+// node is a node in the tree of field paths. A node with an action terminates
+// a rule.
+type node struct {
+	children map[string]*node
+	action   action
+	salt     []byte
+}
+
+// New creates a new redactor. Without any options, it leaves events unchanged.
+func New(options ...Option) (*Redactor, error) {
+	r := &Redactor{
+		root: &node{},
+		mask: DefaultMask,
+	}
+
+	// Apply supplied options.
+	for _, option := range options {
+		if option == nil {
+			continue
+		} else if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (r *Redactor) addRule(act action, salt []byte, paths []string) error {
+	for _, path := range paths {
+		keys := strings.Split(path, ".")
+		if slices.Contains(keys, "") {
+			return fmt.Errorf("invalid field path %q", path)
+		}
+
+		n := r.root
+		for _, key := range keys {
+			child, ok := n.children[key]
+			if !ok {
+				child = &node{}
+				if n.children == nil {
+					n.children = make(map[string]*node)
+				}
+				n.children[key] = child
+			}
+			n = child
+		}
+
+		if act > n.action {
+			n.action, n.salt = act, salt
+		}
+	}
+	return nil
+}
+
+// Redact returns a redacted copy of the event. The event itself is not
+// modified.
+func (r *Redactor) Redact(event map[string]any) map[string]any {
+	if event == nil {
+		return nil
+	}
+	return r.redactMap(event, []*node{r.root})
+}
+
+// Process implements [ingest.Processor]. It is an alias for [Redactor.Redact].
+func (r *Redactor) Process(event map[string]any) map[string]any {
+	return r.Redact(event)
+}
+
+func (r *Redactor) redactMap(m map[string]any, nodes []*node) map[string]any {
+	res := make(map[string]any, len(m))
+	for k, v := range m {
+		var (
+			matches []*node
+			rule    *node
+		)
+		for _, n := range nodes {
+			for _, key := range [...]string{k, wildcard} {
+				if child := n.children[key]; child != nil {
+					matches = append(matches, child)
+					if rule == nil || child.action > rule.action {
+						rule = child
+					}
+				}
+			}
+		}
+
+		switch {
+		case rule == nil || rule.action == 0:
+			res[k] = r.redactValue(v, matches)
+		case rule.action == actionDrop:
+		case rule.action == actionHash:
+			res[k] = hashValue(rule.salt, v)
+		case rule.action == actionMask:
+			res[k] = r.mask
+		}
+	}
+	return res
+}
+
+func (r *Redactor) redactValue(v any, nodes []*node) any {
+	switch v := v.(type) {
+	case nil, bool, json.Number:
+		return v
+	case string:
+		return r.scrub(v)
+	case map[string]any:
+		return r.redactMap(v, nodes)
+	case []any:
+		res := make([]any, len(v))
+		for i, e := range v {
+			res[i] = r.redactValue(e, nodes)
+		}
+		return res
+	case []string:
+		res := make([]string, len(v))
+		for i, e := range v {
+			res[i] = r.scrub(e)
+		}
+		return res
+	}
+
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return v
+	}
+
+	// Any other value (e.g. a struct or a typed map) is redacted in the form it
+	// is going to be ingested in.
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var generic any
+	if err = dec.Decode(&generic); err != nil {
+		return v
+	}
+	return r.redactValue(generic, nodes)
+}
+
+func (r *Redactor) scrub(s string) string {
+	for _, d := range r.detectors {
+		s = d.replace(s, r.mask)
+	}
+	return s
+}
+
+func hashValue(salt []byte, v any) string {
+	h := hmac.New(sha256.New, salt)
+	if s, ok := v.(string); ok {
+		_, _ = h.Write([]byte(s))
+	} else {
+		b, _ := json.Marshal(v)
+		_, _ = h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
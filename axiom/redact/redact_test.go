@@ -0,0 +1,132 @@
+package redact
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	salt := []byte("salt")
+
+	r, err := New(
+		DropFields("password", "users.secret"),
+		HashFields(salt, "user.email"),
+		MaskFields("request.headers.authorization"),
+		DetectSecrets(AxiomToken, Email),
+		ScrubPattern(regexp.MustCompile(`ssn=\d+`)),
+	)
+	require.NoError(t, err)
+
+	event := map[string]any{
+		"password": "hunter2",
+		"request": map[string]any{
+			"headers": map[string]any{
+				"authorization": "Basic Zm9vOmJhcg==",
+				"accept":        "application/json",
+			},
+		},
+		"users": []any{
+			map[string]any{"name": "alice", "secret": 1},
+			map[string]any{"name": "bob", "secret": 2},
+		},
+		"msg":   "token xaat-8b1a4b1e-5a3c-4b0f-a0b6-6c4f3e2d1c0b used by john@example.com with ssn=123",
+		"tags":  []string{"jane@example.com", "public"},
+		"count": 42,
+	}
+
+	exp := map[string]any{
+		"request": map[string]any{
+			"headers": map[string]any{
+				"authorization": DefaultMask,
+				"accept":        "application/json",
+			},
+		},
+		"users": []any{
+			map[string]any{"name": "alice"},
+			map[string]any{"name": "bob"},
+		},
+		"msg":   "token [REDACTED] used by [REDACTED] with [REDACTED]",
+		"tags":  []string{"[REDACTED]", "public"},
+		"count": 42,
+	}
+	assert.Equal(t, exp, r.Redact(event))
+
+	// The original event is left untouched.
+	assert.Equal(t, "hunter2", event["password"])
+	assert.Len(t, event["users"].([]any)[0], 2)
+}
+
+func TestRedactor_Redact_Hash(t *testing.T) {
+	r, err := New(HashFields([]byte("salt"), "user.email", "user.id"))
+	require.NoError(t, err)
+
+	act := r.Redact(map[string]any{
+		"user": map[string]any{"email": "john@example.com", "id": 1},
+	})
+	other := r.Redact(map[string]any{
+		"user": map[string]any{"email": "john@example.com", "id": 2},
+	})
+
+	user := act["user"].(map[string]any)
+	assert.Len(t, user["email"], 64)
+	assert.NotEqual(t, "john@example.com", user["email"])
+	// Hashes are stable, so values stay comparable.
+	assert.Equal(t, user["email"], other["user"].(map[string]any)["email"])
+	assert.NotEqual(t, user["id"], other["user"].(map[string]any)["id"])
+}
+
+func TestRedactor_Redact_Precedence(t *testing.T) {
+	r, err := New(
+		MaskFields("user.*"),
+		DropFields("user.password"),
+	)
+	require.NoError(t, err)
+
+	act := r.Redact(map[string]any{
+		"user": map[string]any{"name": "john", "password": "hunter2"},
+	})
+	assert.Equal(t, map[string]any{
+		"user": map[string]any{"name": DefaultMask},
+	}, act)
+}
+
+func TestRedactor_Redact_Structs(t *testing.T) {
+	type user struct {
+		Email     string    `json:"email"`
+		Password  string    `json:"password"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	r, err := New(
+		DropFields("user.password"),
+		DetectSecrets(Email),
+		SetMask("***"),
+	)
+	require.NoError(t, err)
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	act := r.Redact(map[string]any{
+		"user": user{Email: "john@example.com", Password: "hunter2", CreatedAt: createdAt},
+	})
+	assert.Equal(t, map[string]any{
+		"user": map[string]any{
+			"email":      "***",
+			"created_at": "2024-01-01T00:00:00Z",
+		},
+	}, act)
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	_, err := New(DropFields("user..email"))
+	require.EqualError(t, err, `invalid field path "user..email"`)
+
+	_, err = New(HashFields(nil, "email"))
+	require.EqualError(t, err, "salt cannot be empty")
+
+	_, err = New(DetectSecrets(Detector{Name: "empty"}))
+	require.EqualError(t, err, `detector "empty" has no pattern`)
+}
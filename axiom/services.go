@@ -0,0 +1,206 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/axiom/query"
+)
+
+// The interfaces in this file describe the services of the [Client]. Code that
+// only needs part of the Axiom API can depend on them instead of the concrete
+// services, making it possible to substitute fakes in tests. Mock
+// implementations are available in the [axiommock] package.
+//
+// [axiommock]: https://pkg.go.dev/github.com/axiomhq/axiom-go/axiom/axiommock
+
+var (
+	_ Ingester      = (*Client)(nil)
+	_ Querier       = (*Client)(nil)
+	_ Datasets      = (*DatasetsService)(nil)
+	_ Dashboards    = (*DashboardsService)(nil)
+	_ Organizations = (*OrganizationsService)(nil)
+	_ Users         = (*UsersService)(nil)
+	_ Monitors      = (*MonitorsService)(nil)
+	_ Notifiers     = (*NotifiersService)(nil)
+	_ Annotations   = (*AnnotationsService)(nil)
+	_ Tokens        = (*TokensService)(nil)
+	_ VirtualFields = (*VirtualFieldsService)(nil)
+)
+
+// Ingester ingests events into a dataset. It is implemented by [Client] and
+// [DatasetsService].
+type Ingester interface {
+	// Ingest data into the dataset identified by its id.
+	Ingest(ctx context.Context, id string, r io.Reader, typ ContentType, enc ContentEncoding, options ...ingest.Option) (*ingest.Status, error)
+	// IngestEvents ingests events into the dataset identified by its id.
+	IngestEvents(ctx context.Context, id string, events []Event, options ...ingest.Option) (*ingest.Status, error)
+	// IngestChannel ingests events from a channel into the dataset identified
+	// by its id.
+	IngestChannel(ctx context.Context, id string, events <-chan Event, options ...ingest.Option) (*ingest.Status, error)
+}
+
+// Querier executes APL queries. It is implemented by [Client] and
+// [DatasetsService].
+type Querier interface {
+	// Query executes the given query specified using the Axiom Processing
+	// Language (APL).
+	Query(ctx context.Context, apl string, options ...query.Option) (*query.Result, error)
+}
+
+// Datasets is the interface implemented by [DatasetsService].
+type Datasets interface {
+	Ingester
+	Querier
+
+	// List all available datasets.
+	List(ctx context.Context) ([]*Dataset, error)
+	// Get a dataset by id.
+	Get(ctx context.Context, id string) (*Dataset, error)
+	// Create a dataset with the given properties.
+	Create(ctx context.Context, req DatasetCreateRequest) (*Dataset, error)
+	// Update the dataset identified by the given id with the given properties.
+	Update(ctx context.Context, id string, req DatasetUpdateRequest) (*Dataset, error)
+	// Delete the dataset identified by the given id.
+	Delete(ctx context.Context, id string) error
+	// Trim the dataset identified by its id to a given length.
+	Trim(ctx context.Context, id string, maxDuration time.Duration) error
+	// ListMapFields lists the map fields of the dataset identified by its id.
+	ListMapFields(ctx context.Context, id string) (MapFields, error)
+	// CreateMapField creates a map field on the dataset identified by its id.
+	CreateMapField(ctx context.Context, id string, name string) (string, error)
+	// UpdateMapFields replaces the map fields of the dataset identified by
+	// its id.
+	UpdateMapFields(ctx context.Context, id string, mapFields MapFields) (MapFields, error)
+	// DeleteMapField deletes a map field from the dataset identified by its
+	// id.
+	DeleteMapField(ctx context.Context, id string, name string) error
+	// GetTrace returns the spans of the trace identified by its id.
+	GetTrace(ctx context.Context, id, traceID string, options ...query.Option) (*Trace, error)
+}
+
+// Dashboards is the interface implemented by [DashboardsService].
+type Dashboards interface {
+	// List dashboards.
+	List(ctx context.Context, opts *DashboardsListOptions) ([]*DashboardWithID, error)
+	// Get a dashboard by uid.
+	Get(ctx context.Context, uid string) (*DashboardWithID, error)
+	// Create a dashboard with the given properties.
+	Create(ctx context.Context, req DashboardCreateRequest) (*DashboardWithID, error)
+	// Update the dashboard identified by the given uid with the given
+	// properties.
+	Update(ctx context.Context, uid string, req DashboardUpdateRequest) (*DashboardWithID, error)
+	// ListRaw lists dashboards and returns the raw response payload.
+	ListRaw(ctx context.Context, opts *DashboardsListOptions) (json.RawMessage, error)
+	// GetRaw gets a dashboard by uid and returns the raw response payload.
+	GetRaw(ctx context.Context, uid string) (json.RawMessage, error)
+	// CreateRaw creates a dashboard from a raw payload.
+	CreateRaw(ctx context.Context, payload json.RawMessage) (json.RawMessage, error)
+	// UpdateRaw updates the dashboard identified by its uid from a raw
+	// payload.
+	UpdateRaw(ctx context.Context, uid string, payload json.RawMessage) (json.RawMessage, error)
+	// Delete the dashboard identified by its uid.
+	Delete(ctx context.Context, uid string) error
+}
+
+// Organizations is the interface implemented by [OrganizationsService].
+type Organizations interface {
+	// List all available organizations.
+	List(ctx context.Context) ([]*Organization, error)
+	// Get an organization by id.
+	Get(ctx context.Context, id string) (*Organization, error)
+}
+
+// Users is the interface implemented by [UsersService].
+type Users interface {
+	// Current retrieves the authenticated user.
+	Current(ctx context.Context) (*User, error)
+	// List all users.
+	List(ctx context.Context) ([]*User, error)
+	// Get a user by id.
+	Get(ctx context.Context, id string) (*User, error)
+	// Create a user with the given properties.
+	Create(ctx context.Context, req CreateUserRequest) (*User, error)
+	// Update the user identified by the given id with the given properties.
+	Update(ctx context.Context, id string, req UpdateUserRequest) (*User, error)
+	// UpdateUsersRole updates the role of the user identified by the given id.
+	UpdateUsersRole(ctx context.Context, id string, req UpdateUserRoleRequest) (*User, error)
+	// Delete the user identified by the given id.
+	Delete(ctx context.Context, id string) error
+}
+
+// Monitors is the interface implemented by [MonitorsService].
+type Monitors interface {
+	// List all available monitors.
+	List(ctx context.Context) ([]*Monitor, error)
+	// Get a monitor by id.
+	Get(ctx context.Context, id string) (*Monitor, error)
+	// Create a monitor with the given properties.
+	Create(ctx context.Context, req MonitorCreateRequest) (*Monitor, error)
+	// Update the monitor identified by the given id with the given properties.
+	Update(ctx context.Context, id string, req MonitorUpdateRequest) (*Monitor, error)
+	// Delete the monitor identified by the given id.
+	Delete(ctx context.Context, id string) error
+}
+
+// Notifiers is the interface implemented by [NotifiersService].
+type Notifiers interface {
+	// List all available notifiers.
+	List(ctx context.Context) ([]*Notifier, error)
+	// Get a notifier by id.
+	Get(ctx context.Context, id string) (*Notifier, error)
+	// Create a notifier with the given properties.
+	Create(ctx context.Context, req Notifier) (*Notifier, error)
+	// Update the notifier identified by the given id with the given
+	// properties.
+	Update(ctx context.Context, id string, req Notifier) (*Notifier, error)
+	// Delete the notifier identified by the given id.
+	Delete(ctx context.Context, id string) error
+}
+
+// Annotations is the interface implemented by [AnnotationsService].
+type Annotations interface {
+	// Create an annotation with the given properties.
+	Create(ctx context.Context, annotation *AnnotationCreateRequest) (*Annotation, error)
+	// List annotations matching the given filter.
+	List(ctx context.Context, filter *AnnotationsFilter) ([]*Annotation, error)
+	// Get an annotation by id.
+	Get(ctx context.Context, id string) (*Annotation, error)
+	// Update the annotation identified by the given id with the given
+	// properties.
+	Update(ctx context.Context, id string, annotation *AnnotationUpdateRequest) (*Annotation, error)
+	// Delete the annotation identified by the given id.
+	Delete(ctx context.Context, id string) error
+}
+
+// Tokens is the interface implemented by [TokensService].
+type Tokens interface {
+	// List all available API tokens.
+	List(ctx context.Context) ([]*APIToken, error)
+	// Get an API token by id.
+	Get(ctx context.Context, id string) (*APIToken, error)
+	// Create an API token with the given properties.
+	Create(ctx context.Context, req CreateTokenRequest) (*CreateTokenResponse, error)
+	// Regenerate the API token identified by the given id.
+	Regenerate(ctx context.Context, id string, req RegenerateTokenRequest) (*CreateTokenResponse, error)
+	// Delete the API token identified by the given id.
+	Delete(ctx context.Context, id string) error
+}
+
+// VirtualFields is the interface implemented by [VirtualFieldsService].
+type VirtualFields interface {
+	// List all virtual fields of the given dataset.
+	List(ctx context.Context, dataset string) ([]*VirtualFieldWithID, error)
+	// Get a virtual field by id.
+	Get(ctx context.Context, id string) (*VirtualFieldWithID, error)
+	// Create a virtual field with the given properties.
+	Create(ctx context.Context, req VirtualField) (*VirtualFieldWithID, error)
+	// Update the virtual field identified by the given id with the given
+	// properties.
+	Update(ctx context.Context, id string, req VirtualField) (*VirtualFieldWithID, error)
+	// Delete the virtual field identified by the given id.
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,48 @@
+// Package tokens provides helpers to manage Axiom API tokens: a [Policy]
+// builder for token capabilities, which can also check if a token allows an
+// [Operation], and a [Rotator] which regenerates a token ahead of its expiry,
+// hands the new token to a [Sink] for storage and serves it to running
+// clients.
+//
+// Building a token with least privilege:
+//
+//	req := axiom.CreateTokenRequest{Name: "ingest-logs"}
+//	tokens.NewPolicy().
+//		Dataset("logs").Ingest(tokens.Create).
+//		Policy().Apply(&req)
+//
+//	token, err := adminClient.Tokens.Create(ctx, req)
+//
+// Failing fast, if an existing token lacks a capability:
+//
+//	token, err := adminClient.Tokens.Get(ctx, "token-id")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if err = tokens.Check(token, tokens.OpIngestEvents, "logs"); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// Rotating a token:
+//
+//	rotator, err := tokens.New(adminClient.Tokens, "token-id",
+//		func(ctx context.Context, token *axiom.CreateTokenResponse) error {
+//			return os.WriteFile("/etc/axiom/token", []byte(token.Token), 0o600)
+//		},
+//		tokens.SetToken(os.Getenv("AXIOM_TOKEN")),
+//		tokens.SetOverlap(time.Hour),
+//		tokens.SetAnnotations(adminClient.Annotations, "my-dataset"),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	go rotator.Run(ctx)
+//
+//	// The client always uses the latest token.
+//	client, err := axiom.NewClient(
+//		axiom.SetCredentialsProvider(rotator),
+//	)
+//
+// The client managing the tokens needs a token with the capability to update
+// API tokens, which should not be the token it rotates.
+package tokens
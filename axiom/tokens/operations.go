@@ -0,0 +1,208 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+// ErrMissingCapability is returned by [Policy.Check] if the policy doesn't
+// grant the capability required for an operation.
+var ErrMissingCapability = errors.New("missing capability")
+
+// Operation is an operation of the [axiom.Client], named after the method that
+// performs it.
+type Operation string
+
+// All [Operation]s that can be checked.
+const (
+	OpIngest        Operation = "Ingest"
+	OpIngestEvents  Operation = "IngestEvents"
+	OpIngestChannel Operation = "IngestChannel"
+	OpQuery         Operation = "Query"
+
+	OpDatasetsList            Operation = "Datasets.List"
+	OpDatasetsGet             Operation = "Datasets.Get"
+	OpDatasetsCreate          Operation = "Datasets.Create"
+	OpDatasetsUpdate          Operation = "Datasets.Update"
+	OpDatasetsDelete          Operation = "Datasets.Delete"
+	OpDatasetsTrim            Operation = "Datasets.Trim"
+	OpDatasetsListMapFields   Operation = "Datasets.ListMapFields"
+	OpDatasetsCreateMapField  Operation = "Datasets.CreateMapField"
+	OpDatasetsUpdateMapFields Operation = "Datasets.UpdateMapFields"
+	OpDatasetsDeleteMapField  Operation = "Datasets.DeleteMapField"
+	OpDatasetsGetTrace        Operation = "Datasets.GetTrace"
+
+	OpVirtualFieldsList   Operation = "VirtualFields.List"
+	OpVirtualFieldsGet    Operation = "VirtualFields.Get"
+	OpVirtualFieldsCreate Operation = "VirtualFields.Create"
+	OpVirtualFieldsUpdate Operation = "VirtualFields.Update"
+	OpVirtualFieldsDelete Operation = "VirtualFields.Delete"
+
+	OpAnnotationsList   Operation = "Annotations.List"
+	OpAnnotationsGet    Operation = "Annotations.Get"
+	OpAnnotationsCreate Operation = "Annotations.Create"
+	OpAnnotationsUpdate Operation = "Annotations.Update"
+	OpAnnotationsDelete Operation = "Annotations.Delete"
+
+	OpDashboardsList   Operation = "Dashboards.List"
+	OpDashboardsGet    Operation = "Dashboards.Get"
+	OpDashboardsCreate Operation = "Dashboards.Create"
+	OpDashboardsUpdate Operation = "Dashboards.Update"
+	OpDashboardsDelete Operation = "Dashboards.Delete"
+
+	OpMonitorsList   Operation = "Monitors.List"
+	OpMonitorsGet    Operation = "Monitors.Get"
+	OpMonitorsCreate Operation = "Monitors.Create"
+	OpMonitorsUpdate Operation = "Monitors.Update"
+	OpMonitorsDelete Operation = "Monitors.Delete"
+
+	OpNotifiersList   Operation = "Notifiers.List"
+	OpNotifiersGet    Operation = "Notifiers.Get"
+	OpNotifiersCreate Operation = "Notifiers.Create"
+	OpNotifiersUpdate Operation = "Notifiers.Update"
+	OpNotifiersDelete Operation = "Notifiers.Delete"
+
+	OpTokensList       Operation = "Tokens.List"
+	OpTokensGet        Operation = "Tokens.Get"
+	OpTokensCreate     Operation = "Tokens.Create"
+	OpTokensRegenerate Operation = "Tokens.Regenerate"
+	OpTokensDelete     Operation = "Tokens.Delete"
+
+	OpUsersList   Operation = "Users.List"
+	OpUsersGet    Operation = "Users.Get"
+	OpUsersCreate Operation = "Users.Create"
+	OpUsersUpdate Operation = "Users.Update"
+	OpUsersDelete Operation = "Users.Delete"
+)
+
+// requirement is the capability an operation requires. Exactly one of dataset
+// and org is set.
+type requirement struct {
+	dataset *capability[axiom.DatasetCapabilities]
+	org     *capability[axiom.OrganisationCapabilities]
+	action  axiom.Action
+}
+
+func datasetRequirement(name string, action axiom.Action) requirement {
+	for i, c := range datasetCapabilities {
+		if c.name == name {
+			return requirement{dataset: &datasetCapabilities[i], action: action}
+		}
+	}
+	panic("unknown dataset capability " + name)
+}
+
+func orgRequirement(name string, action axiom.Action) requirement {
+	for i, c := range organisationCapabilities {
+		if c.name == name {
+			return requirement{org: &organisationCapabilities[i], action: action}
+		}
+	}
+	panic("unknown organisation capability " + name)
+}
+
+var requirements = map[Operation]requirement{
+	OpIngest:        datasetRequirement("ingest", Create),
+	OpIngestEvents:  datasetRequirement("ingest", Create),
+	OpIngestChannel: datasetRequirement("ingest", Create),
+	OpQuery:         datasetRequirement("query", Read),
+
+	OpDatasetsList:            orgRequirement("datasets", Read),
+	OpDatasetsGet:             orgRequirement("datasets", Read),
+	OpDatasetsCreate:          orgRequirement("datasets", Create),
+	OpDatasetsUpdate:          orgRequirement("datasets", Update),
+	OpDatasetsDelete:          orgRequirement("datasets", Delete),
+	OpDatasetsTrim:            datasetRequirement("trim", Update),
+	OpDatasetsListMapFields:   orgRequirement("datasets", Read),
+	OpDatasetsCreateMapField:  orgRequirement("datasets", Update),
+	OpDatasetsUpdateMapFields: orgRequirement("datasets", Update),
+	OpDatasetsDeleteMapField:  orgRequirement("datasets", Update),
+	OpDatasetsGetTrace:        datasetRequirement("query", Read),
+
+	OpVirtualFieldsList:   datasetRequirement("virtualFields", Read),
+	OpVirtualFieldsGet:    datasetRequirement("virtualFields", Read),
+	OpVirtualFieldsCreate: datasetRequirement("virtualFields", Create),
+	OpVirtualFieldsUpdate: datasetRequirement("virtualFields", Update),
+	OpVirtualFieldsDelete: datasetRequirement("virtualFields", Delete),
+
+	OpAnnotationsList:   orgRequirement("annotations", Read),
+	OpAnnotationsGet:    orgRequirement("annotations", Read),
+	OpAnnotationsCreate: orgRequirement("annotations", Create),
+	OpAnnotationsUpdate: orgRequirement("annotations", Update),
+	OpAnnotationsDelete: orgRequirement("annotations", Delete),
+
+	OpDashboardsList:   orgRequirement("dashboards", Read),
+	OpDashboardsGet:    orgRequirement("dashboards", Read),
+	OpDashboardsCreate: orgRequirement("dashboards", Create),
+	OpDashboardsUpdate: orgRequirement("dashboards", Update),
+	OpDashboardsDelete: orgRequirement("dashboards", Delete),
+
+	OpMonitorsList:   orgRequirement("monitors", Read),
+	OpMonitorsGet:    orgRequirement("monitors", Read),
+	OpMonitorsCreate: orgRequirement("monitors", Create),
+	OpMonitorsUpdate: orgRequirement("monitors", Update),
+	OpMonitorsDelete: orgRequirement("monitors", Delete),
+
+	OpNotifiersList:   orgRequirement("notifiers", Read),
+	OpNotifiersGet:    orgRequirement("notifiers", Read),
+	OpNotifiersCreate: orgRequirement("notifiers", Create),
+	OpNotifiersUpdate: orgRequirement("notifiers", Update),
+	OpNotifiersDelete: orgRequirement("notifiers", Delete),
+
+	OpTokensList:       orgRequirement("apiTokens", Read),
+	OpTokensGet:        orgRequirement("apiTokens", Read),
+	OpTokensCreate:     orgRequirement("apiTokens", Create),
+	OpTokensRegenerate: orgRequirement("apiTokens", Update),
+	OpTokensDelete:     orgRequirement("apiTokens", Delete),
+
+	OpUsersList:   orgRequirement("users", Read),
+	OpUsersGet:    orgRequirement("users", Read),
+	OpUsersCreate: orgRequirement("users", Create),
+	OpUsersUpdate: orgRequirement("users", Update),
+	OpUsersDelete: orgRequirement("users", Delete),
+}
+
+// Check returns an error wrapping [ErrMissingCapability] if the token doesn't
+// allow the operation. See [Policy.Check].
+func Check(token *axiom.APIToken, op Operation, dataset string) error {
+	return PolicyFromToken(token).Check(op, dataset)
+}
+
+// Allows reports whether the policy allows the operation. See [Policy.Check].
+func (p *Policy) Allows(op Operation, dataset string) bool {
+	return p.Check(op, dataset) == nil
+}
+
+// Check returns an error wrapping [ErrMissingCapability] if the policy doesn't
+// grant the capability required for the operation. The dataset is only
+// considered for operations on the data of a dataset, like [OpIngestEvents] or
+// [OpQuery].
+//
+// The check is based on the capabilities of the token alone. It can't predict
+// every authorization decision of the Axiom API but catches tokens that are
+// obviously lacking a capability, before they are put to use.
+func (p *Policy) Check(op Operation, dataset string) error {
+	req, ok := requirements[op]
+	if !ok {
+		return fmt.Errorf("unknown operation %q", op)
+	}
+
+	if req.org != nil {
+		if !slices.Contains(*req.org.actions(&p.org), req.action) {
+			return fmt.Errorf("%w: %s requires %s on org %s", ErrMissingCapability, op, req.action, req.org.name)
+		}
+		return nil
+	}
+
+	if dataset == "" {
+		return fmt.Errorf("operation %s requires a dataset", op)
+	}
+	caps, ok := p.datasets[dataset]
+	if !ok || !slices.Contains(*req.dataset.actions(caps), req.action) {
+		return fmt.Errorf("%w: %s requires %s on dataset %q %s", ErrMissingCapability, op, req.action, dataset, req.dataset.name)
+	}
+	return nil
+}
@@ -0,0 +1,409 @@
+package tokens
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+// Shorthands for the [axiom.Action]s, so policies read naturally.
+const (
+	Create = axiom.ActionCreate
+	Read   = axiom.ActionRead
+	Update = axiom.ActionUpdate
+	Delete = axiom.ActionDelete
+)
+
+// capability describes a capability of T by its name in the Axiom API and how
+// to access its actions.
+type capability[T any] struct {
+	name    string
+	actions func(*T) *[]axiom.Action
+}
+
+var datasetCapabilities = []capability[axiom.DatasetCapabilities]{
+	{"ingest", func(c *axiom.DatasetCapabilities) *[]axiom.Action { return &c.Ingest }},
+	{"query", func(c *axiom.DatasetCapabilities) *[]axiom.Action { return &c.Query }},
+	{"starredQueries", func(c *axiom.DatasetCapabilities) *[]axiom.Action { return &c.StarredQueries }},
+	{"virtualFields", func(c *axiom.DatasetCapabilities) *[]axiom.Action { return &c.VirtualFields }},
+	{"trim", func(c *axiom.DatasetCapabilities) *[]axiom.Action { return &c.Trim }},
+	{"vacuum", func(c *axiom.DatasetCapabilities) *[]axiom.Action { return &c.Vacuum }},
+	{"data", func(c *axiom.DatasetCapabilities) *[]axiom.Action { return &c.Data }},
+	{"share", func(c *axiom.DatasetCapabilities) *[]axiom.Action { return &c.Share }},
+}
+
+var organisationCapabilities = []capability[axiom.OrganisationCapabilities]{
+	{"annotations", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.Annotations }},
+	{"apiTokens", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.APITokens }},
+	{"auditLog", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.AuditLog }},
+	{"billing", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.Billing }},
+	{"dashboards", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.Dashboards }},
+	{"datasets", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.Datasets }},
+	{"endpoints", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.Endpoints }},
+	{"flows", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.Flows }},
+	{"integrations", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.Integrations }},
+	{"monitors", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.Monitors }},
+	{"notifiers", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.Notifiers }},
+	{"rbac", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.RBAC }},
+	{"sharedAccessKeys", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.SharedAccessKeys }},
+	{"users", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.Users }},
+	{"views", func(c *axiom.OrganisationCapabilities) *[]axiom.Action { return &c.Views }},
+}
+
+// Policy describes the capabilities of an API token. Use [NewPolicy] to build
+// one from scratch or [PolicyFromToken] to inspect an existing token. The zero
+// value grants nothing and is ready to use.
+//
+// Usage:
+//
+//	policy := tokens.NewPolicy().
+//		Dataset("logs").Ingest(tokens.Create).Query(tokens.Read).
+//		Org().Monitors(tokens.Read).
+//		Policy()
+//
+//	req := axiom.CreateTokenRequest{Name: "ingest-logs"}
+//	policy.Apply(&req)
+type Policy struct {
+	datasets map[string]*axiom.DatasetCapabilities
+	org      axiom.OrganisationCapabilities
+}
+
+// NewPolicy returns a new [Policy] that grants nothing.
+func NewPolicy() *Policy {
+	return new(Policy)
+}
+
+// PolicyFromToken returns the [Policy] of the given token.
+func PolicyFromToken(token *axiom.APIToken) *Policy {
+	p := new(Policy)
+	for name, caps := range token.DatasetCapabilities {
+		ds := p.Dataset(name)
+		for _, c := range datasetCapabilities {
+			ds.add(c.actions(ds.caps), *c.actions(&caps)...)
+		}
+	}
+	for _, c := range organisationCapabilities {
+		p.Org().add(c.actions(&p.org), *c.actions(&token.OrganisationCapabilities)...)
+	}
+	return p
+}
+
+// Dataset returns a [DatasetPolicy] to grant capabilities on the dataset with
+// the given name.
+func (p *Policy) Dataset(name string) *DatasetPolicy {
+	if p.datasets == nil {
+		p.datasets = make(map[string]*axiom.DatasetCapabilities)
+	}
+	caps, ok := p.datasets[name]
+	if !ok {
+		caps = new(axiom.DatasetCapabilities)
+		p.datasets[name] = caps
+	}
+	return &DatasetPolicy{policy: p, caps: caps}
+}
+
+// Org returns an [OrgPolicy] to grant capabilities on the organisation.
+func (p *Policy) Org() *OrgPolicy {
+	return &OrgPolicy{policy: p, caps: &p.org}
+}
+
+// DatasetCapabilities returns the capabilities the policy grants on datasets,
+// keyed by dataset name.
+func (p *Policy) DatasetCapabilities() map[string]axiom.DatasetCapabilities {
+	res := make(map[string]axiom.DatasetCapabilities, len(p.datasets))
+	for name, caps := range p.datasets {
+		res[name] = cloneCapabilities(*caps, datasetCapabilities)
+	}
+	return res
+}
+
+// OrganisationCapabilities returns the capabilities the policy grants on the
+// organisation.
+func (p *Policy) OrganisationCapabilities() axiom.OrganisationCapabilities {
+	return cloneCapabilities(p.org, organisationCapabilities)
+}
+
+// Apply sets the capabilities of the token request to the ones granted by the
+// policy.
+func (p *Policy) Apply(req *axiom.CreateTokenRequest) {
+	req.DatasetCapabilities = p.DatasetCapabilities()
+	req.OrganisationCapabilities = p.OrganisationCapabilities()
+}
+
+// DatasetPolicy grants capabilities on a single dataset of a [Policy]. All
+// methods add to the actions already granted and return the DatasetPolicy for
+// chaining.
+type DatasetPolicy struct {
+	policy *Policy
+	caps   *axiom.DatasetCapabilities
+}
+
+// Ingest grants the given actions on the ingest capability.
+func (dp *DatasetPolicy) Ingest(actions ...axiom.Action) *DatasetPolicy {
+	return dp.add(&dp.caps.Ingest, actions...)
+}
+
+// Query grants the given actions on the query capability.
+func (dp *DatasetPolicy) Query(actions ...axiom.Action) *DatasetPolicy {
+	return dp.add(&dp.caps.Query, actions...)
+}
+
+// StarredQueries grants the given actions on the starred queries capability.
+func (dp *DatasetPolicy) StarredQueries(actions ...axiom.Action) *DatasetPolicy {
+	return dp.add(&dp.caps.StarredQueries, actions...)
+}
+
+// VirtualFields grants the given actions on the virtual fields capability.
+func (dp *DatasetPolicy) VirtualFields(actions ...axiom.Action) *DatasetPolicy {
+	return dp.add(&dp.caps.VirtualFields, actions...)
+}
+
+// Trim grants the given actions on the trim capability.
+func (dp *DatasetPolicy) Trim(actions ...axiom.Action) *DatasetPolicy {
+	return dp.add(&dp.caps.Trim, actions...)
+}
+
+// Vacuum grants the given actions on the vacuum capability.
+func (dp *DatasetPolicy) Vacuum(actions ...axiom.Action) *DatasetPolicy {
+	return dp.add(&dp.caps.Vacuum, actions...)
+}
+
+// Data grants the given actions on the data capability.
+func (dp *DatasetPolicy) Data(actions ...axiom.Action) *DatasetPolicy {
+	return dp.add(&dp.caps.Data, actions...)
+}
+
+// Share grants the given actions on the share capability.
+func (dp *DatasetPolicy) Share(actions ...axiom.Action) *DatasetPolicy {
+	return dp.add(&dp.caps.Share, actions...)
+}
+
+// Dataset continues with the dataset with the given name. See
+// [Policy.Dataset].
+func (dp *DatasetPolicy) Dataset(name string) *DatasetPolicy {
+	return dp.policy.Dataset(name)
+}
+
+// Org continues with the organisation. See [Policy.Org].
+func (dp *DatasetPolicy) Org() *OrgPolicy {
+	return dp.policy.Org()
+}
+
+// Policy returns the [Policy] the DatasetPolicy belongs to.
+func (dp *DatasetPolicy) Policy() *Policy {
+	return dp.policy
+}
+
+func (dp *DatasetPolicy) add(dst *[]axiom.Action, actions ...axiom.Action) *DatasetPolicy {
+	*dst = addActions(*dst, actions...)
+	return dp
+}
+
+// OrgPolicy grants capabilities on the organisation of a [Policy]. All methods
+// add to the actions already granted and return the OrgPolicy for chaining.
+type OrgPolicy struct {
+	policy *Policy
+	caps   *axiom.OrganisationCapabilities
+}
+
+// Annotations grants the given actions on the annotations capability.
+func (op *OrgPolicy) Annotations(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.Annotations, actions...)
+}
+
+// APITokens grants the given actions on the API tokens capability.
+func (op *OrgPolicy) APITokens(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.APITokens, actions...)
+}
+
+// AuditLog grants the given actions on the audit log capability.
+func (op *OrgPolicy) AuditLog(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.AuditLog, actions...)
+}
+
+// Billing grants the given actions on the billing capability.
+func (op *OrgPolicy) Billing(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.Billing, actions...)
+}
+
+// Dashboards grants the given actions on the dashboards capability.
+func (op *OrgPolicy) Dashboards(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.Dashboards, actions...)
+}
+
+// Datasets grants the given actions on the datasets capability.
+func (op *OrgPolicy) Datasets(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.Datasets, actions...)
+}
+
+// Endpoints grants the given actions on the endpoints capability.
+func (op *OrgPolicy) Endpoints(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.Endpoints, actions...)
+}
+
+// Flows grants the given actions on the flows capability.
+func (op *OrgPolicy) Flows(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.Flows, actions...)
+}
+
+// Integrations grants the given actions on the integrations capability.
+func (op *OrgPolicy) Integrations(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.Integrations, actions...)
+}
+
+// Monitors grants the given actions on the monitors capability.
+func (op *OrgPolicy) Monitors(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.Monitors, actions...)
+}
+
+// Notifiers grants the given actions on the notifiers capability.
+func (op *OrgPolicy) Notifiers(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.Notifiers, actions...)
+}
+
+// RBAC grants the given actions on the RBAC capability.
+func (op *OrgPolicy) RBAC(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.RBAC, actions...)
+}
+
+// SharedAccessKeys grants the given actions on the shared access keys
+// capability.
+func (op *OrgPolicy) SharedAccessKeys(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.SharedAccessKeys, actions...)
+}
+
+// Users grants the given actions on the users capability.
+func (op *OrgPolicy) Users(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.Users, actions...)
+}
+
+// Views grants the given actions on the views capability.
+func (op *OrgPolicy) Views(actions ...axiom.Action) *OrgPolicy {
+	return op.add(&op.caps.Views, actions...)
+}
+
+// Dataset continues with the dataset with the given name. See
+// [Policy.Dataset].
+func (op *OrgPolicy) Dataset(name string) *DatasetPolicy {
+	return op.policy.Dataset(name)
+}
+
+// Policy returns the [Policy] the OrgPolicy belongs to.
+func (op *OrgPolicy) Policy() *Policy {
+	return op.policy
+}
+
+func (op *OrgPolicy) add(dst *[]axiom.Action, actions ...axiom.Action) *OrgPolicy {
+	*dst = addActions(*dst, actions...)
+	return op
+}
+
+// Change is a difference in the actions granted on a single capability.
+type Change struct {
+	// Dataset the capability applies to. Empty for organisation capabilities.
+	Dataset string
+	// Capability is the name of the capability as used by the Axiom API, e.g.
+	// "ingest" or "monitors".
+	Capability string
+	// Added are the actions that are granted only by the new policy.
+	Added []axiom.Action
+	// Removed are the actions that are granted only by the old policy.
+	Removed []axiom.Action
+}
+
+// String returns a string representation of the change, e.g.
+// `dataset "logs" ingest: +create -read`.
+func (c Change) String() string {
+	var sb strings.Builder
+	if c.Dataset != "" {
+		fmt.Fprintf(&sb, "dataset %q %s:", c.Dataset, c.Capability)
+	} else {
+		fmt.Fprintf(&sb, "org %s:", c.Capability)
+	}
+	for _, a := range c.Added {
+		fmt.Fprintf(&sb, " +%s", a)
+	}
+	for _, a := range c.Removed {
+		fmt.Fprintf(&sb, " -%s", a)
+	}
+	return sb.String()
+}
+
+// Diff returns the changes between the capabilities of two tokens.
+func Diff(from, to *axiom.APIToken) []Change {
+	return PolicyFromToken(from).Diff(PolicyFromToken(to))
+}
+
+// Diff returns the changes from the policy to the other one. Organisation
+// capabilities come first, followed by the dataset capabilities sorted by
+// dataset name. It returns nil if both policies grant the same actions.
+func (p *Policy) Diff(other *Policy) []Change {
+	var changes []Change
+
+	changes = appendChanges(changes, "", p.org, other.org, organisationCapabilities)
+
+	names := slices.Collect(maps.Keys(p.datasets))
+	for name := range other.datasets {
+		if _, ok := p.datasets[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		var from, to axiom.DatasetCapabilities
+		if caps, ok := p.datasets[name]; ok {
+			from = *caps
+		}
+		if caps, ok := other.datasets[name]; ok {
+			to = *caps
+		}
+		changes = appendChanges(changes, name, from, to, datasetCapabilities)
+	}
+
+	return changes
+}
+
+func appendChanges[T any](changes []Change, dataset string, from, to T, caps []capability[T]) []Change {
+	for _, c := range caps {
+		fromActions, toActions := *c.actions(&from), *c.actions(&to)
+
+		change := Change{Dataset: dataset, Capability: c.name}
+		for _, a := range toActions {
+			if !slices.Contains(fromActions, a) {
+				change.Added = append(change.Added, a)
+			}
+		}
+		for _, a := range fromActions {
+			if !slices.Contains(toActions, a) {
+				change.Removed = append(change.Removed, a)
+			}
+		}
+		if len(change.Added) > 0 || len(change.Removed) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// addActions adds the actions to the given ones, keeping them sorted and free
+// of duplicates.
+func addActions(dst []axiom.Action, actions ...axiom.Action) []axiom.Action {
+	for _, a := range actions {
+		if i, found := slices.BinarySearch(dst, a); !found {
+			dst = slices.Insert(dst, i, a)
+		}
+	}
+	return dst
+}
+
+func cloneCapabilities[T any](caps T, capabilities []capability[T]) T {
+	for _, c := range capabilities {
+		actions := c.actions(&caps)
+		*actions = slices.Clone(*actions)
+	}
+	return caps
+}
@@ -0,0 +1,147 @@
+package tokens_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/tokens"
+)
+
+func TestPolicy(t *testing.T) {
+	policy := tokens.NewPolicy().
+		Dataset("logs").Ingest(tokens.Create).Query(tokens.Read, tokens.Read).
+		Dataset("traces").Query(tokens.Read).
+		Org().Monitors(tokens.Read).Monitors(tokens.Update, tokens.Read).
+		Dataset("logs").Ingest(tokens.Create).Trim(tokens.Update).
+		Policy()
+
+	var req axiom.CreateTokenRequest
+	policy.Apply(&req)
+
+	assert.Equal(t, map[string]axiom.DatasetCapabilities{
+		"logs": {
+			Ingest: []axiom.Action{axiom.ActionCreate},
+			Query:  []axiom.Action{axiom.ActionRead},
+			Trim:   []axiom.Action{axiom.ActionUpdate},
+		},
+		"traces": {
+			Query: []axiom.Action{axiom.ActionRead},
+		},
+	}, req.DatasetCapabilities)
+	assert.Equal(t, axiom.OrganisationCapabilities{
+		Monitors: []axiom.Action{axiom.ActionRead, axiom.ActionUpdate},
+	}, req.OrganisationCapabilities)
+
+	// The request doesn't share state with the policy.
+	req.OrganisationCapabilities.Monitors[0] = axiom.ActionDelete
+	assert.Equal(t, []axiom.Action{axiom.ActionRead, axiom.ActionUpdate}, policy.OrganisationCapabilities().Monitors)
+}
+
+func TestDiff(t *testing.T) {
+	from := &axiom.APIToken{
+		DatasetCapabilities: map[string]axiom.DatasetCapabilities{
+			"logs": {
+				Ingest: []axiom.Action{axiom.ActionCreate},
+				Query:  []axiom.Action{axiom.ActionRead},
+			},
+			"old": {
+				Query: []axiom.Action{axiom.ActionRead},
+			},
+		},
+		OrganisationCapabilities: axiom.OrganisationCapabilities{
+			Monitors: []axiom.Action{axiom.ActionRead, axiom.ActionUpdate},
+		},
+	}
+	to := &axiom.APIToken{
+		DatasetCapabilities: map[string]axiom.DatasetCapabilities{
+			"logs": {
+				Ingest: []axiom.Action{axiom.ActionCreate},
+			},
+			"new": {
+				Ingest: []axiom.Action{axiom.ActionCreate},
+			},
+		},
+		OrganisationCapabilities: axiom.OrganisationCapabilities{
+			Monitors:  []axiom.Action{axiom.ActionUpdate, axiom.ActionRead},
+			Notifiers: []axiom.Action{axiom.ActionRead},
+		},
+	}
+
+	changes := tokens.Diff(from, to)
+	assert.Equal(t, []tokens.Change{
+		{Capability: "notifiers", Added: []axiom.Action{axiom.ActionRead}},
+		{Dataset: "logs", Capability: "query", Removed: []axiom.Action{axiom.ActionRead}},
+		{Dataset: "new", Capability: "ingest", Added: []axiom.Action{axiom.ActionCreate}},
+		{Dataset: "old", Capability: "query", Removed: []axiom.Action{axiom.ActionRead}},
+	}, changes)
+
+	assert.Equal(t, "org notifiers: +read", changes[0].String())
+	assert.Equal(t, `dataset "logs" query: -read`, changes[1].String())
+
+	assert.Empty(t, tokens.Diff(from, from))
+}
+
+func TestPolicy_Check(t *testing.T) {
+	policy := tokens.NewPolicy().
+		Dataset("logs").Ingest(tokens.Create).
+		Org().Monitors(tokens.Read).
+		Policy()
+
+	tests := []struct {
+		op      tokens.Operation
+		dataset string
+		err     string
+	}{
+		{op: tokens.OpIngestEvents, dataset: "logs"},
+		{op: tokens.OpMonitorsList},
+		{op: tokens.OpMonitorsGet, dataset: "ignored"},
+		{
+			op:      tokens.OpIngestEvents,
+			dataset: "traces",
+			err:     `missing capability: IngestEvents requires create on dataset "traces" ingest`,
+		},
+		{
+			op:      tokens.OpQuery,
+			dataset: "logs",
+			err:     `missing capability: Query requires read on dataset "logs" query`,
+		},
+		{
+			op:  tokens.OpMonitorsCreate,
+			err: "missing capability: Monitors.Create requires create on org monitors",
+		},
+		{
+			op:  tokens.OpQuery,
+			err: "operation Query requires a dataset",
+		},
+		{
+			op:  "Foo.Bar",
+			err: `unknown operation "Foo.Bar"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.op)+"/"+tt.dataset, func(t *testing.T) {
+			err := policy.Check(tt.op, tt.dataset)
+			if tt.err == "" {
+				require.NoError(t, err)
+				assert.True(t, policy.Allows(tt.op, tt.dataset))
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+			assert.False(t, policy.Allows(tt.op, tt.dataset))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	token := &axiom.APIToken{
+		DatasetCapabilities: map[string]axiom.DatasetCapabilities{
+			"logs": {Ingest: []axiom.Action{axiom.ActionCreate}},
+		},
+	}
+
+	assert.NoError(t, tokens.Check(token, tokens.OpIngest, "logs"))
+	assert.ErrorIs(t, tokens.Check(token, tokens.OpTokensCreate, ""), tokens.ErrMissingCapability)
+}
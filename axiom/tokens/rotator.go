@@ -0,0 +1,327 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+// AnnotationType is the type of the annotations created for token rotations.
+const AnnotationType = "token-rotation"
+
+// ErrNoToken is returned by [Rotator.Credentials] if the token has neither
+// been set using [SetToken] nor been rotated, yet.
+var ErrNoToken = errors.New("token not known before first rotation")
+
+// A Sink stores a newly generated token, e.g. in a file or a secret store. The
+// secret token value is only available at this point and can't be retrieved
+// from the Axiom API later.
+type Sink func(ctx context.Context, token *axiom.CreateTokenResponse) error
+
+// An Option modifies the configuration of a [Rotator].
+type Option func(*Rotator) error
+
+// SetToken sets the current secret value of the rotated token, so it can be
+// served by [Rotator.Credentials] before the first rotation.
+func SetToken(token string) Option {
+	return func(r *Rotator) error {
+		r.token = token
+		return nil
+	}
+}
+
+// SetRotateBefore sets how long before its expiry a token is rotated. Defaults
+// to 24 hours.
+func SetRotateBefore(d time.Duration) Option {
+	return func(r *Rotator) error {
+		if d <= 0 {
+			return errors.New("rotate before duration must be positive")
+		}
+		r.rotateBefore = d
+		return nil
+	}
+}
+
+// SetOverlap sets how long the existing token stays valid after it was
+// rotated, so all consumers have time to pick up the new one. It never extends
+// the lifetime of the existing token. Defaults to one hour.
+func SetOverlap(d time.Duration) Option {
+	return func(r *Rotator) error {
+		if d < 0 {
+			return errors.New("overlap must not be negative")
+		}
+		r.overlap = d
+		return nil
+	}
+}
+
+// SetValidity sets how long a newly generated token is valid. If not set, the
+// Axiom API decides about the expiry of the new token.
+func SetValidity(d time.Duration) Option {
+	return func(r *Rotator) error {
+		if d < 0 {
+			return errors.New("validity must not be negative")
+		}
+		r.validity = d
+		return nil
+	}
+}
+
+// SetInterval sets how often [Rotator.Run] checks if the token needs to be
+// rotated. Defaults to one hour.
+func SetInterval(d time.Duration) Option {
+	return func(r *Rotator) error {
+		if d <= 0 {
+			return errors.New("interval must be positive")
+		}
+		r.interval = d
+		return nil
+	}
+}
+
+// SetErrorHandler sets a function that is called with errors that occur in
+// [Rotator.Run]. With a handler set, Run keeps going and tries again at the
+// next interval. Without one, Run returns on the first error.
+func SetErrorHandler(handler func(error)) Option {
+	return func(r *Rotator) error {
+		r.errorHandler = handler
+		return nil
+	}
+}
+
+// SetAuditDataset makes the rotator ingest an event into the given dataset
+// for every rotation. The event never contains secret token values.
+func SetAuditDataset(ingester axiom.Ingester, dataset string) Option {
+	return func(r *Rotator) error {
+		if dataset == "" {
+			return errors.New("audit dataset must not be empty")
+		}
+		r.auditIngester, r.auditDataset = ingester, dataset
+		return nil
+	}
+}
+
+// SetAnnotations makes the rotator create an annotation of type
+// [AnnotationType] on the given datasets for every rotation.
+func SetAnnotations(annotations axiom.Annotations, datasets ...string) Option {
+	return func(r *Rotator) error {
+		if len(datasets) == 0 {
+			return errors.New("annotation datasets must not be empty")
+		}
+		r.annotations, r.annotationDatasets = annotations, datasets
+		return nil
+	}
+}
+
+// Rotator rotates an API token ahead of its expiry. It implements
+// [axiom.CredentialsProvider] so clients using it always use the latest token.
+// It is safe for concurrent use.
+type Rotator struct {
+	tokens axiom.Tokens
+	sink   Sink
+
+	rotateBefore time.Duration
+	overlap      time.Duration
+	validity     time.Duration
+	interval     time.Duration
+	errorHandler func(error)
+
+	auditIngester      axiom.Ingester
+	auditDataset       string
+	annotations        axiom.Annotations
+	annotationDatasets []string
+
+	// rotateMu serializes rotations, so the token is only regenerated once,
+	// even if rotations are triggered concurrently.
+	rotateMu sync.Mutex
+
+	mu    sync.Mutex
+	id    string
+	token string
+}
+
+var _ axiom.CredentialsProvider = (*Rotator)(nil)
+
+// New creates a new [Rotator] for the API token with the given ID, managed
+// using the given tokens service. Every newly generated token is passed to the
+// sink.
+func New(tokens axiom.Tokens, id string, sink Sink, options ...Option) (*Rotator, error) {
+	if tokens == nil {
+		return nil, errors.New("tokens service must not be nil")
+	} else if id == "" {
+		return nil, errors.New("token id must not be empty")
+	} else if sink == nil {
+		return nil, errors.New("sink must not be nil")
+	}
+
+	r := &Rotator{
+		tokens: tokens,
+		sink:   sink,
+
+		rotateBefore: 24 * time.Hour,
+		overlap:      time.Hour,
+		interval:     time.Hour,
+
+		id: id,
+	}
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// ID returns the ID of the current token. It changes with every rotation.
+func (r *Rotator) ID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.id
+}
+
+// Credentials implements [axiom.CredentialsProvider]. It returns the latest
+// token.
+func (r *Rotator) Credentials(context.Context) (axiom.Credentials, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.token == "" {
+		return axiom.Credentials{}, ErrNoToken
+	}
+	return axiom.Credentials{Token: r.token}, nil
+}
+
+// Run checks if the token needs to be rotated immediately and then at the
+// configured interval, until the context is canceled.
+func (r *Rotator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RotateIfNeeded(ctx); ctx.Err() != nil {
+			return ctx.Err()
+		} else if err != nil && r.errorHandler == nil {
+			return err
+		} else if err != nil {
+			r.errorHandler(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RotateIfNeeded rotates the token, if it expires within the configured
+// duration. Tokens that never expire are never rotated. It returns the new
+// token, if it was rotated.
+func (r *Rotator) RotateIfNeeded(ctx context.Context) (*axiom.CreateTokenResponse, error) {
+	r.rotateMu.Lock()
+	defer r.rotateMu.Unlock()
+
+	token, err := r.tokens.Get(ctx, r.ID())
+	if err != nil {
+		return nil, fmt.Errorf("get token: %w", err)
+	}
+
+	if token.ExpiresAt.IsZero() || time.Now().Before(token.ExpiresAt.Add(-r.rotateBefore)) {
+		return nil, nil
+	}
+	return r.rotate(ctx, token)
+}
+
+// Rotate rotates the token, regardless of its expiry.
+func (r *Rotator) Rotate(ctx context.Context) (*axiom.CreateTokenResponse, error) {
+	r.rotateMu.Lock()
+	defer r.rotateMu.Unlock()
+
+	token, err := r.tokens.Get(ctx, r.ID())
+	if err != nil {
+		return nil, fmt.Errorf("get token: %w", err)
+	}
+	return r.rotate(ctx, token)
+}
+
+// rotate regenerates the token. It must be called with rotateMu held. Once the token is regenerated, it is served
+// to clients, even if storing or auditing it fails.
+func (r *Rotator) rotate(ctx context.Context, existing *axiom.APIToken) (*axiom.CreateTokenResponse, error) {
+	now := time.Now()
+
+	req := axiom.RegenerateTokenRequest{
+		ExistingTokenExpiresAt: now.Add(r.overlap),
+	}
+	if !existing.ExpiresAt.IsZero() && existing.ExpiresAt.Before(req.ExistingTokenExpiresAt) {
+		req.ExistingTokenExpiresAt = existing.ExpiresAt
+	}
+	if r.validity > 0 {
+		req.NewTokenExpiresAt = now.Add(r.validity)
+	}
+
+	token, err := r.tokens.Regenerate(ctx, existing.ID, req)
+	if err != nil {
+		return nil, fmt.Errorf("regenerate token: %w", err)
+	}
+
+	r.mu.Lock()
+	r.id, r.token = token.ID, token.Token
+	r.mu.Unlock()
+
+	var errs []error
+	if err = r.sink(ctx, token); err != nil {
+		errs = append(errs, fmt.Errorf("store token: %w", err))
+	}
+	if err = r.audit(ctx, existing, token, req.ExistingTokenExpiresAt); err != nil {
+		errs = append(errs, fmt.Errorf("audit token rotation: %w", err))
+	}
+
+	return token, errors.Join(errs...)
+}
+
+func (r *Rotator) audit(ctx context.Context, existing *axiom.APIToken, token *axiom.CreateTokenResponse, existingExpiresAt time.Time) error {
+	now := time.Now()
+
+	var errs []error
+	if r.auditIngester != nil {
+		event := axiom.Event{
+			ingest.TimestampField: now,
+			"action":              "token_rotated",
+			"token": map[string]any{
+				"id":        existing.ID,
+				"name":      existing.Name,
+				"expiresAt": existingExpiresAt,
+			},
+			"newToken": map[string]any{
+				"id":        token.ID,
+				"name":      token.Name,
+				"expiresAt": token.ExpiresAt,
+			},
+		}
+		if _, err := r.auditIngester.IngestEvents(ctx, r.auditDataset, []axiom.Event{event}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if r.annotations != nil {
+		if _, err := r.annotations.Create(ctx, &axiom.AnnotationCreateRequest{
+			Datasets: r.annotationDatasets,
+			Type:     AnnotationType,
+			Time:     now,
+			Title:    fmt.Sprintf("Rotated API token %q", existing.Name),
+			Description: fmt.Sprintf("Token %s was regenerated as %s. The existing token expires at %s.",
+				existing.ID, token.ID, existingExpiresAt.UTC().Format(time.RFC3339)),
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
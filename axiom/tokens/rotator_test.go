@@ -0,0 +1,220 @@
+package tokens_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/axiomtest"
+	"github.com/axiomhq/axiom-go/axiom/tokens"
+)
+
+func setup(t *testing.T, expiresIn time.Duration) (*axiomtest.Server, *axiom.Client, *axiom.CreateTokenResponse) {
+	t.Helper()
+
+	srv := axiomtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := srv.Client()
+	require.NoError(t, err)
+
+	token, err := client.Tokens.Create(t.Context(), axiom.CreateTokenRequest{
+		Name:      "ingest",
+		ExpiresAt: time.Now().Add(expiresIn),
+	})
+	require.NoError(t, err)
+
+	return srv, client, token
+}
+
+func TestNew(t *testing.T) {
+	_, client, _ := setup(t, time.Hour)
+
+	sink := func(context.Context, *axiom.CreateTokenResponse) error { return nil }
+
+	_, err := tokens.New(nil, "id", sink)
+	assert.EqualError(t, err, "tokens service must not be nil")
+
+	_, err = tokens.New(client.Tokens, "", sink)
+	assert.EqualError(t, err, "token id must not be empty")
+
+	_, err = tokens.New(client.Tokens, "id", nil)
+	assert.EqualError(t, err, "sink must not be nil")
+
+	_, err = tokens.New(client.Tokens, "id", sink, tokens.SetInterval(0))
+	assert.EqualError(t, err, "interval must be positive")
+}
+
+func TestRotator_RotateIfNeeded(t *testing.T) {
+	_, client, token := setup(t, time.Hour)
+
+	var stored []*axiom.CreateTokenResponse
+	r, err := tokens.New(client.Tokens, token.ID,
+		func(_ context.Context, token *axiom.CreateTokenResponse) error {
+			stored = append(stored, token)
+			return nil
+		},
+		tokens.SetToken(token.Token),
+		tokens.SetOverlap(10*time.Minute),
+		tokens.SetValidity(30*24*time.Hour),
+	)
+	require.NoError(t, err)
+
+	creds, err := r.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, token.Token, creds.Token)
+
+	newToken, err := r.RotateIfNeeded(t.Context())
+	require.NoError(t, err)
+	require.NotNil(t, newToken)
+	assert.Equal(t, []*axiom.CreateTokenResponse{newToken}, stored)
+	assert.NotEqual(t, token.ID, newToken.ID)
+	assert.NotEqual(t, token.Token, newToken.Token)
+	assert.Equal(t, "ingest", newToken.Name)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), newToken.ExpiresAt, time.Minute)
+	assert.Equal(t, newToken.ID, r.ID())
+
+	creds, err = r.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, newToken.Token, creds.Token)
+
+	// The existing token stays valid for the configured overlap.
+	existing, err := client.Tokens.Get(t.Context(), token.ID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), existing.ExpiresAt, time.Minute)
+
+	// The new token doesn't need to be rotated, yet.
+	newToken, err = r.RotateIfNeeded(t.Context())
+	require.NoError(t, err)
+	assert.Nil(t, newToken)
+	assert.Len(t, stored, 1)
+}
+
+func TestRotator_RotateIfNeeded_NoExpiry(t *testing.T) {
+	_, client, _ := setup(t, 0)
+
+	token, err := client.Tokens.Create(t.Context(), axiom.CreateTokenRequest{Name: "forever"})
+	require.NoError(t, err)
+
+	r, err := tokens.New(client.Tokens, token.ID, func(context.Context, *axiom.CreateTokenResponse) error {
+		return errors.New("unexpected rotation")
+	})
+	require.NoError(t, err)
+
+	newToken, err := r.RotateIfNeeded(t.Context())
+	require.NoError(t, err)
+	assert.Nil(t, newToken)
+
+	_, err = r.Credentials(t.Context())
+	assert.ErrorIs(t, err, tokens.ErrNoToken)
+}
+
+func TestRotator_RotateIfNeeded_Concurrent(t *testing.T) {
+	_, client, token := setup(t, time.Hour)
+
+	var (
+		mu     sync.Mutex
+		stored []*axiom.CreateTokenResponse
+	)
+	r, err := tokens.New(client.Tokens, token.ID,
+		func(_ context.Context, token *axiom.CreateTokenResponse) error {
+			mu.Lock()
+			defer mu.Unlock()
+			stored = append(stored, token)
+			return nil
+		},
+		tokens.SetToken(token.Token),
+		tokens.SetValidity(30*24*time.Hour),
+	)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			_, err := r.RotateIfNeeded(t.Context())
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+
+	// Only the first call rotates, the others see the new token which isn't
+	// due, yet.
+	require.Len(t, stored, 1)
+	assert.Equal(t, stored[0].ID, r.ID())
+
+	creds, err := r.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, stored[0].Token, creds.Token)
+}
+
+func TestRotator_Rotate(t *testing.T) {
+	srv, client, token := setup(t, 48*time.Hour)
+
+	srv.CreateDataset("audit")
+
+	r, err := tokens.New(client.Tokens, token.ID,
+		func(context.Context, *axiom.CreateTokenResponse) error {
+			return errors.New("secret store unavailable")
+		},
+		tokens.SetAuditDataset(client, "audit"),
+		tokens.SetAnnotations(client.Annotations, "audit"),
+	)
+	require.NoError(t, err)
+
+	// The token isn't due, but rotation is forced.
+	newToken, err := r.Rotate(t.Context())
+	require.EqualError(t, err, "store token: secret store unavailable")
+	require.NotNil(t, newToken)
+
+	// The new token is served, even though storing it failed.
+	creds, err := r.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, newToken.Token, creds.Token)
+
+	events := srv.Events("audit")
+	require.Len(t, events, 1)
+	assert.Equal(t, "token_rotated", events[0]["action"])
+	assert.Equal(t, token.ID, events[0]["token"].(map[string]any)["id"])
+	assert.Equal(t, newToken.ID, events[0]["newToken"].(map[string]any)["id"])
+	assert.NotContains(t, events[0]["newToken"], "token")
+
+	annotations, err := client.Annotations.List(t.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, annotations, 1)
+	assert.Equal(t, tokens.AnnotationType, annotations[0].Type)
+	assert.Equal(t, `Rotated API token "ingest"`, annotations[0].Title)
+	assert.Equal(t, []string{"audit"}, annotations[0].Datasets)
+}
+
+func TestRotator_Run(t *testing.T) {
+	srv, client, token := setup(t, time.Hour)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	r, err := tokens.New(client.Tokens, token.ID,
+		func(context.Context, *axiom.CreateTokenResponse) error {
+			cancel()
+			return nil
+		},
+		tokens.SetInterval(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	// The rotated client picks up the new token from the rotator.
+	rotatedClient, err := srv.Client(axiom.SetCredentialsProvider(r))
+	require.NoError(t, err)
+
+	err = r.Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotEqual(t, token.ID, r.ID())
+
+	_, err = rotatedClient.Datasets.List(t.Context())
+	require.NoError(t, err)
+}
@@ -0,0 +1,431 @@
+package axiom
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axiomhq/axiom-go/axiom/query"
+)
+
+// Field names of the spans stored in an OpenTelemetry traces dataset (of kind
+// "otel:traces:v1").
+const (
+	traceFieldTime          = "_time"
+	traceFieldTraceID       = "trace_id"
+	traceFieldSpanID        = "span_id"
+	traceFieldParentSpanID  = "parent_span_id"
+	traceFieldName          = "name"
+	traceFieldKind          = "kind"
+	traceFieldDuration      = "duration"
+	traceFieldServiceName   = "service.name"
+	traceFieldStatusCode    = "status.code"
+	traceFieldStatusMessage = "status.message"
+	traceFieldError         = "error"
+
+	traceFieldAttributesPrefix = "attributes."
+	traceFieldResourcePrefix   = "resource."
+)
+
+// traceSpanLimit is the maximum number of spans retrieved for a single trace.
+const traceSpanLimit = 10000
+
+// ErrTraceNotFound is returned by [DatasetsService.GetTrace] when no spans are
+// found for the requested trace.
+var ErrTraceNotFound = errors.New("trace not found")
+
+// Trace is a distributed trace assembled from the spans stored in an
+// OpenTelemetry traces dataset.
+type Trace struct {
+	// ID of the trace.
+	ID string
+	// Roots are the spans of the trace that have no parent span or whose
+	// parent span is not part of the trace. A complete trace has exactly one
+	// root span.
+	Roots []*Span
+	// Spans of the trace, ordered by their start time.
+	Spans []*Span
+	// Start is the start time of the earliest span of the trace.
+	Start time.Time
+	// End is the end time of the latest span of the trace.
+	End time.Time
+	// Duration is the time between the start of the earliest and the end of
+	// the latest span of the trace.
+	Duration time.Duration
+	// CriticalPath are the spans that contributed to the overall latency of the
+	// trace, ordered by their start time. It is computed from the longest root
+	// span by repeatedly following the child span that finished last.
+	CriticalPath []*Span
+	// Services is a breakdown of the trace by service, ordered by the time
+	// spent in each service, descending.
+	Services []ServiceBreakdown
+	// Truncated is true if the trace has more spans than could be retrieved.
+	// Only the earliest spans are part of the trace, then.
+	Truncated bool
+}
+
+// Span of a [Trace].
+type Span struct {
+	// TraceID is the ID of the trace the span belongs to.
+	TraceID string
+	// ID of the span.
+	ID string
+	// ParentID is the ID of the parent span. Empty for root spans.
+	ParentID string
+	// Name of the span.
+	Name string
+	// Kind of the span (e.g. "server", "client", "internal").
+	Kind string
+	// ServiceName is the name of the service that emitted the span.
+	ServiceName string
+	// Start time of the span.
+	Start time.Time
+	// Duration of the span.
+	Duration time.Duration
+	// StatusCode of the span (e.g. "OK", "ERROR" or "UNSET").
+	StatusCode string
+	// StatusMessage of the span.
+	StatusMessage string
+	// Error is true if the span is marked as erroneous.
+	Error bool
+	// Attributes of the span, without the "attributes." prefix.
+	Attributes map[string]any
+	// Resource attributes of the span, without the "resource." prefix.
+	Resource map[string]any
+	// Children of the span, ordered by their start time.
+	Children []*Span
+	// Depth of the span in the trace tree. Root spans have a depth of zero.
+	Depth int
+	// SelfDuration is the part of the spans duration that is not covered by
+	// any of its children.
+	SelfDuration time.Duration
+}
+
+// End returns the end time of the span.
+func (s *Span) End() time.Time {
+	return s.Start.Add(s.Duration)
+}
+
+// ServiceBreakdown is the share of a single service in a [Trace].
+type ServiceBreakdown struct {
+	// ServiceName is the name of the service.
+	ServiceName string
+	// SpanCount is the number of spans emitted by the service.
+	SpanCount int
+	// ErrorCount is the number of erroneous spans emitted by the service.
+	ErrorCount int
+	// Duration is the sum of the durations of all spans emitted by the
+	// service.
+	Duration time.Duration
+	// SelfDuration is the sum of the self durations of all spans emitted by
+	// the service. This is the time actually spent in the service, excluding
+	// time spent waiting on child spans.
+	SelfDuration time.Duration
+}
+
+// traceAPL returns the APL query that selects the earliest spans of the trace
+// identified by the given id from the given dataset. One span more than the
+// given limit is selected, so truncation can be detected.
+func traceAPL(dataset, traceID string, limit int) string {
+	return fmt.Sprintf("['%s'] | where %s == %q | order by %s asc | take %d",
+		strings.ReplaceAll(dataset, "'", `\'`), traceFieldTraceID, traceID, traceFieldTime, limit+1)
+}
+
+// newTrace assembles a [Trace] from the rows of the given query table. Spans
+// beyond the given limit are dropped and the trace is marked as truncated.
+func newTrace(traceID string, table query.Table, limit int) (*Trace, error) {
+	var (
+		spans     []*Span
+		truncated bool
+	)
+	for row := range table.Rows() {
+		if len(spans) == limit {
+			truncated = true
+			break
+		}
+		span, err := newSpan(table.Fields, row)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, span)
+	}
+	if len(spans) == 0 {
+		return nil, ErrTraceNotFound
+	}
+	t := assembleTrace(traceID, spans)
+	t.Truncated = truncated
+	return t, nil
+}
+
+func newSpan(fields []query.Field, row query.Row) (*Span, error) {
+	span := &Span{
+		Attributes: make(map[string]any),
+		Resource:   make(map[string]any),
+	}
+	for i, field := range fields {
+		v := row[i]
+		if v == nil {
+			continue
+		}
+
+		var err error
+		switch name := field.Name; {
+		case name == traceFieldTime:
+			span.Start, err = parseSpanTime(v)
+		case name == traceFieldTraceID:
+			span.TraceID = fmt.Sprint(v)
+		case name == traceFieldSpanID:
+			span.ID = fmt.Sprint(v)
+		case name == traceFieldParentSpanID:
+			span.ParentID = fmt.Sprint(v)
+		case name == traceFieldName:
+			span.Name = fmt.Sprint(v)
+		case name == traceFieldKind:
+			span.Kind = fmt.Sprint(v)
+		case name == traceFieldDuration:
+			span.Duration, err = parseSpanDuration(v)
+		case name == traceFieldServiceName:
+			span.ServiceName = fmt.Sprint(v)
+		case name == traceFieldStatusCode:
+			span.StatusCode = fmt.Sprint(v)
+		case name == traceFieldStatusMessage:
+			span.StatusMessage = fmt.Sprint(v)
+		case name == traceFieldError:
+			span.Error, _ = v.(bool)
+		case strings.HasPrefix(name, traceFieldAttributesPrefix):
+			span.Attributes[strings.TrimPrefix(name, traceFieldAttributesPrefix)] = v
+		case strings.HasPrefix(name, traceFieldResourcePrefix):
+			span.Resource[strings.TrimPrefix(name, traceFieldResourcePrefix)] = v
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse field %q of span: %w", field.Name, err)
+		}
+	}
+	if strings.EqualFold(span.StatusCode, "error") {
+		span.Error = true
+	}
+	return span, nil
+}
+
+func parseSpanTime(v any) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected type %T", v)
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// parseSpanDuration parses a span duration which is either a number of
+// nanoseconds, a Go duration string (e.g. "1.5ms") or a timespan string (e.g.
+// "00:00:01.5000000" or "1.00:00:00").
+func parseSpanDuration(v any) (time.Duration, error) {
+	switch v := v.(type) {
+	case float64:
+		return time.Duration(v), nil
+	case int64:
+		return time.Duration(v), nil
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d, nil
+		}
+		return parseTimespan(v)
+	default:
+		return 0, fmt.Errorf("unexpected type %T", v)
+	}
+}
+
+func parseTimespan(s string) (time.Duration, error) {
+	var (
+		d   time.Duration
+		neg bool
+	)
+	if strings.HasPrefix(s, "-") {
+		neg, s = true, s[1:]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid timespan %q", s)
+	}
+
+	// Days are separated from the hours by a dot.
+	if days, hours, ok := strings.Cut(parts[0], "."); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid timespan %q: %w", s, err)
+		}
+		d += time.Duration(n) * 24 * time.Hour
+		parts[0] = hours
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid timespan %q: %w", s, err)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid timespan %q: %w", s, err)
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timespan %q: %w", s, err)
+	}
+	d += time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second))
+
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// assembleTrace links the given spans into a tree and computes the derived
+// properties of the trace.
+func assembleTrace(traceID string, spans []*Span) *Trace {
+	slices.SortStableFunc(spans, func(a, b *Span) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	byID := make(map[string]*Span, len(spans))
+	for _, span := range spans {
+		byID[span.ID] = span
+	}
+
+	t := &Trace{
+		ID:    traceID,
+		Spans: spans,
+		Start: spans[0].Start,
+	}
+	for _, span := range spans {
+		if parent, ok := byID[span.ParentID]; ok && span.ParentID != "" && parent != span {
+			parent.Children = append(parent.Children, span)
+		} else {
+			t.Roots = append(t.Roots, span)
+		}
+		if end := span.End(); end.After(t.End) {
+			t.End = end
+		}
+	}
+	t.Duration = t.End.Sub(t.Start)
+
+	// Walk the tree from the roots to set the depth of each span. Spans that
+	// are part of a cycle are never reached and keep a depth of zero.
+	var setDepth func(span *Span, depth int)
+	setDepth = func(span *Span, depth int) {
+		span.Depth = depth
+		for _, child := range span.Children {
+			setDepth(child, depth+1)
+		}
+	}
+	for _, root := range t.Roots {
+		setDepth(root, 0)
+	}
+
+	for _, span := range spans {
+		span.SelfDuration = selfDuration(span)
+	}
+
+	if len(t.Roots) > 0 {
+		root := slices.MaxFunc(t.Roots, func(a, b *Span) int {
+			return cmp.Compare(a.Duration, b.Duration)
+		})
+		t.CriticalPath = criticalPath(root, root.End(), make(map[*Span]bool))
+		slices.SortStableFunc(t.CriticalPath, func(a, b *Span) int {
+			return a.Start.Compare(b.Start)
+		})
+	}
+
+	t.Services = serviceBreakdown(spans)
+
+	return t
+}
+
+// selfDuration returns the part of the spans duration that is not covered by
+// any of its children.
+func selfDuration(span *Span) time.Duration {
+	var (
+		covered time.Duration
+		cursor  = span.Start
+		end     = span.End()
+	)
+	// Children are ordered by start time, so overlapping children can be
+	// merged in a single pass.
+	for _, child := range span.Children {
+		start, childEnd := child.Start, child.End()
+		if start.Before(cursor) {
+			start = cursor
+		}
+		if childEnd.After(end) {
+			childEnd = end
+		}
+		if childEnd.After(start) {
+			covered += childEnd.Sub(start)
+			cursor = childEnd
+		}
+	}
+	return span.Duration - covered
+}
+
+// criticalPath returns the spans on the critical path of the given span, which
+// ends at the given time. The child span that finished last before the given
+// time is followed recursively, then the time is moved to the start of that
+// child and the next child is considered.
+func criticalPath(span *Span, until time.Time, visited map[*Span]bool) []*Span {
+	if visited[span] {
+		return nil
+	}
+	visited[span] = true
+
+	path := []*Span{span}
+
+	children := slices.Clone(span.Children)
+	slices.SortStableFunc(children, func(a, b *Span) int {
+		return b.End().Compare(a.End())
+	})
+
+	cursor := until
+	for _, child := range children {
+		if child.End().After(cursor) || !child.Start.Before(cursor) {
+			continue
+		}
+		path = append(path, criticalPath(child, child.End(), visited)...)
+		cursor = child.Start
+	}
+
+	return path
+}
+
+func serviceBreakdown(spans []*Span) []ServiceBreakdown {
+	var (
+		services = make(map[string]*ServiceBreakdown)
+		order    []string
+	)
+	for _, span := range spans {
+		sb, ok := services[span.ServiceName]
+		if !ok {
+			sb = &ServiceBreakdown{ServiceName: span.ServiceName}
+			services[span.ServiceName] = sb
+			order = append(order, span.ServiceName)
+		}
+		sb.SpanCount++
+		if span.Error {
+			sb.ErrorCount++
+		}
+		sb.Duration += span.Duration
+		sb.SelfDuration += span.SelfDuration
+	}
+
+	res := make([]ServiceBreakdown, 0, len(order))
+	for _, name := range order {
+		res = append(res, *services[name])
+	}
+	slices.SortStableFunc(res, func(a, b ServiceBreakdown) int {
+		return cmp.Compare(b.SelfDuration, a.SelfDuration)
+	})
+
+	return res
+}
@@ -0,0 +1,310 @@
+package axiom
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom/query"
+)
+
+const (
+	testTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	actTraceQueryResp = `{
+	"tables": [
+		{
+			"name": "0",
+			"sources": [
+				{
+					"name": "traces"
+				}
+			],
+			"fields": [
+				{
+					"name": "_time",
+					"type": "datetime"
+				},
+				{
+					"name": "trace_id",
+					"type": "string"
+				},
+				{
+					"name": "span_id",
+					"type": "string"
+				},
+				{
+					"name": "parent_span_id",
+					"type": "string"
+				},
+				{
+					"name": "name",
+					"type": "string"
+				},
+				{
+					"name": "kind",
+					"type": "string"
+				},
+				{
+					"name": "duration",
+					"type": "timespan"
+				},
+				{
+					"name": "service.name",
+					"type": "string"
+				},
+				{
+					"name": "status.code",
+					"type": "string"
+				},
+				{
+					"name": "attributes.http.route",
+					"type": "string"
+				},
+				{
+					"name": "resource.host.name",
+					"type": "string"
+				}
+			],
+			"columns": [
+				[
+					"2024-01-01T00:00:00Z",
+					"2024-01-01T00:00:00.010Z",
+					"2024-01-01T00:00:00.020Z",
+					"2024-01-01T00:00:00.060Z"
+				],
+				[
+					"4bf92f3577b34da6a3ce929d0e0e4736",
+					"4bf92f3577b34da6a3ce929d0e0e4736",
+					"4bf92f3577b34da6a3ce929d0e0e4736",
+					"4bf92f3577b34da6a3ce929d0e0e4736"
+				],
+				[
+					"a",
+					"b",
+					"c",
+					"d"
+				],
+				[
+					"",
+					"a",
+					"a",
+					"c"
+				],
+				[
+					"GET /checkout",
+					"auth",
+					"charge",
+					"INSERT payments"
+				],
+				[
+					"server",
+					"client",
+					"client",
+					"client"
+				],
+				[
+					"00:00:00.1000000",
+					"30ms",
+					70000000,
+					"00:00:00.0200000"
+				],
+				[
+					"frontend",
+					"auth",
+					"payments",
+					"payments"
+				],
+				[
+					"OK",
+					"OK",
+					"ERROR",
+					null
+				],
+				[
+					"/checkout",
+					null,
+					null,
+					null
+				],
+				[
+					"web-0",
+					"auth-0",
+					"payments-0",
+					"payments-0"
+				]
+			]
+		}
+	],
+	"status": {
+		"elapsedTime": 1000,
+		"minCursor": "",
+		"maxCursor": "",
+		"rowsExamined": 4,
+		"rowsMatched": 4
+	}
+}`
+)
+
+func TestDatasetsService_GetTrace(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		var req aplQueryRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if assert.NoError(t, err) {
+			assert.Equal(t, `['traces'] | where trace_id == "4bf92f3577b34da6a3ce929d0e0e4736" | order by _time asc | take 10001`, req.APL)
+		}
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, actTraceQueryResp)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "POST /v1/datasets/_apl", hf)
+
+	tr, err := client.Datasets.GetTrace(t.Context(), "traces", testTraceID)
+	require.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, testTraceID, tr.ID)
+	assert.Equal(t, start, tr.Start)
+	assert.Equal(t, start.Add(100*time.Millisecond), tr.End)
+	assert.Equal(t, 100*time.Millisecond, tr.Duration)
+	require.Len(t, tr.Spans, 4)
+	require.Len(t, tr.Roots, 1)
+
+	root := tr.Roots[0]
+	assert.Equal(t, "a", root.ID)
+	assert.Equal(t, "GET /checkout", root.Name)
+	assert.Equal(t, "server", root.Kind)
+	assert.Equal(t, "frontend", root.ServiceName)
+	assert.Equal(t, map[string]any{"http.route": "/checkout"}, root.Attributes)
+	assert.Equal(t, map[string]any{"host.name": "web-0"}, root.Resource)
+	// Children cover 10ms-90ms of the root span.
+	assert.Equal(t, 20*time.Millisecond, root.SelfDuration)
+
+	if assert.Len(t, root.Children, 2) {
+		assert.Equal(t, "b", root.Children[0].ID)
+		assert.Equal(t, "c", root.Children[1].ID)
+		assert.Equal(t, 1, root.Children[1].Depth)
+		assert.True(t, root.Children[1].Error)
+
+		if assert.Len(t, root.Children[1].Children, 1) {
+			assert.Equal(t, "d", root.Children[1].Children[0].ID)
+			assert.Equal(t, 2, root.Children[1].Children[0].Depth)
+		}
+	}
+
+	criticalPath := make([]string, len(tr.CriticalPath))
+	for i, span := range tr.CriticalPath {
+		criticalPath[i] = span.ID
+	}
+	// The "auth" span overlaps with the "charge" span which finishes last, so it
+	// is not on the critical path.
+	assert.Equal(t, []string{"a", "c", "d"}, criticalPath)
+
+	assert.Equal(t, []ServiceBreakdown{
+		{
+			ServiceName:  "payments",
+			SpanCount:    2,
+			ErrorCount:   1,
+			Duration:     90 * time.Millisecond,
+			SelfDuration: 70 * time.Millisecond,
+		},
+		{
+			ServiceName:  "auth",
+			SpanCount:    1,
+			Duration:     30 * time.Millisecond,
+			SelfDuration: 30 * time.Millisecond,
+		},
+		{
+			ServiceName:  "frontend",
+			SpanCount:    1,
+			Duration:     100 * time.Millisecond,
+			SelfDuration: 20 * time.Millisecond,
+		},
+	}, tr.Services)
+}
+
+func TestDatasetsService_GetTrace_NotFound(t *testing.T) {
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{"tables":[{"name":"0","fields":[],"columns":[]}],"status":{}}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "POST /v1/datasets/_apl", hf)
+
+	_, err := client.Datasets.GetTrace(t.Context(), "traces", testTraceID)
+	require.ErrorIs(t, err, ErrTraceNotFound)
+}
+
+func TestDatasetsService_GetTrace_InvalidTraceID(t *testing.T) {
+	client := newClient(t)
+
+	_, err := client.Datasets.GetTrace(t.Context(), "traces", `" or true`)
+	require.ErrorContains(t, err, "invalid trace id")
+}
+
+func TestNewTrace_Truncated(t *testing.T) {
+	table := query.Table{
+		Fields: []query.Field{
+			{Name: traceFieldTime, Type: "datetime"},
+			{Name: traceFieldSpanID, Type: "string"},
+			{Name: traceFieldParentSpanID, Type: "string"},
+		},
+		Columns: []query.Column{
+			{"2024-01-01T00:00:00Z", "2024-01-01T00:00:01Z", "2024-01-01T00:00:02Z"},
+			{"a", "b", "c"},
+			{nil, "a", "a"},
+		},
+	}
+
+	tr, err := newTrace(testTraceID, table, 3)
+	require.NoError(t, err)
+	assert.Len(t, tr.Spans, 3)
+	assert.False(t, tr.Truncated)
+
+	tr, err = newTrace(testTraceID, table, 2)
+	require.NoError(t, err)
+	if assert.Len(t, tr.Spans, 2) {
+		assert.Equal(t, "a", tr.Spans[0].ID)
+		assert.Equal(t, "b", tr.Spans[1].ID)
+	}
+	assert.True(t, tr.Truncated)
+}
+
+func TestParseSpanDuration(t *testing.T) {
+	tests := []struct {
+		input any
+		want  time.Duration
+		err   string
+	}{
+		{input: float64(1500), want: 1500 * time.Nanosecond},
+		{input: "1.5ms", want: 1500 * time.Microsecond},
+		{input: "00:00:01.5000000", want: 1500 * time.Millisecond},
+		{input: "01:02:03", want: time.Hour + 2*time.Minute + 3*time.Second},
+		{input: "1.00:00:00", want: 24 * time.Hour},
+		{input: "-00:00:01", want: -time.Second},
+		{input: "foo", err: `invalid timespan "foo"`},
+		{input: true, err: "unexpected type bool"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.input), func(t *testing.T) {
+			got, err := parseSpanDuration(tt.input)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+var datasetsCommands = []command{
+	{"list", "datasets list [-format table|json]", "List all datasets", runDatasetsList},
+	{"get", "datasets get <name>", "Get a dataset", runDatasetsGet},
+	{"create", "datasets create [flags] <name>", "Create a dataset", runDatasetsCreate},
+	{"update", "datasets update [flags] <name>", "Update a dataset", runDatasetsUpdate},
+	{"delete", "datasets delete <name>", "Delete a dataset", runDatasetsDelete},
+	{"trim", "datasets trim <name> <max-duration>", "Trim a dataset to the given duration", runDatasetsTrim},
+}
+
+func runDatasets(ctx context.Context, a *app, args []string) error {
+	return a.dispatch(ctx, "axiom-go datasets", datasetsCommands, args)
+}
+
+func runDatasetsList(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("datasets list [flags]")
+	format := fs.String("format", formatTable, "output format (table, json)")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	} else if err = checkFormat(*format, formatTable, formatJSON); err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	datasets, err := client.Datasets.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *format == formatJSON {
+		return a.printJSON(datasets)
+	}
+	rows := make([][]any, len(datasets))
+	for i, dataset := range datasets {
+		rows[i] = []any{dataset.Name, dataset.Kind, dataset.Description, dataset.CreatedAt}
+	}
+	return a.printTable([]string{"NAME", "KIND", "DESCRIPTION", "CREATED"}, rows)
+}
+
+func runDatasetsGet(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("datasets get <name>")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	dataset, err := client.Datasets.Get(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return a.printJSON(dataset)
+}
+
+func runDatasetsCreate(ctx context.Context, a *app, args []string) error {
+	var req axiom.DatasetCreateRequest
+
+	fs := a.flagSet("datasets create [flags] <name>")
+	fs.StringVar(&req.Description, "description", "", "description of the dataset")
+	fs.StringVar(&req.Kind, "kind", "", "kind of the dataset (e.g. axiom:events:v1, otel:traces:v1)")
+	fs.IntVar(&req.RetentionDays, "retention-days", 0, "retention period in days, if set")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	req.Name = args[0]
+	req.UseRetentionPeriod = req.RetentionDays > 0
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	dataset, err := client.Datasets.Create(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printJSON(dataset)
+}
+
+func runDatasetsUpdate(ctx context.Context, a *app, args []string) error {
+	var req axiom.DatasetUpdateRequest
+
+	fs := a.flagSet("datasets update [flags] <name>")
+	fs.StringVar(&req.Description, "description", "", "description of the dataset")
+	fs.IntVar(&req.RetentionDays, "retention-days", 0, "retention period in days, if set")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	req.UseRetentionPeriod = req.RetentionDays > 0
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	dataset, err := client.Datasets.Update(ctx, args[0], req)
+	if err != nil {
+		return err
+	}
+	return a.printJSON(dataset)
+}
+
+func runDatasetsDelete(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("datasets delete <name>")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+	return client.Datasets.Delete(ctx, args[0])
+}
+
+func runDatasetsTrim(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("datasets trim <name> <max-duration>")
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	maxDuration, err := time.ParseDuration(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid max duration: %w", err)
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+	return client.Datasets.Trim(ctx, args[0], maxDuration)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// labelsFlag collects "key=value" event labels.
+type labelsFlag map[string]any
+
+func (f labelsFlag) String() string {
+	return fmt.Sprint(map[string]any(f))
+}
+
+func (f labelsFlag) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return errors.New("must be of the form key=value")
+	}
+	f[key] = value
+	return nil
+}
+
+func runIngest(ctx context.Context, a *app, args []string) error {
+	var (
+		fs              = a.flagSet("ingest [flags] <dataset> [file...]")
+		contentType     = fs.String("content-type", "", "content type of the data (json, ndjson, csv), detected if not set")
+		timestampField  = fs.String("timestamp-field", "", "field to take the event time from")
+		timestampFormat = fs.String("timestamp-format", "", "format of the timestamp field")
+		csvDelimiter    = fs.String("csv-delimiter", "", "delimiter of CSV data")
+		labels          = labelsFlag{}
+	)
+	fs.Var(labels, "label", "label to add to all events as key=value (repeatable)")
+	args, err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	var options []ingest.Option
+	if *timestampField != "" {
+		options = append(options, ingest.SetTimestampField(*timestampField))
+	}
+	if *timestampFormat != "" {
+		options = append(options, ingest.SetTimestampFormat(*timestampFormat))
+	}
+	if *csvDelimiter != "" {
+		options = append(options, ingest.SetCSVDelimiter(*csvDelimiter))
+	}
+	if len(labels) > 0 {
+		options = append(options, ingest.SetEventLabels(labels))
+	}
+
+	var typ axiom.ContentType
+	switch strings.ToLower(*contentType) {
+	case "":
+	case "json":
+		typ = axiom.JSON
+	case "ndjson":
+		typ = axiom.NDJSON
+	case "csv":
+		typ = axiom.CSV
+	default:
+		return fmt.Errorf("unsupported content type %q", *contentType)
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	dataset, files := args[0], args[1:]
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	var ingested, failed uint64
+	for _, file := range files {
+		status, err := a.ingestFile(ctx, client, dataset, file, typ, options)
+		if err != nil {
+			return fmt.Errorf("ingest %s: %w", file, err)
+		}
+		ingested += status.Ingested
+		failed += status.Failed
+		for _, failure := range status.Failures {
+			fmt.Fprintf(a.stderr, "failed to ingest event: %s\n", failure.Error)
+		}
+	}
+
+	fmt.Fprintf(a.stderr, "ingested %d events, %d failed\n", ingested, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d events failed to ingest", failed)
+	}
+	return nil
+}
+
+// ingestFile ingests the file at the given path, or stdin if the path is "-".
+// Gzip and zstd compressed files are detected and decompressed, so the content
+// type can be detected, too.
+func (a *app) ingestFile(ctx context.Context, client *axiom.Client, dataset, path string, typ axiom.ContentType, options []ingest.Option) (*ingest.Status, error) {
+	var r io.Reader = a.stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	r, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if typ == 0 {
+		if r, typ, err = axiom.DetectContentType(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if r, err = axiom.ZstdEncoder()(r); err != nil {
+		return nil, err
+	}
+	return client.Ingest(ctx, dataset, r, typ, axiom.Zstd, options...)
+}
+
+// decompress returns a reader which decompresses the data read from r, if it is
+// gzip or zstd compressed.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	}
+	return br, nil
+}
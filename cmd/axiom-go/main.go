@@ -0,0 +1,128 @@
+// Command axiom-go is a command-line tool for the Axiom API, built on the axiom
+// package. It is configured using the same environment variables as the
+// client: AXIOM_TOKEN, AXIOM_ORG_ID, AXIOM_URL, AXIOM_EDGE_URL and AXIOM_EDGE.
+// Set AXIOM_DEPLOYMENT to use a deployment configured in the Axiom CLI's
+// "~/.axiom.toml" instead.
+//
+// Usage:
+//
+//	axiom-go <command> [arguments]
+//
+// Run "axiom-go help" for a list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a := &app{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		newClient: func() (*axiom.Client, error) {
+			return axiom.NewClient()
+		},
+	}
+
+	if err := a.run(ctx, os.Args[1:]); errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "axiom-go: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// app holds the dependencies of all commands, so they can be replaced in
+// tests.
+type app struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+
+	newClient func() (*axiom.Client, error)
+}
+
+// command is a (sub)command of the tool.
+type command struct {
+	name  string
+	usage string
+	short string
+	run   func(ctx context.Context, a *app, args []string) error
+}
+
+var commands = []command{
+	{"datasets", "datasets <list|get|create|update|delete|trim> ...", "Manage datasets", runDatasets},
+	{"ingest", "ingest [flags] <dataset> [file...]", "Ingest data from files or stdin", runIngest},
+	{"query", "query [flags] <apl>", "Run an APL query", runQuery},
+	{"tail", "tail [flags] <dataset>", "Follow the events ingested into a dataset", runTail},
+	{"monitors", "monitors <list|get|create|update|delete> ...", "Manage monitors", runMonitors},
+	{"notifiers", "notifiers <list|get|create|update|delete> ...", "Manage notifiers", runNotifiers},
+	{"tokens", "tokens <list|get|create|regenerate|delete> ...", "Manage API tokens", runTokens},
+	{"reconcile", "reconcile [flags] <file...>", "Reconcile monitors, notifiers and virtual fields with files", runReconcile},
+}
+
+func (a *app) run(ctx context.Context, args []string) error {
+	return a.dispatch(ctx, "axiom-go", commands, args)
+}
+
+// dispatch runs the command named by the first argument.
+func (a *app) dispatch(ctx context.Context, prefix string, cmds []command, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		a.usage(prefix, cmds)
+		return flag.ErrHelp
+	}
+
+	for _, cmd := range cmds {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, a, args[1:])
+		}
+	}
+
+	a.usage(prefix, cmds)
+	return fmt.Errorf("unknown command %q", strings.TrimSpace(prefix+" "+args[0]))
+}
+
+func (a *app) usage(prefix string, cmds []command) {
+	fmt.Fprintf(a.stderr, "Usage:\n\n\t%s <command> [arguments]\n\nCommands:\n\n", prefix)
+	for _, cmd := range cmds {
+		fmt.Fprintf(a.stderr, "\t%-60s %s\n", cmd.usage, cmd.short)
+	}
+	fmt.Fprintln(a.stderr)
+}
+
+// flagSet returns a new flag set for the command which writes its usage to
+// stderr.
+func (a *app) flagSet(usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(usage, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: axiom-go %s\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses the flags and makes sure the expected number of positional
+// arguments is given. A negative max allows any number of arguments.
+func parseArgs(fs *flag.FlagSet, args []string, minArgs, maxArgs int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if n := fs.NArg(); n < minArgs || (maxArgs >= 0 && n > maxArgs) {
+		fs.Usage()
+		return nil, flag.ErrHelp
+	}
+	return fs.Args(), nil
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/axiomtest"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+func setup(t *testing.T) (*axiomtest.Server, func(args ...string) (string, error)) {
+	t.Helper()
+
+	srv := axiomtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	run := func(args ...string) (string, error) {
+		return runWithStdin(t, srv, "", args...)
+	}
+	return srv, run
+}
+
+func runWithStdin(t *testing.T, srv *axiomtest.Server, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	a := &app{
+		stdin:  strings.NewReader(stdin),
+		stdout: &stdout,
+		stderr: &stderr,
+		newClient: func() (*axiom.Client, error) {
+			return srv.Client()
+		},
+	}
+	err := a.run(t.Context(), args)
+	t.Log(stderr.String())
+	return stdout.String(), err
+}
+
+func TestApp_Usage(t *testing.T) {
+	_, run := setup(t)
+
+	_, err := run()
+	assert.ErrorIs(t, err, flag.ErrHelp)
+
+	_, err = run("unknown")
+	assert.EqualError(t, err, `unknown command "axiom-go unknown"`)
+
+	_, err = run("datasets", "unknown")
+	assert.EqualError(t, err, `unknown command "axiom-go datasets unknown"`)
+
+	_, err = run("datasets", "get")
+	assert.ErrorIs(t, err, flag.ErrHelp)
+}
+
+func TestApp_Datasets(t *testing.T) {
+	srv, run := setup(t)
+
+	out, err := run("datasets", "create", "-description", "Test dataset", "test")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"description": "Test dataset"`)
+
+	_, err = run("datasets", "create", "test")
+	assert.ErrorIs(t, err, axiom.ErrExists)
+
+	out, err = run("datasets", "list")
+	require.NoError(t, err)
+	assert.Regexp(t, `(?m)^NAME\s+KIND\s+DESCRIPTION\s+CREATED$`, out)
+	assert.Regexp(t, `(?m)^test\s+axiom:events:v1\s+Test dataset\s+`, out)
+
+	out, err = run("datasets", "list", "-format", "json")
+	require.NoError(t, err)
+	var datasets []*axiom.Dataset
+	require.NoError(t, json.Unmarshal([]byte(out), &datasets))
+	require.Len(t, datasets, 1)
+
+	_, err = run("datasets", "update", "-description", "Updated", "test")
+	require.NoError(t, err)
+
+	_, err = run("datasets", "trim", "test", "1h")
+	require.NoError(t, err)
+
+	_, err = run("datasets", "trim", "test", "forever")
+	assert.ErrorContains(t, err, "invalid max duration")
+
+	_, err = run("datasets", "delete", "test")
+	require.NoError(t, err)
+	assert.Empty(t, srv.Datasets())
+}
+
+func TestApp_Ingest(t *testing.T) {
+	srv, run := setup(t)
+	srv.CreateDataset("test")
+
+	// Stdin with detected content type.
+	_, err := runWithStdin(t, srv, `{"foo":"bar"}`+"\n"+`{"foo":"baz"}`, "ingest", "-label", "env=test", "test")
+	require.NoError(t, err)
+
+	// Gzip compressed CSV file.
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err = gw.Write([]byte("ts,foo\n2024-01-02T03:04:05Z,qux\n"))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	path := filepath.Join(t.TempDir(), "events.csv.gz")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+
+	_, err = run("ingest", "-timestamp-field", "ts", "test", path)
+	require.NoError(t, err)
+
+	events := srv.Events("test")
+	require.Len(t, events, 3)
+	assert.Equal(t, "bar", events[0]["foo"])
+	assert.Equal(t, "test", events[0]["env"])
+	assert.Equal(t, "baz", events[1]["foo"])
+	assert.Equal(t, "qux", events[2]["foo"])
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), events[2][ingest.TimestampField])
+
+	_, err = run("ingest", "-content-type", "xml", "test")
+	assert.EqualError(t, err, `unsupported content type "xml"`)
+}
+
+func TestApp_Query(t *testing.T) {
+	srv, run := setup(t)
+	srv.CreateDataset("test")
+
+	_, err := runWithStdin(t, srv, `[{"level":"info","n":1},{"level":"error","n":2},{"level":"info","n":3}]`, "ingest", "test")
+	require.NoError(t, err)
+
+	const apl = "['test'] | summarize sum(n) by level | order by level asc"
+
+	out, err := run("query", "-start", "1h", apl)
+	require.NoError(t, err)
+	assert.Equal(t, "level  sum_n\nerror  2\ninfo   4\n", out)
+
+	out, err = run("query", "-format", "csv", apl)
+	require.NoError(t, err)
+	assert.Equal(t, "level,sum_n\nerror,2\ninfo,4\n", out)
+
+	out, err = run("query", "-format", "json", apl)
+	require.NoError(t, err)
+	assert.Equal(t, `{"level":"error","sum_n":2}`+"\n"+`{"level":"info","sum_n":4}`+"\n", out)
+
+	_, err = run("query", "-format", "xml", apl)
+	assert.EqualError(t, err, `unsupported format "xml", must be one of table, json, csv`)
+
+	_, err = run("query", "-start", "yesterday", apl)
+	assert.ErrorContains(t, err, "must be an RFC 3339 timestamp or a duration")
+}
+
+func TestApp_Tail(t *testing.T) {
+	srv := axiomtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.CreateDataset("test")
+
+	client, err := srv.Client()
+	require.NoError(t, err)
+
+	_, err = client.IngestEvents(t.Context(), "test", []axiom.Event{
+		{"level": "info", "msg": "hello"},
+		{"level": "error", "msg": "oops"},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	a := &app{stdout: &stdout, stderr: &bytes.Buffer{}, newClient: func() (*axiom.Client, error) { return srv.Client() }}
+	require.NoError(t, a.run(ctx, []string{"tail", "-interval", "10ms", "-where", "level == 'error'", "test"}))
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1, "events must only be printed once")
+	assert.Contains(t, lines[0], `"msg":"oops"`)
+}
+
+func TestApp_Resources(t *testing.T) {
+	srv, run := setup(t)
+
+	dir := t.TempDir()
+	monitorPath := filepath.Join(dir, "monitor.json")
+	require.NoError(t, os.WriteFile(monitorPath, []byte(`{"name":"Errors","aplQuery":"['test'] | count","intervalMinutes":1,"rangeMinutes":5}`), 0o600))
+
+	out, err := run("monitors", "create", monitorPath)
+	require.NoError(t, err)
+	var monitor axiom.Monitor
+	require.NoError(t, json.Unmarshal([]byte(out), &monitor))
+	assert.Equal(t, time.Minute, monitor.Interval)
+
+	out, err = run("monitors", "list")
+	require.NoError(t, err)
+	assert.Regexp(t, `(?m)^`+monitor.ID+`\s+Errors\s+`, out)
+
+	_, err = runWithStdin(t, srv, `{"name":"Ops","properties":{"email":{"emails":["ops@example.com"]}}}`, "notifiers", "create", "-")
+	require.NoError(t, err)
+
+	out, err = run("notifiers", "list", "-format", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, "ops@example.com")
+
+	out, err = runWithStdin(t, srv, `{"name":"CI","datasetCapabilities":{"test":{"ingest":["create"]}}}`, "tokens", "create", "-")
+	require.NoError(t, err)
+	var token axiom.CreateTokenResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &token))
+	assert.NotEmpty(t, token.Token)
+
+	out, err = run("tokens", "regenerate", "-new-expires-in", "24h", token.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, out, token.Token)
+
+	_, err = run("monitors", "delete", monitor.ID)
+	require.NoError(t, err)
+
+	_, err = run("monitors", "get", monitor.ID)
+	assert.ErrorIs(t, err, axiom.ErrNotFound)
+
+	_, err = runWithStdin(t, srv, `{"unknown":true}`, "tokens", "create", "-")
+	assert.ErrorContains(t, err, `unknown field "unknown"`)
+}
+
+func TestApp_Reconcile(t *testing.T) {
+	_, run := setup(t)
+
+	path := filepath.Join(t.TempDir(), "state.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+virtualFields:
+  - dataset: test
+    name: status
+    expression: toint(status_code)
+`), 0o600))
+
+	out, err := run("reconcile", "-dry-run", path)
+	require.NoError(t, err)
+	assert.Equal(t, "+ create virtual field \"test/status\"\n\nPlan: 1 to create, 0 to update, 0 to delete.\n", out)
+
+	_, err = run("reconcile", path)
+	require.NoError(t, err)
+
+	out, err = run("reconcile", path)
+	require.NoError(t, err)
+	assert.Equal(t, "No changes.\n", out)
+
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	out, err = run("reconcile", "-datasets", "test", path)
+	require.NoError(t, err)
+	assert.Contains(t, out, `- delete virtual field "test/status"`)
+}
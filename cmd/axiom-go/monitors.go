@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+var monitorsCommands = []command{
+	{"list", "monitors list [-format table|json]", "List all monitors", runMonitorsList},
+	{"get", "monitors get <id>", "Get a monitor", runMonitorsGet},
+	{"create", "monitors create <file|->", "Create a monitor from a JSON definition", runMonitorsCreate},
+	{"update", "monitors update <id> <file|->", "Update a monitor from a JSON definition", runMonitorsUpdate},
+	{"delete", "monitors delete <id>", "Delete a monitor", runMonitorsDelete},
+}
+
+func runMonitors(ctx context.Context, a *app, args []string) error {
+	return a.dispatch(ctx, "axiom-go monitors", monitorsCommands, args)
+}
+
+func runMonitorsList(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("monitors list [flags]")
+	format := fs.String("format", formatTable, "output format (table, json)")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	} else if err = checkFormat(*format, formatTable, formatJSON); err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	monitors, err := client.Monitors.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *format == formatJSON {
+		return a.printJSON(monitors)
+	}
+	rows := make([][]any, len(monitors))
+	for i, monitor := range monitors {
+		rows[i] = []any{monitor.ID, monitor.Name, monitor.Type, monitor.Interval, monitor.Disabled}
+	}
+	return a.printTable([]string{"ID", "NAME", "TYPE", "INTERVAL", "DISABLED"}, rows)
+}
+
+func runMonitorsGet(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("monitors get <id>")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	monitor, err := client.Monitors.Get(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return a.printJSON(monitor)
+}
+
+func runMonitorsCreate(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("monitors create <file|->")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	var req axiom.MonitorCreateRequest
+	if err = a.readJSON(args[0], &req.Monitor); err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	monitor, err := client.Monitors.Create(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printJSON(monitor)
+}
+
+func runMonitorsUpdate(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("monitors update <id> <file|->")
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	var req axiom.MonitorUpdateRequest
+	if err = a.readJSON(args[1], &req.Monitor); err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	monitor, err := client.Monitors.Update(ctx, args[0], req)
+	if err != nil {
+		return err
+	}
+	return a.printJSON(monitor)
+}
+
+func runMonitorsDelete(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("monitors delete <id>")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+	return client.Monitors.Delete(ctx, args[0])
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+var notifiersCommands = []command{
+	{"list", "notifiers list [-format table|json]", "List all notifiers", runNotifiersList},
+	{"get", "notifiers get <id>", "Get a notifier", runNotifiersGet},
+	{"create", "notifiers create <file|->", "Create a notifier from a JSON definition", runNotifiersCreate},
+	{"update", "notifiers update <id> <file|->", "Update a notifier from a JSON definition", runNotifiersUpdate},
+	{"delete", "notifiers delete <id>", "Delete a notifier", runNotifiersDelete},
+}
+
+func runNotifiers(ctx context.Context, a *app, args []string) error {
+	return a.dispatch(ctx, "axiom-go notifiers", notifiersCommands, args)
+}
+
+func runNotifiersList(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("notifiers list [flags]")
+	format := fs.String("format", formatTable, "output format (table, json)")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	} else if err = checkFormat(*format, formatTable, formatJSON); err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	notifiers, err := client.Notifiers.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *format == formatJSON {
+		return a.printJSON(notifiers)
+	}
+	rows := make([][]any, len(notifiers))
+	for i, notifier := range notifiers {
+		rows[i] = []any{notifier.ID, notifier.Name, notifier.DisabledUntil}
+	}
+	return a.printTable([]string{"ID", "NAME", "DISABLED UNTIL"}, rows)
+}
+
+func runNotifiersGet(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("notifiers get <id>")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	notifier, err := client.Notifiers.Get(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return a.printJSON(notifier)
+}
+
+func runNotifiersCreate(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("notifiers create <file|->")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	var req axiom.Notifier
+	if err = a.readJSON(args[0], &req); err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	notifier, err := client.Notifiers.Create(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printJSON(notifier)
+}
+
+func runNotifiersUpdate(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("notifiers update <id> <file|->")
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	var req axiom.Notifier
+	if err = a.readJSON(args[1], &req); err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	notifier, err := client.Notifiers.Update(ctx, args[0], req)
+	if err != nil {
+		return err
+	}
+	return a.printJSON(notifier)
+}
+
+func runNotifiersDelete(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("notifiers delete <id>")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+	return client.Notifiers.Delete(ctx, args[0])
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/query"
+)
+
+// timeFlag is a flag which accepts either an RFC 3339 timestamp or a duration
+// relative to now, e.g. "1h" for one hour ago.
+type timeFlag struct {
+	t time.Time
+}
+
+func (f *timeFlag) String() string {
+	if f.t.IsZero() {
+		return ""
+	}
+	return f.t.Format(time.RFC3339)
+}
+
+func (f *timeFlag) Set(s string) error {
+	if d, err := time.ParseDuration(s); err == nil {
+		f.t = time.Now().Add(-d)
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("must be an RFC 3339 timestamp or a duration: %w", err)
+	}
+	f.t = t
+	return nil
+}
+
+func runQuery(ctx context.Context, a *app, args []string) error {
+	var (
+		fs         = a.flagSet("query [flags] <apl>")
+		format     = fs.String("format", formatTable, "output format (table, json, csv)")
+		start, end timeFlag
+	)
+	fs.Var(&start, "start", "start time as RFC 3339 timestamp or duration ago (e.g. 1h)")
+	fs.Var(&end, "end", "end time as RFC 3339 timestamp or duration ago (e.g. 5m)")
+	args, err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	} else if err = checkFormat(*format, formatTable, formatJSON, formatCSV); err != nil {
+		return err
+	}
+
+	var options []query.Option
+	if !start.t.IsZero() {
+		options = append(options, query.SetStartTime(start.t))
+	}
+	if !end.t.IsZero() {
+		options = append(options, query.SetEndTime(end.t))
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Query(ctx, strings.Join(args, " "), options...)
+	if err != nil {
+		return err
+	}
+	return a.printResult(res, *format)
+}
+
+func (a *app) printResult(res *query.Result, format string) error {
+	if len(res.Tables) == 0 {
+		return nil
+	}
+	table := res.Tables[0]
+
+	header := make([]string, len(table.Fields))
+	for i, field := range table.Fields {
+		header[i] = field.Name
+	}
+
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(a.stdout)
+		for row := range table.Rows() {
+			if err := enc.Encode(rowObject(header, row)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case formatCSV:
+		w := csv.NewWriter(a.stdout)
+		if err := w.Write(header); err != nil {
+			return err
+		}
+		for row := range table.Rows() {
+			record := make([]string, len(row))
+			for i, v := range row {
+				record[i] = formatValue(v)
+			}
+			if err := w.Write(record); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	var rows [][]any
+	for row := range table.Rows() {
+		rows = append(rows, row)
+	}
+	return a.printTable(header, rows)
+}
+
+func rowObject(header []string, row query.Row) map[string]any {
+	obj := make(map[string]any, len(header))
+	for i, name := range header {
+		obj[name] = row[i]
+	}
+	return obj
+}
+
+// quoteDataset returns the dataset name quoted for use in APL.
+func quoteDataset(name string) string {
+	return "['" + strings.ReplaceAll(name, "'", `\'`) + "']"
+}
+
+func runTail(ctx context.Context, a *app, args []string) error {
+	var (
+		fs       = a.flagSet("tail [flags] <dataset>")
+		interval = fs.Duration("interval", 2*time.Second, "interval to poll for new events")
+		since    = fs.Duration("since", time.Minute, "how far back to start")
+		where    = fs.String("where", "", "APL filter applied to the events (e.g. level == 'error')")
+	)
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	apl := quoteDataset(args[0])
+	if *where != "" {
+		apl += " | where " + *where
+	}
+	apl += " | sort by _time asc"
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+	return a.tail(ctx, client, apl, time.Now().Add(-*since), *interval)
+}
+
+// tail polls for events in consecutive, non-overlapping time windows and
+// prints them as NDJSON until the context is canceled.
+func (a *app) tail(ctx context.Context, client *axiom.Client, apl string, start time.Time, interval time.Duration) error {
+	enc := json.NewEncoder(a.stdout)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		end := time.Now()
+		res, err := client.Query(ctx, apl, query.SetStartTime(start), query.SetEndTime(end))
+		if ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		start = end
+
+		if len(res.Tables) > 0 {
+			table := res.Tables[0]
+			header := make([]string, len(table.Fields))
+			for i, field := range table.Fields {
+				header[i] = field.Name
+			}
+			for row := range table.Rows() {
+				if err = enc.Encode(rowObject(header, row)); err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
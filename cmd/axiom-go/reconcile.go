@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/axiomhq/axiom-go/axiom/reconcile"
+)
+
+func runReconcile(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("reconcile [flags] <file...>")
+	var (
+		owner    = fs.String("owner", "axiom-go", "owner of the managed resources")
+		datasets = fs.String("datasets", "", "comma-separated datasets to check for managed virtual fields, in addition to the ones in the files")
+		dryRun   = fs.Bool("dry-run", false, "print the plan without applying it")
+	)
+	args, err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	state, err := reconcile.LoadFiles(args...)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	var extraDatasets []string
+	if *datasets != "" {
+		extraDatasets = strings.Split(*datasets, ",")
+	}
+	r, err := reconcile.New(*owner, reconcile.SetClient(client, extraDatasets...))
+	if err != nil {
+		return err
+	}
+
+	plan, err := r.Plan(ctx, state)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(a.stdout, plan)
+
+	if *dryRun || plan.Empty() {
+		return nil
+	}
+	return r.Apply(ctx, plan)
+}
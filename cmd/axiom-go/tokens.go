@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+var tokensCommands = []command{
+	{"list", "tokens list [-format table|json]", "List all API tokens", runTokensList},
+	{"get", "tokens get <id>", "Get an API token", runTokensGet},
+	{"create", "tokens create <file|->", "Create an API token from a JSON definition", runTokensCreate},
+	{"regenerate", "tokens regenerate [flags] <id>", "Regenerate an API token", runTokensRegenerate},
+	{"delete", "tokens delete <id>", "Delete an API token", runTokensDelete},
+}
+
+func runTokens(ctx context.Context, a *app, args []string) error {
+	return a.dispatch(ctx, "axiom-go tokens", tokensCommands, args)
+}
+
+func runTokensList(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("tokens list [flags]")
+	format := fs.String("format", formatTable, "output format (table, json)")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	} else if err = checkFormat(*format, formatTable, formatJSON); err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	tokens, err := client.Tokens.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *format == formatJSON {
+		return a.printJSON(tokens)
+	}
+	rows := make([][]any, len(tokens))
+	for i, token := range tokens {
+		rows[i] = []any{token.ID, token.Name, token.Description, token.ExpiresAt}
+	}
+	return a.printTable([]string{"ID", "NAME", "DESCRIPTION", "EXPIRES"}, rows)
+}
+
+func runTokensGet(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("tokens get <id>")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	token, err := client.Tokens.Get(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return a.printJSON(token)
+}
+
+func runTokensCreate(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("tokens create <file|->")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	var req axiom.CreateTokenRequest
+	if err = a.readJSON(args[0], &req); err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	token, err := client.Tokens.Create(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printJSON(token)
+}
+
+func runTokensRegenerate(ctx context.Context, a *app, args []string) error {
+	var (
+		fs              = a.flagSet("tokens regenerate [flags] <id>")
+		existingExpires = fs.Duration("existing-expires-in", 0, "time until the existing token expires")
+		newExpires      = fs.Duration("new-expires-in", 0, "time until the new token expires, never if not set")
+	)
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	req := axiom.RegenerateTokenRequest{
+		ExistingTokenExpiresAt: now.Add(*existingExpires),
+	}
+	if *newExpires > 0 {
+		req.NewTokenExpiresAt = now.Add(*newExpires)
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+
+	token, err := client.Tokens.Regenerate(ctx, args[0], req)
+	if err != nil {
+		return err
+	}
+	return a.printJSON(token)
+}
+
+func runTokensDelete(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("tokens delete <id>")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.newClient()
+	if err != nil {
+		return err
+	}
+	return client.Tokens.Delete(ctx, args[0])
+}
@@ -0,0 +1,29 @@
+// The purpose of this example is to show how to integrate with hclog.
+package main
+
+import (
+	"log"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/hclog"
+)
+
+func main() {
+	// Export "AXIOM_DATASET" in addition to the required environment variables.
+
+	// 1. Setup the Axiom logger for hclog.
+	logger, err := adapter.New(adapter.SetName("example"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// 2. Have all logs flushed before the application exits.
+	//
+	// ❗THIS IS IMPORTANT❗ Without it, the logs will not be sent to Axiom as
+	// the buffer will not be flushed when the application exits.
+	defer logger.Close()
+
+	// 3. Log ⚡
+	logger.Info("This is awesome!", "mood", "hyped")
+	logger.Named("sub").Warn("This is not that awesome...", "mood", "worried")
+	logger.With("mood", "depressed").Error("This is rather bad.")
+}
@@ -0,0 +1,35 @@
+// The purpose of this example is to show how to integrate with logr.
+package main
+
+import (
+	"errors"
+	"log"
+
+	"github.com/go-logr/logr"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/logr"
+)
+
+func main() {
+	// Export "AXIOM_DATASET" in addition to the required environment variables.
+
+	// 1. Setup the Axiom sink for logr. Also ingest logs of V-level 1.
+	sink, err := adapter.New(adapter.SetVerbosity(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// 2. Have all logs flushed before the application exits.
+	//
+	// ❗THIS IS IMPORTANT❗ Without it, the logs will not be sent to Axiom as
+	// the buffer will not be flushed when the application exits.
+	defer sink.Close()
+
+	// 3. Create the logger.
+	logger := logr.New(sink).WithName("example")
+
+	// 4. Log ⚡
+	logger.Info("This is awesome!", "mood", "hyped")
+	logger.V(1).Info("This is not that awesome...", "mood", "worried")
+	logger.Error(errors.New("oops"), "This is rather bad.", "mood", "depressed")
+}
@@ -0,0 +1,60 @@
+// The purpose of this example is to show how to send OpenTelemetry metrics to
+// Axiom.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	axiotel "github.com/axiomhq/axiom-go/axiom/otel"
+)
+
+func main() {
+	// Export "AXIOM_DATASET" in addition to the required environment variables.
+	// The dataset must be of kind "otel:metrics:v1".
+
+	ctx := context.Background()
+
+	dataset := os.Getenv("AXIOM_DATASET")
+	if dataset == "" {
+		log.Fatal("AXIOM_DATASET is required")
+	}
+
+	// 1. Initialize OpenTelemetry.
+	stop, err := axiotel.InitMetrics(ctx, dataset, "axiom-otel-example", "v1.0.0")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if stopErr := stop(); stopErr != nil {
+			log.Fatal(stopErr)
+		}
+	}()
+
+	// 2. Instrument ⚡
+	meter := otel.Meter("main")
+
+	requests, err := meter.Int64Counter("requests")
+	if err != nil {
+		log.Fatal(err)
+	}
+	latency, err := meter.Float64Histogram("latency", metric.WithUnit("ms"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for range 3 {
+		start := time.Now()
+		time.Sleep(time.Millisecond * 100)
+
+		attrs := metric.WithAttributes(attribute.Key("route").String("/"))
+		requests.Add(ctx, 1, attrs)
+		latency.Record(ctx, float64(time.Since(start).Milliseconds()), attrs)
+	}
+}
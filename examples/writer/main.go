@@ -0,0 +1,41 @@
+// The purpose of this example is to show how to ship the plain text output of
+// a subprocess to Axiom.
+package main
+
+import (
+	"log"
+	"os/exec"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/writer"
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+func main() {
+	// Export "AXIOM_DATASET" in addition to the required environment variables.
+
+	// 1. Setup the Axiom writer.
+	w, err := adapter.New(
+		adapter.SetFields(axiom.Event{"command": "ls"}),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// 2. Have all lines flushed before the application exits.
+	//
+	// ❗THIS IS IMPORTANT❗ Without it, the lines will not be sent to Axiom as
+	// the buffer will not be flushed when the application exits.
+	defer func() {
+		if closeErr := w.Close(); closeErr != nil {
+			log.Fatal(closeErr)
+		}
+	}()
+
+	// 3. Run the subprocess with its output written to Axiom ⚡
+	cmd := exec.Command("ls", "-l")
+	cmd.Stdout = w.With(axiom.Event{"stream": "stdout"})
+	cmd.Stderr = w.With(axiom.Event{"stream": "stderr"})
+	if err := cmd.Run(); err != nil {
+		log.Print(err)
+	}
+}
@@ -0,0 +1,324 @@
+// Command mockgen generates mock implementations of the interfaces declared in
+// a Go source file. The mocks are based on [mock.Mock] and are used to
+// generate the axiommock package.
+//
+// [mock.Mock]: https://pkg.go.dev/github.com/stretchr/testify/mock#Mock
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	source     = flag.String("source", "", "Go source file declaring the interfaces")
+	output     = flag.String("output", "", "output file, defaults to stdout")
+	pkgName    = flag.String("package", "", "package name of the generated file")
+	importPath = flag.String("import", "", "import path of the source package")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("mockgen: ")
+
+	flag.Parse()
+	if *source == "" || *pkgName == "" || *importPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, *source, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+
+	g := &generator{
+		fset:       fset,
+		srcPkg:     file.Name.Name,
+		interfaces: make(map[string]*ast.InterfaceType),
+		imports:    make(map[string]string),
+		used:       map[string]string{"mock": "github.com/stretchr/testify/mock"},
+	}
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		g.imports[name] = path
+	}
+	g.used[g.srcPkg] = *importPath
+
+	var names []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		if iface, ok := spec.Type.(*ast.InterfaceType); ok && spec.Name.IsExported() {
+			g.interfaces[spec.Name.Name] = iface
+			names = append(names, spec.Name.Name)
+		}
+		return false
+	})
+	if len(names) == 0 {
+		return fmt.Errorf("no interfaces found in %s", *source)
+	}
+
+	var body bytes.Buffer
+	for _, name := range names {
+		if err = g.generate(&body, name); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by mockgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\nimport (", *pkgName)
+	group := -1
+	for _, name := range sortedKeys(g.used) {
+		if group != importGroup(g.used[name]) {
+			group = importGroup(g.used[name])
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "\t%q\n", g.used[name])
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+	fmt.Fprintf(&buf, "var (\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "\t_ %s.%s = (*%s)(nil)\n", g.srcPkg, name, name)
+	}
+	fmt.Fprintf(&buf, ")\n")
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated code: %w\n%s", err, buf.Bytes())
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0o644) //nolint:gosec // Generated source files are meant to be world-readable.
+}
+
+type generator struct {
+	fset       *token.FileSet
+	srcPkg     string
+	interfaces map[string]*ast.InterfaceType
+	imports    map[string]string // Imports of the source file by name.
+	used       map[string]string // Imports used by the generated code by name.
+}
+
+func (g *generator) generate(w *bytes.Buffer, name string) error {
+	methods, err := g.methods(name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n// %s is a mock implementation of [%s.%s].\n", name, g.srcPkg, name)
+	fmt.Fprintf(w, "type %s struct {\n\tmock.Mock\n}\n", name)
+
+	fmt.Fprintf(w, "\n// New%s creates a new [%s] and registers a cleanup function that\n", name, name)
+	fmt.Fprintf(w, "// asserts that all expectations were met.\n")
+	fmt.Fprintf(w, "func New%s(t interface {\n\tmock.TestingT\n\tCleanup(func())\n}) *%s {\n", name, name)
+	fmt.Fprintf(w, "\tm := new(%s)\n\tm.Test(t)\n\tt.Cleanup(func() { m.AssertExpectations(t) })\n\treturn m\n}\n", name)
+
+	for _, method := range methods {
+		if err = g.method(w, name, method); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// methods returns the methods of the named interface, including the ones of
+// embedded interfaces declared in the same file.
+func (g *generator) methods(name string) ([]*ast.Field, error) {
+	iface, ok := g.interfaces[name]
+	if !ok {
+		return nil, fmt.Errorf("embedded interface %q not declared in %s", name, *source)
+	}
+
+	var methods []*ast.Field
+	for _, field := range iface.Methods.List {
+		if len(field.Names) > 0 {
+			methods = append(methods, field)
+			continue
+		}
+		ident, ok := field.Type.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("unsupported embedded type in %q", name)
+		}
+		embedded, err := g.methods(ident.Name)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, embedded...)
+	}
+	return methods, nil
+}
+
+func (g *generator) method(w *bytes.Buffer, recv string, method *ast.Field) error {
+	var (
+		name = method.Names[0].Name
+		fn   = method.Type.(*ast.FuncType)
+
+		params, args []string
+		results      []string
+	)
+	for i, field := range fn.Params.List {
+		typ, err := g.typeString(field.Type)
+		if err != nil {
+			return err
+		}
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("arg%d", i))}
+		}
+		for _, n := range names {
+			params = append(params, n.Name+" "+typ)
+			args = append(args, n.Name)
+		}
+	}
+	if fn.Results != nil {
+		for _, field := range fn.Results.List {
+			typ, err := g.typeString(field.Type)
+			if err != nil {
+				return err
+			}
+			for range max(len(field.Names), 1) {
+				results = append(results, typ)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "\n// %s implements [%s.%s].\n", name, g.srcPkg, recv)
+	fmt.Fprintf(w, "func (m *%s) %s(%s) ", recv, name, strings.Join(params, ", "))
+	switch len(results) {
+	case 0:
+		fmt.Fprintf(w, "{\n\tm.Called(%s)\n}\n", strings.Join(args, ", "))
+		return nil
+	case 1:
+		fmt.Fprintf(w, "%s {\n", results[0])
+	default:
+		fmt.Fprintf(w, "(%s) {\n", strings.Join(results, ", "))
+	}
+
+	fmt.Fprintf(w, "\tret := m.Called(%s)\n\n", strings.Join(args, ", "))
+	rets := make([]string, len(results))
+	for i, typ := range results {
+		rets[i] = fmt.Sprintf("r%d", i)
+		if typ == "error" {
+			fmt.Fprintf(w, "\tr%d := ret.Error(%d)\n", i, i)
+			continue
+		}
+		fmt.Fprintf(w, "\tvar r%d %s\n\tif v := ret.Get(%d); v != nil {\n\t\tr%d = v.(%s)\n\t}\n", i, typ, i, i, typ)
+	}
+	fmt.Fprintf(w, "\n\treturn %s\n}\n", strings.Join(rets, ", "))
+	return nil
+}
+
+// typeString returns the source representation of the type expression,
+// qualifying identifiers declared in the source package and recording the
+// imports it needs.
+func (g *generator) typeString(expr ast.Expr) (string, error) {
+	var err error
+	expr = qualify(expr, g.srcPkg)
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg := sel.X.(*ast.Ident).Name
+		if pkg == g.srcPkg {
+			return false
+		}
+		path, ok := g.imports[pkg]
+		if !ok {
+			err = fmt.Errorf("unknown package %q", pkg)
+			return false
+		}
+		g.used[pkg] = path
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err = printer.Fprint(&buf, g.fset, expr); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// qualify returns a copy of the type expression with all exported identifiers
+// that are not already qualified prefixed with the given package name.
+func qualify(expr ast.Expr, pkg string) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if e.IsExported() {
+			return &ast.SelectorExpr{X: ast.NewIdent(pkg), Sel: ast.NewIdent(e.Name)}
+		}
+		return ast.NewIdent(e.Name)
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X, pkg)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt, pkg)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key, pkg), Value: qualify(e.Value, pkg)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: e.Dir, Value: qualify(e.Value, pkg)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(e.Elt, pkg)}
+	}
+	return expr
+}
+
+// importGroup returns the group an import path is sorted into: the standard
+// library first, then third party packages and packages of this module last.
+func importGroup(path string) int {
+	switch {
+	case !strings.Contains(strings.Split(path, "/")[0], "."):
+		return 0
+	case strings.HasPrefix(path, "github.com/axiomhq/axiom-go"):
+		return 2
+	}
+	return 1
+}
+
+// sortedKeys returns the import names sorted by import group and path.
+func sortedKeys(imports map[string]string) []string {
+	keys := make([]string, 0, len(imports))
+	for k := range imports {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(importGroup(imports[a]), importGroup(imports[b])),
+			strings.Compare(imports[a], imports[b]),
+		)
+	})
+	return keys
+}
@@ -196,24 +196,76 @@ func (c *Config) IncorporateEnvironment() error {
 		addOption(SetEdge(envEdge))
 	}
 
-	return c.Options(options...)
+	// The environment is incorporated as a whole, so its edge settings
+	// replace the ones of the configuration.
+	var env Config
+	if err := env.Options(options...); err != nil {
+		return err
+	}
+	c.Incorporate(env)
+
+	return nil
+}
+
+// Incorporate overwrites the values of the configuration with the ones that
+// are set in the given configuration. The edge URL and the edge domain are
+// treated as one setting: if either is set in the given configuration, both
+// are taken from it, so an edge domain can override an edge URL.
+func (c *Config) Incorporate(other Config) {
+	if other.baseURL != nil {
+		c.baseURL = other.baseURL
+	}
+	if other.token != "" {
+		c.token = other.token
+	}
+	if other.organizationID != "" {
+		c.organizationID = other.organizationID
+	}
+	if other.IsEdgeConfigured() {
+		c.edgeURL, c.edge = other.edgeURL, other.edge
+	}
 }
 
 // Validate the configuration.
 func (c Config) Validate() error {
+	if err := c.ValidateEndpoints(); err != nil {
+		return err
+	}
+	return c.ValidateCredentials(c.token, c.organizationID)
+}
+
+// ValidateEndpoints validates the base URL and the edge configuration, which
+// is everything but the credentials.
+func (c Config) ValidateEndpoints() error {
+	for _, u := range []*url.URL{c.baseURL, c.edgeURL} {
+		if u != nil && (u.Scheme == "" || u.Host == "") {
+			return ErrInvalidURL
+		}
+	}
+	if strings.ContainsAny(c.edge, "/ ") {
+		return ErrInvalidEdge
+	}
+	return nil
+}
+
+// ValidateCredentials validates the given token and organization ID for use
+// with the configuration, e.g. when they are not part of the configuration but
+// provided otherwise.
+func (c Config) ValidateCredentials(token, organizationID string) error {
 	// Failsafe to protect against an empty baseURL.
-	if c.baseURL == nil {
-		c.baseURL = apiURL
+	baseURL := c.baseURL
+	if baseURL == nil {
+		baseURL = apiURL
 	}
 
-	if c.token == "" {
+	if token == "" {
 		return ErrMissingToken
-	} else if !IsValidToken(c.token) {
+	} else if !IsValidToken(token) {
 		return ErrInvalidToken
 	}
 
 	// The organization ID is not required for API tokens.
-	if c.organizationID == "" && IsPersonalToken(c.token) && c.baseURL.String() == apiURL.String() {
+	if organizationID == "" && IsPersonalToken(token) && baseURL.String() == apiURL.String() {
 		return ErrMissingOrganizationID
 	}
 
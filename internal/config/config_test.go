@@ -85,6 +85,18 @@ func TestConfig_IncorporateEnvironment(t *testing.T) {
 				edge:    "eu-central-1.aws.edge.axiom.co",
 			},
 		},
+		{
+			name: "edge domain environment; edge url preset",
+			baseConfig: Config{
+				edgeURL: mustParseURL(t, "https://edge.example.com"),
+			},
+			environment: map[string]string{
+				"AXIOM_EDGE": "eu-central-1.aws.edge.axiom.co",
+			},
+			want: Config{
+				edge: "eu-central-1.aws.edge.axiom.co",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -124,6 +136,30 @@ func TestConfig_Validate(t *testing.T) {
 				organizationID: organizationID,
 			},
 		},
+		{
+			name: "relative base url",
+			config: Config{
+				baseURL: &url.URL{Path: "/api"},
+				token:   apiToken,
+			},
+			expErr: ErrInvalidURL,
+		},
+		{
+			name: "relative edge url",
+			config: Config{
+				token:   apiToken,
+				edgeURL: &url.URL{Path: "/v1/ingest"},
+			},
+			expErr: ErrInvalidURL,
+		},
+		{
+			name: "edge with scheme",
+			config: Config{
+				token: apiToken,
+				edge:  "https://eu-central-1.aws.edge.axiom.co",
+			},
+			expErr: ErrInvalidEdge,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
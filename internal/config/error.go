@@ -14,6 +14,13 @@ var ErrMissingOrganizationID = errors.New("missing organization id")
 // ErrInvalidToken is returned when the token is invalid.
 var ErrInvalidToken = errors.New("invalid token")
 
+// ErrInvalidURL is returned when the base URL or the edge URL is not an
+// absolute URL.
+var ErrInvalidURL = errors.New("invalid url, must include scheme and host")
+
+// ErrInvalidEdge is returned when the edge is not a domain.
+var ErrInvalidEdge = errors.New("invalid edge, must be a domain only")
+
 // ErrPersonalTokenNotSupportedForEdge is returned when a personal token is
 // used for edge operations. Edge endpoints only support API tokens.
 var ErrPersonalTokenNotSupportedForEdge = errors.New("personal tokens are not supported for edge operations, use an API token (xaat-)")
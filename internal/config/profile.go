@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ProfileFileName is the name of the profile file in the home directory of the
+// user. It is shared with the official Axiom CLI.
+const ProfileFileName = ".axiom.toml"
+
+// ErrProfileNotFound is returned when the selected profile does not exist in
+// the profile file.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// profileFile is the format of the profile file. It is compatible with the
+// configuration file of the official Axiom CLI:
+//
+//	active_deployment = "production"
+//
+//	[deployments.production]
+//	url = "https://api.axiom.co"
+//	token = "xapt-..."
+//	org_id = "my-org"
+type profileFile struct {
+	ActiveDeployment string             `toml:"active_deployment"`
+	Deployments      map[string]profile `toml:"deployments"`
+}
+
+type profile struct {
+	URL            string `toml:"url"`
+	Token          string `toml:"token"`
+	OrganizationID string `toml:"org_id"`
+	EdgeURL        string `toml:"edge_url"`
+	Edge           string `toml:"edge"`
+}
+
+// DefaultProfileFile returns the path of the profile file in the home
+// directory of the user.
+func DefaultProfileFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ProfileFileName), nil
+}
+
+// IncorporateProfile loads configuration from the named profile (called
+// deployment by the Axiom CLI) in the TOML profile file at the given path. If
+// no name is given, the active deployment of the file is used. It will reject
+// invalid values.
+func (c *Config) IncorporateProfile(path, name string) error {
+	var f profileFile
+	if _, err := toml.DecodeFile(path, &f); err != nil {
+		return fmt.Errorf("read profile file: %w", err)
+	}
+
+	if name == "" {
+		name = f.ActiveDeployment
+	}
+	p, ok := f.Deployments[name]
+	if !ok {
+		return fmt.Errorf("%w: %q in %s", ErrProfileNotFound, name, path)
+	}
+
+	options := make([]Option, 0, 5)
+	if p.URL != "" {
+		options = append(options, SetURL(p.URL))
+	}
+	if p.Token != "" {
+		options = append(options, SetToken(p.Token))
+	}
+	if p.OrganizationID != "" {
+		options = append(options, SetOrganizationID(p.OrganizationID))
+	}
+	if p.EdgeURL != "" {
+		options = append(options, SetEdgeURL(p.EdgeURL))
+	}
+	if p.Edge != "" {
+		options = append(options, SetEdge(p.Edge))
+	}
+
+	if err := c.Options(options...); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profileFileContent = `
+active_deployment = "production"
+
+[deployments.production]
+url = "https://api.axiom.co"
+token = "` + personalToken + `"
+org_id = "` + organizationID + `"
+
+[deployments.staging]
+url = "` + endpoint + `"
+token = "` + apiToken + `"
+edge = "eu-central-1.aws.edge.axiom.co"
+
+[deployments.invalid]
+token = "` + unspecifiedToken + `"
+`
+
+func TestConfig_IncorporateProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ProfileFileName)
+	require.NoError(t, os.WriteFile(path, []byte(profileFileContent), 0o600))
+
+	tests := []struct {
+		name    string
+		path    string
+		profile string
+		want    Config
+		wantErr error
+	}{
+		{
+			name: "active deployment",
+			path: path,
+			want: Config{
+				baseURL:        mustParseURL(t, "https://api.axiom.co"),
+				token:          personalToken,
+				organizationID: organizationID,
+			},
+		},
+		{
+			name:    "named deployment",
+			path:    path,
+			profile: "staging",
+			want: Config{
+				baseURL: mustParseURL(t, endpoint),
+				token:   apiToken,
+				edge:    "eu-central-1.aws.edge.axiom.co",
+			},
+		},
+		{
+			name:    "unknown deployment",
+			path:    path,
+			profile: "development",
+			wantErr: ErrProfileNotFound,
+		},
+		{
+			name:    "invalid deployment",
+			path:    path,
+			profile: "invalid",
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "missing file",
+			path:    filepath.Join(t.TempDir(), ProfileFileName),
+			wantErr: os.ErrNotExist,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Config
+			err := c.IncorporateProfile(tt.path, tt.profile)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c)
+		})
+	}
+}
+
+func TestConfig_Incorporate(t *testing.T) {
+	c := Config{
+		baseURL:        mustParseURL(t, "https://api.axiom.co"),
+		token:          personalToken,
+		organizationID: organizationID,
+	}
+	c.Incorporate(Config{
+		baseURL: mustParseURL(t, endpoint),
+		token:   apiToken,
+	})
+
+	assert.Equal(t, Config{
+		baseURL:        mustParseURL(t, endpoint),
+		token:          apiToken,
+		organizationID: organizationID,
+	}, c)
+
+	// The edge URL and the edge domain replace each other.
+	c.Incorporate(Config{edgeURL: mustParseURL(t, "https://edge.example.com")})
+	c.Incorporate(Config{edge: "eu-central-1.aws.edge.axiom.co"})
+	assert.Nil(t, c.EdgeURL())
+	assert.Equal(t, "eu-central-1.aws.edge.axiom.co", c.Edge())
+
+	c.Incorporate(Config{edgeURL: mustParseURL(t, "https://edge.example.com")})
+	assert.Equal(t, "https://edge.example.com", c.EdgeURL().String())
+	assert.Empty(t, c.Edge())
+}
@@ -0,0 +1,4 @@
+// Package ingester provides a buffered ingester that ships events to Axiom in
+// the background. It is shared by the packages that integrate Axiom into other
+// libraries and only need to hand off single events.
+package ingester
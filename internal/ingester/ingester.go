@@ -0,0 +1,109 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+// DefaultBatchSize is the default capacity of the event buffer.
+const DefaultBatchSize = 10_000
+
+// ErrClosed is returned by [Ingester.Ingest] when the ingester is closed.
+var ErrClosed = errors.New("ingester closed")
+
+// Ingester buffers events and ingests them into a dataset in the background
+// using [axiom.DatasetsService.IngestChannel].
+type Ingester struct {
+	client        *axiom.Client
+	datasetName   string
+	ingestOptions []ingest.Option
+	logger        *log.Logger
+
+	eventCh   chan axiom.Event
+	stopCh    chan struct{}
+	closeCh   chan struct{}
+	closeOnce sync.Once
+
+	// mu guards closed and makes sure no event is sent on the closed event
+	// channel.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// New creates a new ingester that ingests events into the given dataset and
+// starts ingesting in the background. Failures are logged to stderr using the
+// given log prefix (e.g. "[AXIOM|LOGRUS]").
+//
+// An ingester needs to be closed properly to make sure all events are sent by
+// calling [Ingester.Close].
+func New(client *axiom.Client, datasetName, logPrefix string, ingestOptions ...ingest.Option) *Ingester {
+	i := &Ingester{
+		client:        client,
+		datasetName:   datasetName,
+		ingestOptions: ingestOptions,
+		logger:        log.New(os.Stderr, logPrefix, 0),
+
+		eventCh: make(chan axiom.Event, DefaultBatchSize),
+		stopCh:  make(chan struct{}),
+		closeCh: make(chan struct{}),
+	}
+
+	go i.run()
+
+	return i
+}
+
+func (i *Ingester) run() {
+	defer close(i.closeCh)
+
+	for {
+		if res, err := i.client.IngestChannel(context.Background(), i.datasetName, i.eventCh, i.ingestOptions...); err != nil {
+			i.logger.Printf("failed to ingest events: %s\n", err)
+		} else if res.Failed > 0 {
+			// Best effort on notifying the user about the ingest failure.
+			i.logger.Printf("event at %s failed to ingest: %s\n",
+				res.Failures[0].Timestamp, res.Failures[0].Error)
+		}
+
+		select {
+		case <-i.stopCh:
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Ingest hands the event off to the background ingestion. It blocks if the
+// buffer is full.
+func (i *Ingester) Ingest(event axiom.Event) error {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.closed {
+		return ErrClosed
+	}
+	i.eventCh <- event
+
+	return nil
+}
+
+// Close the ingester and make sure all events are flushed. Closing the
+// ingester renders it unusable for further use.
+func (i *Ingester) Close() {
+	i.closeOnce.Do(func() {
+		i.mu.Lock()
+		i.closed = true
+		close(i.stopCh)
+		close(i.eventCh)
+		i.mu.Unlock()
+
+		<-i.closeCh
+	})
+}